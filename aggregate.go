@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+
+	"github.com/ananthanir/hello-zkp/challenge"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runAggregate proves every row of an input file on the inner curve, folds
+// all the proofs into one outer proof on the outer curve and verifies the
+// batch with that single proof.
+func runAggregate(args []string) error {
+	fs := flag.NewFlagSet("aggregate", flag.ExitOnError)
+	input := fs.String("input", "witnesses.jsonl", "JSONL ({\"age\":..,\"min\":..,\"max\":..} per line) or .csv (age,min,max) file")
+	size := fs.Int("size", 0, "proof slots of the aggregate circuit (0: one per row); fewer rows are padded")
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	cache := fs.String("cache", defaultCacheDir(), "directory caching keys per circuit (empty: always run setup)")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	rows, err := readBatchRows(*input)
+	if err != nil {
+		return err
+	}
+	if *size == 0 {
+		*size = len(rows)
+	}
+
+	// -----------------------------
+	// 1) One inner proof per row on BLS12-377
+	// -----------------------------
+	inner, err := circuit.NewRangeCircuit(*bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	start := time.Now()
+	innerCcs, err := prover.Compile(circuit.InnerCurve, inner)
+	if err != nil {
+		return err
+	}
+	innerPk, innerVk, err := setupKeys(innerCcs, *cache)
+	if err != nil {
+		return err
+	}
+	proofs := make([]groth16.Proof, len(rows))
+	nonces := make([]*big.Int, len(rows))
+	for i, row := range rows {
+		assignment, err := row.Build(inner, nil)
+		if err != nil {
+			return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("row %d: %w", i+1, err))
+		}
+		if nonces[i], err = challenge.New(); err != nil {
+			return err
+		}
+		full, _, err := prover.NewWitness(circuit.InnerCurve, assignment.WithChallenge(nonces[i]))
+		if err != nil {
+			return err
+		}
+		proofs[i], err = prover.Prove(innerCcs, innerPk, full,
+			stdgroth16.GetNativeProverOptions(circuit.OuterCurve.ScalarField(), circuit.InnerCurve.ScalarField()))
+		if err != nil {
+			return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("row %d: %w", i+1, err))
+		}
+	}
+	report.since("inner_ns", start)
+	report.printf("Inner proofs: %d of %s on %s in %v\n", len(rows), inner.ID(), circuit.InnerCurve, time.Since(start).Round(time.Millisecond))
+
+	// The verifier's side: the statements it expects, one per row.
+	statements := make([]witness.Witness, len(rows))
+	for i, row := range rows {
+		expected, err := inner.PublicAssignment(row.Min, row.Max, nonces[i])
+		if err != nil {
+			return zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		if statements[i], err = prover.NewPublicWitness(circuit.InnerCurve, expected); err != nil {
+			return err
+		}
+	}
+
+	// -----------------------------
+	// 2) Aggregate circuit on BW6-761
+	// -----------------------------
+	outer, err := circuit.NewAggregateRangeCircuit(inner, innerCcs, innerVk, *size)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	outerAssignment, err := outer.Assign(proofs, statements)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	start = time.Now()
+	outerCcs, err := prover.Compile(circuit.OuterCurve, outer)
+	if err != nil {
+		return err
+	}
+	report.since("compile_ns", start)
+	report.printf("Aggregate circuit: %s on %s, %d constraints\n", outer.ID(), circuit.OuterCurve, outerCcs.GetNbConstraints())
+	report.set("circuit", outer.ID())
+	report.set("curve", circuit.OuterCurve.String())
+	report.set("constraints", outerCcs.GetNbConstraints())
+	report.set("proofs", len(rows))
+
+	start = time.Now()
+	outerPk, outerVk, err := setupKeys(outerCcs, *cache)
+	if err != nil {
+		return err
+	}
+	report.since("setup_ns", start)
+
+	// -----------------------------
+	// 3) One outer proof for the whole batch
+	// -----------------------------
+	outerWitness, _, err := prover.NewWitness(circuit.OuterCurve, outerAssignment)
+	if err != nil {
+		return err
+	}
+	start = time.Now()
+	outerProof, err := prover.Prove(outerCcs, outerPk, outerWitness)
+	if err != nil {
+		report.println("Aggregate proof: ❌ FAILED (an inner proof does not verify)")
+		return err
+	}
+	report.since("prove_ns", start)
+
+	// -----------------------------
+	// 4) A single verification covers every statement
+	// -----------------------------
+	expected, err := outer.PublicAssignment(statements)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	publicWitness, err := prover.NewPublicWitness(circuit.OuterCurve, expected)
+	if err != nil {
+		return err
+	}
+	start = time.Now()
+	err = zkp.Verify(outerProof, outerVk, publicWitness)
+	report.since("verify_ns", start)
+	if err == nil {
+		report.printf("Aggregate proof checks %d statements in %v\n", len(rows), time.Since(start).Round(time.Millisecond))
+	}
+	return reportVerification(err)
+}
@@ -0,0 +1,209 @@
+// Package audit keeps an append-only log of the proofs a service issued and
+// verified, so that its operator can settle a dispute ("this proof was
+// never accepted", "we never issued that") from a record made at the time.
+//
+// The log is a JSON Lines file, one Record per line. Every record carries
+// the SHA-256 of the line before it, so a record edited, removed or
+// reordered after the fact breaks the chain, which Read reports; records
+// cut from the end are caught by comparing the head hash with a copy kept
+// elsewhere. Records hold public data only: circuit, keys, public inputs
+// and the hash of the proof, never a private input. One process appends to
+// a log at a time.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+)
+
+// Events recorded.
+const (
+	Proved   = "prove"
+	Verified = "verify"
+)
+
+// Genesis is the Prev of the first record of a log.
+const Genesis = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// ErrTampered is returned by Read when the chain of records is broken.
+var ErrTampered = errors.New("audit log does not chain")
+
+// Record is one proof issued or verified.
+type Record struct {
+	Seq   int       `json:"seq"`
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+
+	Circuit   string `json:"circuit"`
+	Curve     string `json:"curve"`
+	VKHash    string `json:"vk_hash"`
+	ProofHash string `json:"proof_hash,omitempty"`
+
+	// PublicInputs are the inputs the proof carries, by name.
+	PublicInputs []circuit.PublicInput `json:"public_inputs,omitempty"`
+
+	// Pinned is set when the verifier checked the proof against a statement
+	// of its own rather than the public inputs the proof carries.
+	Pinned bool `json:"pinned,omitempty"`
+
+	// OK is whether the proof was issued, or verified. Error says why not.
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	// Peer is the remote address of a service request.
+	Peer string `json:"peer,omitempty"`
+
+	// Prev is the hex SHA-256 of the previous line, or Genesis.
+	Prev string `json:"prev"`
+}
+
+// NewRecord describes an event on an envelope, with the public inputs of
+// public named after definition when both are known. A nil envelope, one
+// that could not be read say, leaves the proof fields empty. err is the
+// outcome.
+func NewRecord(event string, definition circuit.Definition, env *envelope.Envelope, public witness.Witness, err error) Record {
+	r := Record{Event: event, OK: err == nil}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if definition != nil {
+		r.Circuit = definition.ID()
+	}
+	if env != nil {
+		if r.Circuit == "" {
+			r.Circuit = env.Circuit
+		}
+		r.Curve, r.VKHash, r.ProofHash = env.Curve, env.VKHash, ProofHash(env)
+	}
+	if definition != nil && public != nil {
+		if inputs, err := circuit.ReadPublicInputs(definition, public); err == nil {
+			r.PublicInputs = inputs.Inputs
+		}
+	}
+	return r
+}
+
+// ProofHash returns the hex SHA-256 of the proof in an envelope, which
+// names the proof in the log.
+func ProofHash(env *envelope.Envelope) string {
+	sum := sha256.Sum256(env.Proof)
+	return hex.EncodeToString(sum[:])
+}
+
+// Log appends records to a file.
+type Log struct {
+	mu   sync.Mutex
+	f    *os.File
+	seq  int
+	prev string
+}
+
+// Open opens the log at path for appending, creating it with mode 0600 if
+// needed, and continues the chain from its last record.
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{f: f, prev: Genesis}
+	err = scan(f, func(line []byte, r Record) error {
+		l.seq, l.prev = r.Seq, digest(line)
+		return nil
+	})
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Append stamps r with the next sequence number, the time if unset and the
+// hash of the previous record, and writes it to disk before returning.
+func (l *Log) Append(r Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r.Seq, r.Prev = l.seq+1, l.prev
+	if r.Time.IsZero() {
+		r.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := l.f.Sync(); err != nil {
+		return err
+	}
+	l.seq, l.prev = r.Seq, digest(line)
+	return nil
+}
+
+// Close closes the file.
+func (l *Log) Close() error {
+	return l.f.Close()
+}
+
+// Read returns the records of the log at path and its head, the hash of the
+// last record. If the chain is broken it returns the records before the
+// break and an error wrapping ErrTampered.
+func Read(path string) (records []Record, head string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+	head = Genesis
+	err = scan(f, func(line []byte, r Record) error {
+		if r.Seq != len(records)+1 || r.Prev != head {
+			return fmt.Errorf("%w: record %d does not follow record %d (either was altered, or records between them removed)", ErrTampered, r.Seq, len(records))
+		}
+		records = append(records, r)
+		head = digest(line)
+		return nil
+	})
+	if err != nil {
+		return records, head, fmt.Errorf("%s: %w", path, err)
+	}
+	return records, head, nil
+}
+
+// scan calls f with every line of r and the record it holds.
+func scan(r io.Reader, f func(line []byte, r Record) error) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for n := 1; s.Scan(); n++ {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrTampered, n, err)
+		}
+		if err := f(line, rec); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+func digest(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/audit"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+const auditUsage = `usage: hello-zkp audit <step> [flags]
+
+steps:
+  list  list the proofs issued and verified, and check the log's chain
+  show  print every record of one proof, by sequence number or proof hash
+`
+
+// auditSteps maps each audit step to its implementation.
+var auditSteps = map[string]func(args []string) error{
+	"list": runAuditList,
+	"show": runAuditShow,
+}
+
+// runAudit dispatches to a step of reading an audit log, as written by
+// prove, verify and cmd/prover with -audit-log.
+func runAudit(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, auditUsage)
+		os.Exit(exitUsage)
+	}
+	run, ok := auditSteps[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown audit step %q\n\n%s", args[0], auditUsage)
+		os.Exit(exitUsage)
+	}
+	return run(args[1:])
+}
+
+// addAuditFlag registers -audit-log on fs.
+func addAuditFlag(fs *flag.FlagSet) *string {
+	return fs.String("audit-log", "", "append a record of the proof to this audit log (JSON Lines; empty: none)")
+}
+
+// openAudit opens the audit log at path, or returns nil for an empty path.
+func openAudit(path string) (*audit.Log, error) {
+	if path == "" {
+		return nil, nil
+	}
+	l, err := audit.Open(path)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	return l, nil
+}
+
+// recordAudit appends the outcome err of event on env to l, if not nil, and
+// returns err, or the failure to record it: an operation that must be
+// audited fails if it cannot be. Commands that fail before naming a circuit
+// or reading an envelope leave nothing to record.
+func recordAudit(l *audit.Log, event string, definition circuit.Definition, env *envelope.Envelope, public witness.Witness, pinned bool, err error) error {
+	if l == nil {
+		return err
+	}
+	defer l.Close()
+	if definition == nil && env == nil {
+		return err
+	}
+	r := audit.NewRecord(event, definition, env, public, err)
+	if r.Curve == "" {
+		r.Curve = curve.String()
+	}
+	r.Pinned = pinned
+	if werr := l.Append(r); werr != nil {
+		return errors.Join(err, zkp.Wrap(zkp.ErrIO, fmt.Errorf("audit log: %w", werr)))
+	}
+	return err
+}
+
+// readAudit reads a log, mapping a broken chain to a failed verification.
+// The records before the break are returned with it.
+func readAudit(path string) ([]audit.Record, string, error) {
+	records, head, err := audit.Read(path)
+	switch {
+	case errors.Is(err, audit.ErrTampered):
+		return records, head, zkp.Wrap(zkp.ErrVerificationFailed, err)
+	case err != nil:
+		return nil, "", zkp.Wrap(zkp.ErrIO, err)
+	}
+	return records, head, nil
+}
+
+// runAuditList lists the records matching the filters, and checks the
+// whole chain. The head it prints is worth keeping somewhere else: a log
+// cut short still chains, but no longer ends there.
+func runAuditList(args []string) error {
+	fs := flag.NewFlagSet("audit list", flag.ExitOnError)
+	path := fs.String("log", "audit.jsonl", "audit log to read")
+	name := fs.String("circuit", "", "only records of circuit IDs starting with this")
+	event := fs.String("event", "", "only prove or verify records")
+	failed := fs.Bool("failed", false, "only proofs that failed to prove or verify")
+	since := fs.Duration("since", 0, "only records newer than this, e.g. 24h (0: all)")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	if *event != "" && *event != audit.Proved && *event != audit.Verified {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("-event %q: must be %s or %s", *event, audit.Proved, audit.Verified))
+	}
+	records, head, chainErr := readAudit(*path)
+	if records == nil && chainErr != nil {
+		return chainErr
+	}
+	var listed []audit.Record
+	for _, r := range records {
+		switch {
+		case *name != "" && !strings.HasPrefix(r.Circuit, *name),
+			*event != "" && r.Event != *event,
+			*failed && r.OK,
+			*since != 0 && time.Since(r.Time) > *since:
+			continue
+		}
+		listed = append(listed, r)
+		result := "✅"
+		if !r.OK {
+			result = "❌"
+		}
+		report.printf("%5d  %s  %-6s  %s  %-22s  %s\n", r.Seq, r.Time.Format(time.RFC3339), r.Event, result, r.Circuit, shortHash(r.ProofHash))
+	}
+	report.set("records", listed)
+	report.set("total", len(records))
+	report.set("chain_ok", chainErr == nil)
+	if chainErr != nil {
+		report.printf("Chain: ❌ broken after record %d\n", len(records))
+		return chainErr
+	}
+	report.set("head", head)
+	report.printf("Chain: ✅ %d records, listed %d, head %s\n", len(records), len(listed), head)
+	return nil
+}
+
+// runAuditShow prints the records matching a sequence number, or every
+// record of the proofs whose hash starts with the argument: when a proof
+// was issued, and each time it was presented.
+func runAuditShow(args []string) error {
+	fs := flag.NewFlagSet("audit show", flag.ExitOnError)
+	path := fs.String("log", "audit.jsonl", "audit log to read")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 {
+		return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("usage: audit show [-log file] <seq | proof hash prefix>"))
+	}
+	query := strings.ToLower(fs.Arg(0))
+	records, _, chainErr := readAudit(*path)
+	if records == nil && chainErr != nil {
+		return chainErr
+	}
+	seq, bySeq := strconv.Atoi(query)
+	var shown []audit.Record
+	for _, r := range records {
+		if (bySeq == nil && r.Seq == seq) || (len(query) >= 8 && strings.HasPrefix(r.ProofHash, query)) {
+			shown = append(shown, r)
+		}
+	}
+	if len(shown) == 0 {
+		if chainErr != nil {
+			return chainErr
+		}
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("no record %q in %s (give a sequence number or at least 8 hex digits of a proof hash)", query, *path))
+	}
+	for _, r := range shown {
+		report.printf("Record %d, %s\n", r.Seq, r.Time.Format(time.RFC3339Nano))
+		report.printf("  event:       %s\n", r.Event)
+		report.printf("  circuit:     %s on %s\n", r.Circuit, r.Curve)
+		report.printf("  vk hash:     %s\n", r.VKHash)
+		report.printf("  proof hash:  %s\n", r.ProofHash)
+		for _, in := range r.PublicInputs {
+			report.printf("  %-12s %s\n", in.Name+":", in.Value)
+		}
+		if r.Event == audit.Verified {
+			report.printf("  pinned:      %t\n", r.Pinned)
+		}
+		if r.Peer != "" {
+			report.printf("  peer:        %s\n", r.Peer)
+		}
+		if r.OK {
+			report.printf("  result:      ✅ ok\n")
+		} else {
+			report.printf("  result:      ❌ %s\n", r.Error)
+		}
+	}
+	report.set("records", shown)
+	report.set("chain_ok", chainErr == nil)
+	if chainErr != nil {
+		report.printf("Chain: ❌ broken after record %d\n", len(records))
+	}
+	return chainErr
+}
+
+// shortHash abbreviates a hash for listings.
+func shortHash(h string) string {
+	if len(h) > 16 {
+		return h[:16]
+	}
+	if h == "" {
+		return "-"
+	}
+	return h
+}
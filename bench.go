@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// benchResult holds the measurements for one curve/backend/accelerator
+// combination.
+type benchResult struct {
+	Curve       string        `json:"curve"`
+	Backend     string        `json:"backend"`
+	Accelerator string        `json:"accelerator"`
+	Fallback    string        `json:"fallback,omitempty"` // why a gpu request proved on the cpu
+	Constraints int           `json:"constraints"`
+	Compile     time.Duration `json:"compile_ns"`
+	Setup       time.Duration `json:"setup_ns"`
+	Prove       time.Duration `json:"prove_ns"`
+	Verify      time.Duration `json:"verify_ns"`
+	ProofSize   int64         `json:"proof_bytes"`
+	VKSize      int64         `json:"vk_bytes"`
+	PKSize      int64         `json:"pk_bytes"`
+	Error       string        `json:"error,omitempty"` // why the run failed (prove-all-curves)
+
+	// artifacts of the run, for commands that keep them
+	vk  groth16.VerifyingKey
+	env *envelope.Envelope
+}
+
+// runBench measures the full pipeline on each requested curve and
+// accelerator.
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	curves := fs.String("curves", "bn254,bls12_381,bls12_377", "comma-separated curves to benchmark")
+	accelerators := fs.String("accelerators", string(prover.CPU), "comma-separated accelerators to prove on: cpu, gpu")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	definition, err := circuit.NewRangeCircuit(*bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	assignment, err := definition.Assign(25, 18, 30)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+
+	var accs []prover.Accelerator
+	for _, name := range strings.Split(*accelerators, ",") {
+		acc, err := prover.ParseAccelerator(strings.TrimSpace(name))
+		if err != nil {
+			return zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		accs = append(accs, acc)
+	}
+
+	var results []benchResult
+	for _, name := range strings.Split(*curves, ",") {
+		id, err := ecc.IDFromString(strings.TrimSpace(name))
+		if err != nil {
+			return zkp.Wrap(zkp.ErrCompile, fmt.Errorf("%q: %w", name, err))
+		}
+		for _, acc := range accs {
+			r, err := benchGroth16(id, acc, definition, assignment)
+			if err != nil {
+				return fmt.Errorf("%s: %w", id, err)
+			}
+			results = append(results, r)
+		}
+	}
+
+	report.set("circuit", definition.ID())
+	report.set("results", results)
+	if report.json {
+		return nil
+	}
+
+	report.printf("Circuit %s\n\n", definition.ID())
+	report.printf("%-10s %-8s %-5s %11s %9s %9s %9s %9s %7s %7s %9s\n",
+		"curve", "backend", "accel", "constraints", "compile", "setup", "prove", "verify", "proof", "vk", "pk")
+	var fallbacks []string
+	for _, r := range results {
+		accel := r.Accelerator
+		if r.Fallback != "" {
+			accel += "*"
+			fallbacks = append(fallbacks, fmt.Sprintf("%s: %s", r.Curve, r.Fallback))
+		}
+		report.printf("%-10s %-8s %-5s %11d %9v %9v %9v %9v %6dB %6dB %8dB\n",
+			r.Curve, r.Backend, accel, r.Constraints,
+			r.Compile.Round(time.Microsecond*100), r.Setup.Round(time.Microsecond*100),
+			r.Prove.Round(time.Microsecond*100), r.Verify.Round(time.Microsecond*100),
+			r.ProofSize, r.VKSize, r.PKSize)
+	}
+	for _, f := range fallbacks {
+		report.printf("* gpu requested, proved on the cpu (%s)\n", f)
+	}
+	return nil
+}
+
+// benchGroth16 runs compile → setup → prove → verify once on the given curve,
+// proving on acc. Accelerator records where the proof was actually computed,
+// which is the cpu when a gpu request fell back.
+func benchGroth16(id ecc.ID, acc prover.Accelerator, definition circuit.Definition, assignment frontend.Circuit) (benchResult, error) {
+	r := benchResult{Curve: id.String(), Backend: "groth16"}
+
+	start := time.Now()
+	ccs, err := prover.Compile(id, definition)
+	if err != nil {
+		return r, err
+	}
+	r.Compile = time.Since(start)
+	r.Constraints = ccs.GetNbConstraints()
+
+	start = time.Now()
+	pk, vk, err := prover.Setup(ccs)
+	if err != nil {
+		return r, err
+	}
+	r.Setup = time.Since(start)
+
+	witness, publicWitness, err := prover.NewWitness(id, assignment)
+	if err != nil {
+		return r, err
+	}
+
+	start = time.Now()
+	proof, used, fallback, err := prover.ProveOn(acc, ccs, pk, witness)
+	if err != nil {
+		return r, err
+	}
+	r.Prove = time.Since(start)
+	r.Accelerator = string(used)
+	if fallback != nil {
+		r.Fallback = fallback.Error()
+	}
+
+	start = time.Now()
+	if err := zkp.Verify(proof, vk, publicWitness); err != nil {
+		return r, err
+	}
+	r.Verify = time.Since(start)
+
+	if r.ProofSize, err = serializedSize(proof); err != nil {
+		return r, err
+	}
+	if r.VKSize, err = serializedSize(vk); err != nil {
+		return r, err
+	}
+	if r.PKSize, err = serializedSize(pk); err != nil {
+		return r, err
+	}
+	r.vk = vk
+	r.env, err = envelope.New(definition.ID(), vk, proof, publicWitness)
+	return r, err
+}
+
+// serializedSize returns the length of the compressed binary encoding.
+func serializedSize(v io.WriterTo) (int64, error) {
+	n, err := v.WriteTo(io.Discard)
+	return n, zkp.Wrap(zkp.ErrIO, err)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/commitment"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runBlocklist plays the issuer maintaining a blocklist: it creates the file
+// if needed, applies additions and removals and prints the root verifiers
+// pin.
+func runBlocklist(args []string) error {
+	fs := flag.NewFlagSet("blocklist", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of the identifiers")
+	depth := fs.Int("depth", circuit.DefaultBlocklistDepth, "Merkle depth of a new blocklist")
+	file := fs.String("file", "blocklist.json", "blocklist to create or update")
+	add := fs.String("add", "", "comma-separated identifiers to block")
+	remove := fs.String("remove", "", "comma-separated identifiers to unblock")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	list, err := commitment.LoadBlocklist(*file)
+	if errors.Is(err, os.ErrNotExist) {
+		list, err = commitment.NewBlocklist(curve, *bits, *depth)
+	}
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+
+	added, err := parseIDs(*add)
+	if err != nil {
+		return err
+	}
+	removed, err := parseIDs(*remove)
+	if err != nil {
+		return err
+	}
+	for _, id := range added {
+		if err := list.Add(id); err != nil {
+			return zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+	}
+	for _, id := range removed {
+		list.Remove(id)
+	}
+	if err := list.Save(*file); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+
+	root := commitment.Format(list.Root())
+	report.set("root", root)
+	report.set("blocked", len(list.IDs))
+	report.set("blocklist", *file)
+	report.printf("Blocklist root: %s\n", root)
+	report.printf("%d identifiers blocked, written to %s\n", len(list.IDs), *file)
+	return nil
+}
+
+// parseIDs parses a comma-separated list of identifiers.
+func parseIDs(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var ids []int
+	for _, field := range strings.Split(s, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,139 @@
+// Package ceremony runs a multi-party (MPC) Groth16 setup instead of the
+// single-party one in package prover.
+//
+// The setup is secure as long as a single participant destroys their
+// randomness. Phase 1 ("powers of tau") is circuit-independent and is
+// normally taken from a public transcript; phase 2 is specific to one
+// circuit. Each phase-2 participant takes the latest contribution, adds their
+// own and passes the result on; anyone can then check the whole chain before
+// the keys are extracted from it.
+//
+// gnark implements the ceremony on BN254 only. Errors wrap zkp.ErrSetup.
+package ceremony
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/groth16/bn254/mpcsetup"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+var (
+	// ErrUnsupportedCurve is returned for circuits compiled over another
+	// curve than BN254.
+	ErrUnsupportedCurve = errors.New("MPC setup is only available on bn254")
+
+	// ErrPowerMismatch is returned when the powers of tau do not match the
+	// size of the circuit.
+	ErrPowerMismatch = errors.New("powers of tau do not fit the circuit")
+
+	// ErrNoContribution is returned when finalizing a phase 2 nobody has
+	// contributed to: its δ would be the publicly known 1.
+	ErrNoContribution = errors.New("phase 2 has no contribution")
+
+	// ErrWrongStart is returned when a chain was not started from the given
+	// circuit and powers of tau.
+	ErrWrongStart = errors.New("phase 2 was not started from this circuit and powers of tau")
+)
+
+// Power returns the power of tau a circuit needs: its constraint count
+// rounded up to a power of two.
+func Power(ccs constraint.ConstraintSystem) int {
+	return bits.Len(uint(ccs.GetNbConstraints() - 1))
+}
+
+// NewPowersOfTau generates a phase 1 of 2^power elements with a single
+// contribution. It suits demos; real deployments import a public transcript.
+func NewPowersOfTau(power int) *mpcsetup.Phase1 {
+	srs1 := mpcsetup.InitPhase1(power)
+	srs1.Contribute()
+	return &srs1
+}
+
+// Init prepares phase 2 of the circuit from the powers of tau. It returns the
+// initial state, which carries no contribution yet, and the circuit
+// evaluations the keys are extracted with.
+//
+// Apart from the initial public key, both are a deterministic function of the
+// circuit and the powers of tau.
+func Init(ccs constraint.ConstraintSystem, srs1 *mpcsetup.Phase1) (*mpcsetup.Phase2, *mpcsetup.Phase2Evaluations, error) {
+	r1cs, err := toR1CS(ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+	// gnark builds the keys on the smallest domain fitting the constraints,
+	// so the powers of tau must have exactly that size.
+	if got, want := len(srs1.Parameters.G1.AlphaTau), 1<<Power(ccs); got != want {
+		return nil, nil, zkp.Wrap(zkp.ErrSetup, fmt.Errorf("%w: %d powers, circuit needs exactly %d (power %d)", ErrPowerMismatch, got, want, Power(ccs)))
+	}
+	srs2, evals := mpcsetup.InitPhase2(r1cs, srs1)
+	return &srs2, &evals, nil
+}
+
+// Contribute adds fresh randomness to a phase-2 state, in place.
+func Contribute(srs2 *mpcsetup.Phase2) {
+	srs2.Contribute()
+}
+
+// Verify checks a chain of phase-2 states, from the initial one to the
+// latest contribution, each built on the previous one.
+func Verify(chain []*mpcsetup.Phase2) error {
+	if len(chain) < 2 {
+		return zkp.Wrap(zkp.ErrSetup, ErrNoContribution)
+	}
+	if err := mpcsetup.VerifyPhase2(chain[0], chain[1], chain[2:]...); err != nil {
+		return zkp.Wrap(zkp.ErrSetup, fmt.Errorf("invalid contribution: %w", err))
+	}
+	return nil
+}
+
+// Finalize verifies the chain and extracts the proving and verifying keys
+// from its latest contribution.
+//
+// The initial state and evaluations are recomputed rather than taken from
+// the coordinator, which also checks that the chain starts from this circuit
+// and these powers of tau. (gnark does not serialize every evaluation the
+// verifying key needs, so they could not be read back anyway.)
+func Finalize(ccs constraint.ConstraintSystem, srs1 *mpcsetup.Phase1, chain []*mpcsetup.Phase2) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	initial, evals, err := Init(ccs, srs1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := Verify(chain); err != nil {
+		return nil, nil, err
+	}
+	if !sameParameters(chain[0], initial) {
+		return nil, nil, zkp.Wrap(zkp.ErrSetup, ErrWrongStart)
+	}
+	pk, vk := mpcsetup.ExtractKeys(srs1, chain[len(chain)-1], evals, ccs.GetNbConstraints())
+	return &pk, &vk, nil
+}
+
+// sameParameters compares the parameters of two phase-2 states, ignoring
+// their randomized public keys.
+func sameParameters(a, b *mpcsetup.Phase2) bool {
+	pa, pb := &a.Parameters, &b.Parameters
+	return pa.G1.Delta.Equal(&pb.G1.Delta) && pa.G2.Delta.Equal(&pb.G2.Delta) &&
+		slices.EqualFunc(pa.G1.L, pb.G1.L, equalG1) && slices.EqualFunc(pa.G1.Z, pb.G1.Z, equalG1)
+}
+
+func equalG1(p, q bn254.G1Affine) bool {
+	return p.Equal(&q)
+}
+
+func toR1CS(ccs constraint.ConstraintSystem) (*cs.R1CS, error) {
+	r1cs, ok := ccs.(*cs.R1CS)
+	if !ok {
+		return nil, zkp.Wrap(zkp.ErrSetup, ErrUnsupportedCurve)
+	}
+	return r1cs, nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/consensys/gnark/backend/groth16/bn254/mpcsetup"
+
+	"github.com/ananthanir/hello-zkp/ceremony"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+const ceremonyUsage = `usage: hello-zkp ceremony <step> [flags]
+
+steps:
+  ptau        generate demo powers of tau (phase 1) sized for a circuit
+  init        start phase 2 of a circuit from the powers of tau
+  contribute  add a contribution to the latest phase-2 state
+  verify      check every contribution made so far
+  finalize    verify the chain and write the proving and verifying keys
+`
+
+// phase2Pattern matches the phase-2 states inside a ceremony directory.
+const phase2Pattern = "phase2-*.bin"
+
+// ceremonySteps maps each ceremony step to its implementation.
+var ceremonySteps = map[string]func(args []string) error{
+	"ptau":       runCeremonyPtau,
+	"init":       runCeremonyInit,
+	"contribute": runCeremonyContribute,
+	"verify":     runCeremonyVerify,
+	"finalize":   runCeremonyFinalize,
+}
+
+// runCeremony dispatches to a step of the MPC setup ceremony.
+func runCeremony(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, ceremonyUsage)
+		os.Exit(exitUsage)
+	}
+	run, ok := ceremonySteps[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown ceremony step %q\n\n%s", args[0], ceremonyUsage)
+		os.Exit(exitUsage)
+	}
+	return run(args[1:])
+}
+
+func runCeremonyPtau(args []string) error {
+	fs := flag.NewFlagSet("ceremony ptau", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	name := fs.String("circuit", "range", "circuit to size the powers of tau for")
+	out := fs.String("out", "ptau.bin", "file to write the powers of tau to")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	_, ccs, err := compileCircuit(*name, *bits)
+	if err != nil {
+		return err
+	}
+	power := ceremony.Power(ccs)
+	if err := writeTo(*out, ceremony.NewPowersOfTau(power)); err != nil {
+		return err
+	}
+	report.set("power", power)
+	report.set("out", *out)
+	report.printf("Ceremony: ⚠️ wrote demo powers of tau (2^%d, single contribution) to %s\n", power, *out)
+	return nil
+}
+
+func runCeremonyInit(args []string) error {
+	fs := flag.NewFlagSet("ceremony init", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	name := fs.String("circuit", "range", "circuit to set up")
+	ptau := fs.String("ptau", "ptau.bin", "powers of tau (phase 1) to start from")
+	dir := fs.String("dir", "ceremony-state", "directory holding the ceremony state")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	definition, ccs, err := compileCircuit(*name, *bits)
+	if err != nil {
+		return err
+	}
+	srs1, err := readPowersOfTau(*ptau)
+	if err != nil {
+		return err
+	}
+	srs2, _, err := ceremony.Init(ccs, srs1)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	if err := writeTo(phase2Path(*dir, 0), srs2); err != nil {
+		return err
+	}
+	report.set("circuit", definition.ID())
+	report.set("dir", *dir)
+	report.printf("Ceremony: ✅ started phase 2 of circuit %s in %s\n", definition.ID(), *dir)
+	return nil
+}
+
+func runCeremonyContribute(args []string) error {
+	fs := flag.NewFlagSet("ceremony contribute", flag.ExitOnError)
+	dir := fs.String("dir", "ceremony-state", "directory holding the ceremony state")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	chain, err := readPhase2Chain(*dir)
+	if err != nil {
+		return err
+	}
+	srs2 := chain[len(chain)-1]
+	ceremony.Contribute(srs2)
+	path := phase2Path(*dir, len(chain))
+	if err := writeTo(path, srs2); err != nil {
+		return err
+	}
+	report.set("contribution", len(chain))
+	report.set("hash", hex.EncodeToString(srs2.Hash))
+	report.set("out", path)
+	report.printf("Ceremony: ✅ contribution %d written to %s\n", len(chain), path)
+	report.printf("Contribution hash: %s\n", hex.EncodeToString(srs2.Hash))
+	return nil
+}
+
+func runCeremonyVerify(args []string) error {
+	fs := flag.NewFlagSet("ceremony verify", flag.ExitOnError)
+	dir := fs.String("dir", "ceremony-state", "directory holding the ceremony state")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	chain, err := readPhase2Chain(*dir)
+	if err != nil {
+		return err
+	}
+	if err := ceremony.Verify(chain); err != nil {
+		report.println("Ceremony: ❌ FAILED")
+		return err
+	}
+	hashes := make([]string, 0, len(chain)-1)
+	for i, srs2 := range chain[1:] {
+		hashes = append(hashes, hex.EncodeToString(srs2.Hash))
+		report.printf("contribution %d: %s\n", i+1, hashes[i])
+	}
+	report.set("contributions", hashes)
+	report.printf("Ceremony: ✅ %d contributions verified\n", len(chain)-1)
+	return nil
+}
+
+func runCeremonyFinalize(args []string) error {
+	fs := flag.NewFlagSet("ceremony finalize", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	name := fs.String("circuit", "range", "circuit to set up")
+	ptau := fs.String("ptau", "ptau.bin", "powers of tau (phase 1) the ceremony started from")
+	dir := fs.String("dir", "ceremony-state", "directory holding the ceremony state")
+	keys := fs.String("keys", "keys", "directory to write pk.bin and vk.bin to")
+	compress := addCompressFlag(fs)
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	definition, ccs, err := compileCircuit(*name, *bits)
+	if err != nil {
+		return err
+	}
+	srs1, err := readPowersOfTau(*ptau)
+	if err != nil {
+		return err
+	}
+	chain, err := readPhase2Chain(*dir)
+	if err != nil {
+		return err
+	}
+	pk, vk, err := ceremony.Finalize(ccs, srs1, chain)
+	if err != nil {
+		return err
+	}
+	if err := writeKeys(*keys, pk, vk, keyfile.EncodingOf(*compress)); err != nil {
+		return err
+	}
+	report.set("circuit", definition.ID())
+	report.set("contributions", len(chain)-1)
+	report.set("keys", *keys)
+	report.printf("Ceremony: ✅ wrote keys for circuit %s from %d contributions to %s\n", definition.ID(), len(chain)-1, *keys)
+	return nil
+}
+
+func phase2Path(dir string, i int) string {
+	return filepath.Join(dir, fmt.Sprintf("phase2-%04d.bin", i))
+}
+
+func readPowersOfTau(path string) (*mpcsetup.Phase1, error) {
+	srs1 := new(mpcsetup.Phase1)
+	if err := readFrom(path, srs1); err != nil {
+		return nil, err
+	}
+	return srs1, nil
+}
+
+// readPhase2Chain reads every phase-2 state of a ceremony, initial state
+// first.
+func readPhase2Chain(dir string) ([]*mpcsetup.Phase2, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, phase2Pattern))
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	if len(paths) == 0 {
+		return nil, zkp.Wrap(zkp.ErrIO, fmt.Errorf("no phase-2 state in %s; run 'ceremony init' first", dir))
+	}
+	sort.Strings(paths)
+	chain := make([]*mpcsetup.Phase2, len(paths))
+	for i, path := range paths {
+		if path != phase2Path(dir, i) {
+			return nil, zkp.Wrap(zkp.ErrIO, fmt.Errorf("expected %s, found %s", phase2Path(dir, i), path))
+		}
+		chain[i] = new(mpcsetup.Phase2)
+		if err := readFrom(path, chain[i]); err != nil {
+			return nil, err
+		}
+	}
+	return chain, nil
+}
@@ -0,0 +1,103 @@
+// Package challenge implements the nonce exchange that makes age proofs
+// non-replayable.
+//
+// The verifier issues a fresh random challenge, the prover binds it into the
+// proof as a public input, and the verifier only accepts a proof carrying the
+// challenge it issued. A proof intercepted on the way is useless to anyone
+// else because no other verifier will have issued the same challenge.
+package challenge
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Size is the challenge length in bytes. 31 bytes keep every challenge below
+// the scalar field order of all supported curves.
+const Size = 31
+
+var (
+	// ErrMalformed is returned when a challenge string cannot be parsed.
+	ErrMalformed = errors.New("malformed challenge")
+
+	// ErrUnknown is returned when a challenge was never issued, was already
+	// consumed or has expired.
+	ErrUnknown = errors.New("unknown or expired challenge")
+)
+
+// New returns a fresh random challenge.
+func New() (*big.Int, error) {
+	var buf [Size]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, fmt.Errorf("generate challenge: %w", err)
+	}
+	return new(big.Int).SetBytes(buf[:]), nil
+}
+
+// Format returns the hex form of a challenge, as exchanged between parties.
+func Format(c *big.Int) string {
+	return fmt.Sprintf("%0*x", 2*Size, c)
+}
+
+// Parse reads a challenge in the form produced by Format.
+func Parse(s string) (*big.Int, error) {
+	c, ok := new(big.Int).SetString(s, 16)
+	if !ok || c.Sign() < 0 || c.BitLen() > 8*Size {
+		return nil, fmt.Errorf("%w: %q", ErrMalformed, s)
+	}
+	return c, nil
+}
+
+// Registry is the verifier-side record of outstanding challenges. Each
+// challenge can be consumed once, within its time to live.
+type Registry struct {
+	ttl time.Duration
+
+	mu     sync.Mutex
+	issued map[string]time.Time
+}
+
+// NewRegistry returns a registry whose challenges expire after ttl.
+func NewRegistry(ttl time.Duration) *Registry {
+	return &Registry{ttl: ttl, issued: make(map[string]time.Time)}
+}
+
+// Issue creates and records a new challenge.
+func (r *Registry) Issue() (*big.Int, error) {
+	c, err := New()
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expire(time.Now())
+	r.issued[Format(c)] = time.Now().Add(r.ttl)
+	return c, nil
+}
+
+// Consume removes a challenge from the registry, failing if it is unknown or
+// expired. Call it before verifying the proof that carries the challenge so
+// the same proof cannot be presented twice.
+func (r *Registry) Consume(c *big.Int) error {
+	key := Format(c)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	expiry, ok := r.issued[key]
+	delete(r.issued, key)
+	if !ok || time.Now().After(expiry) {
+		return ErrUnknown
+	}
+	return nil
+}
+
+func (r *Registry) expire(now time.Time) {
+	for key, expiry := range r.issued {
+		if now.After(expiry) {
+			delete(r.issued, key)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/ananthanir/hello-zkp/challenge"
+)
+
+// runChallenge prints a fresh challenge for a verifier to hand to a prover.
+func runChallenge(args []string) error {
+	fs := flag.NewFlagSet("challenge", flag.ExitOnError)
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	c, err := challenge.New()
+	if err != nil {
+		return err
+	}
+	report.set("challenge", challenge.Format(c))
+	report.println(challenge.Format(c))
+	return nil
+}
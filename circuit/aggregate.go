@@ -0,0 +1,133 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+)
+
+// ErrProofCount is returned when an aggregate is given no proofs, or more
+// proofs than it has slots.
+var ErrProofCount = errors.New("invalid number of proofs")
+
+// AggregateRangeCircuit generalizes RecursiveRangeCircuit to a batch: one
+// outer proof that Size range proofs on InnerCurve all verify against the
+// same fixed verifying key, each for its own public Min, Max, Challenge and
+// Domain.
+// A relying party checks the whole batch with one Groth16 verification whose
+// cost does not depend on Size; only the public inputs grow with it.
+type AggregateRangeCircuit struct {
+	// Private inputs: the inner proofs
+	Proofs []stdgroth16.Proof[sw_bls12377.G1Affine, sw_bls12377.G2Affine]
+
+	// Public inputs: the inner public inputs, one statement per proof
+	Inner []stdgroth16.Witness[sw_bls12377.ScalarField] `gnark:",public"`
+
+	vk stdgroth16.VerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT] `gnark:"-"`
+	id string
+}
+
+// NewAggregateRangeCircuit returns the outer circuit for batches of size
+// proofs of inner, a RangeCircuit compiled on InnerCurve, under the inner
+// verifying key vk.
+func NewAggregateRangeCircuit(inner *RangeCircuit, innerCcs constraint.ConstraintSystem, vk groth16.VerifyingKey, size int) (*AggregateRangeCircuit, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("%w: %d slots (must be at least 1)", ErrProofCount, size)
+	}
+	if vk.CurveID() != InnerCurve {
+		return nil, fmt.Errorf("inner verifying key is on %s, aggregation needs %s", vk.CurveID(), InnerCurve)
+	}
+	fixed, err := stdgroth16.ValueOfVerifyingKeyFixed[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](vk)
+	if err != nil {
+		return nil, err
+	}
+	c := &AggregateRangeCircuit{
+		Proofs: make([]stdgroth16.Proof[sw_bls12377.G1Affine, sw_bls12377.G2Affine], size),
+		Inner:  make([]stdgroth16.Witness[sw_bls12377.ScalarField], size),
+		vk:     fixed,
+		id:     fmt.Sprintf("aggregate/%dx%s", size, inner.ID()),
+	}
+	for i := range c.Proofs {
+		c.Proofs[i] = stdgroth16.PlaceholderProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](innerCcs)
+		c.Inner[i] = stdgroth16.PlaceholderWitness[sw_bls12377.ScalarField](innerCcs)
+	}
+	return c, nil
+}
+
+// Size returns the number of proofs in a batch.
+func (c *AggregateRangeCircuit) Size() int {
+	return len(c.Proofs)
+}
+
+// ID identifies the circuit shape; as for RecursiveRangeCircuit, the inner
+// verifying key is part of the compiled circuit.
+func (c *AggregateRangeCircuit) ID() string {
+	return c.id
+}
+
+// Assign returns the witness assignment for inner proofs and their public
+// witnesses. Batches smaller than Size are padded by repeating the last
+// proof, which proves nothing new.
+func (c *AggregateRangeCircuit) Assign(proofs []groth16.Proof, public []witness.Witness) (*AggregateRangeCircuit, error) {
+	if len(proofs) != len(public) {
+		return nil, fmt.Errorf("%w: %d proofs for %d public witnesses", ErrProofCount, len(proofs), len(public))
+	}
+	assignment, err := c.PublicAssignment(public)
+	if err != nil {
+		return nil, err
+	}
+	for i := range assignment.Proofs {
+		p, err := stdgroth16.ValueOfProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](proofs[min(i, len(proofs)-1)])
+		if err != nil {
+			return nil, err
+		}
+		assignment.Proofs[i] = p
+	}
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only, from the
+// inner public witnesses the verifier expects, padded as in Assign.
+func (c *AggregateRangeCircuit) PublicAssignment(public []witness.Witness) (*AggregateRangeCircuit, error) {
+	if len(public) == 0 || len(public) > c.Size() {
+		return nil, fmt.Errorf("%w: got %d (circuit takes 1 to %d)", ErrProofCount, len(public), c.Size())
+	}
+	assignment := &AggregateRangeCircuit{
+		Proofs: make([]stdgroth16.Proof[sw_bls12377.G1Affine, sw_bls12377.G2Affine], c.Size()),
+		Inner:  make([]stdgroth16.Witness[sw_bls12377.ScalarField], c.Size()),
+		vk:     c.vk,
+		id:     c.id,
+	}
+	for i := range assignment.Inner {
+		w, err := stdgroth16.ValueOfWitness[sw_bls12377.ScalarField](public[min(i, len(public)-1)])
+		if err != nil {
+			return nil, err
+		}
+		assignment.Inner[i] = w
+	}
+	return assignment, nil
+}
+
+// Define: enforce that every Proofs[i] verifies against the fixed inner key
+// for the public inputs Inner[i]
+func (c *AggregateRangeCircuit) Define(api frontend.API) error {
+	if len(c.Proofs) != len(c.Inner) {
+		return fmt.Errorf("%w: %d proofs for %d statements", ErrProofCount, len(c.Proofs), len(c.Inner))
+	}
+	verifier, err := stdgroth16.NewVerifier[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](api)
+	if err != nil {
+		return err
+	}
+	for i := range c.Proofs {
+		if err := verifier.AssertProof(c.vk, c.Proofs[i], c.Inner[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
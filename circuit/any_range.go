@@ -0,0 +1,170 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+// DefaultRangeSlots is the number of ranges of the registered "any-range"
+// circuit. Statements with fewer ranges are padded by repeating the last one,
+// which leaves their meaning unchanged.
+const DefaultRangeSlots = 4
+
+// ErrRangeCount is returned when a statement has no ranges, or more ranges
+// than the circuit has slots.
+var ErrRangeCount = errors.New("invalid number of ranges")
+
+// Bounds is one public [Min, Max] range.
+type Bounds struct {
+	Min, Max int
+}
+
+func (b Bounds) String() string {
+	return fmt.Sprintf("%d-%d", b.Min, b.Max)
+}
+
+// AnyRangeCircuit proves that Age lies in at least one of several public
+// ranges (say, a youth OR a senior discount) without revealing which.
+type AnyRangeCircuit struct {
+	// Private input: the user's age
+	Age frontend.Variable `gnark:"age"`
+
+	// Private input: one boolean per range, exactly one of them set, picking
+	// the range Age is claimed to lie in
+	Selectors []frontend.Variable `gnark:"selectors"`
+
+	// Public inputs: the ranges, as parallel lists of bounds
+	Mins []frontend.Variable `gnark:",public"`
+	Maxs []frontend.Variable `gnark:",public"`
+
+	Challenge frontend.Variable `gnark:",public"`
+
+	bits int
+}
+
+// NewAnyRangeCircuit returns a circuit definition with the given number of
+// range slots, bounding all values to the given number of bits.
+func NewAnyRangeCircuit(bits, slots int) (*AnyRangeCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	if slots < 1 {
+		return nil, fmt.Errorf("%w: %d slots (must be at least 1)", ErrRangeCount, slots)
+	}
+	return newAnyRange(bits, slots), nil
+}
+
+func newAnyRange(bits, slots int) *AnyRangeCircuit {
+	return &AnyRangeCircuit{
+		Selectors: make([]frontend.Variable, slots),
+		Mins:      make([]frontend.Variable, slots),
+		Maxs:      make([]frontend.Variable, slots),
+		bits:      bits,
+	}
+}
+
+// Slots returns the number of ranges the circuit takes.
+func (c *AnyRangeCircuit) Slots() int {
+	return len(c.Mins)
+}
+
+// ID identifies the circuit shape.
+func (c *AnyRangeCircuit) ID() string {
+	return fmt.Sprintf("any-range/%dx%d", c.Slots(), c.bits)
+}
+
+// Assign validates the inputs and returns the witness assignment, selecting
+// the first range that contains age.
+func (c *AnyRangeCircuit) Assign(age int, ranges []Bounds) (*AnyRangeCircuit, error) {
+	if err := checkPrivateFits("Age", age, c.bits); err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(ranges, nil)
+	if err != nil {
+		return nil, err
+	}
+	selected := -1
+	for i, r := range ranges {
+		if r.Min <= age && age <= r.Max {
+			selected = i
+			break
+		}
+	}
+	if selected < 0 {
+		return nil, fmt.Errorf("%w: Age = %d is in none of the ranges %v", ErrOutOfRange, age, ranges)
+	}
+	assignment.Age = age
+	for i := range assignment.Selectors {
+		assignment.Selectors[i] = 0
+	}
+	assignment.Selectors[selected] = 1
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only. A nil
+// challenge stands for zero.
+func (c *AnyRangeCircuit) PublicAssignment(ranges []Bounds, challenge *big.Int) (*AnyRangeCircuit, error) {
+	if len(ranges) == 0 || len(ranges) > c.Slots() {
+		return nil, fmt.Errorf("%w: got %d (circuit takes 1 to %d)", ErrRangeCount, len(ranges), c.Slots())
+	}
+	assignment := newAnyRange(c.bits, c.Slots())
+	for i := range assignment.Mins {
+		r := ranges[min(i, len(ranges)-1)]
+		if err := checkBounds(r.Min, r.Max, c.bits); err != nil {
+			return nil, err
+		}
+		assignment.Mins[i] = r.Min
+		assignment.Maxs[i] = r.Max
+	}
+	assignment.Challenge = challengeOrZero(challenge)
+	return assignment, nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *AnyRangeCircuit) WithChallenge(challenge *big.Int) *AnyRangeCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// Define: enforce Min_i ≤ Age ≤ Max_i for the one range picked by Selectors
+func (c *AnyRangeCircuit) Define(api frontend.API) error {
+	if err := validateBits(c.bits); err != nil {
+		return err
+	}
+	if len(c.Selectors) != len(c.Mins) || len(c.Maxs) != len(c.Mins) {
+		return fmt.Errorf("%w: %d selectors for %d mins and %d maxs", ErrRangeCount, len(c.Selectors), len(c.Mins), len(c.Maxs))
+	}
+
+	gadgets.AssertBitLen(api, c.Age, c.bits)
+
+	// Every public range must be consistent on its own, and the selectors
+	// must be booleans summing to 1.
+	var sum, lo, hi frontend.Variable = 0, 0, 0
+	for i := range c.Mins {
+		gadgets.AssertBitLen(api, c.Mins[i], c.bits)
+		gadgets.AssertBitLen(api, c.Maxs[i], c.bits)
+		gadgets.AssertLessOrEqualBounded(api, c.Mins[i], c.Maxs[i], c.bits)
+
+		api.AssertIsBoolean(c.Selectors[i])
+		sum = api.Add(sum, c.Selectors[i])
+		lo = api.Add(lo, api.Mul(c.Selectors[i], c.Mins[i]))
+		hi = api.Add(hi, api.Mul(c.Selectors[i], c.Maxs[i]))
+	}
+	api.AssertIsEqual(sum, 1)
+
+	// With exactly one selector set, lo and hi are the bounds of the picked
+	// range, already known to fit in bits.
+	gadgets.AssertLessOrEqualBounded(api, lo, c.Age, c.bits)
+	gadgets.AssertLessOrEqualBounded(api, c.Age, hi, c.bits)
+
+	// Bind the challenge, as in defineRange.
+	api.Mul(c.Challenge, c.Challenge)
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/selector"
+
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+// DefaultBracketEdges is the number of edges of the registered "age-bracket"
+// circuit: DefaultBrackets, five brackets.
+const DefaultBracketEdges = 4
+
+// DefaultBrackets are the brackets proven when none are given: under 18,
+// 18–25, 26–40, 41–64 and 65+.
+var DefaultBrackets = Brackets{18, 26, 41, 65}
+
+// ErrBrackets is returned for brackets whose edges are not strictly
+// increasing positive values, or do not match the circuit's edge count.
+var ErrBrackets = errors.New("invalid brackets")
+
+// Brackets partition ages into consecutive buckets by their edges, the
+// lowest age of every bucket but the first: edges 18,26,41,65 make the
+// brackets <18, 18–25, 26–40, 41–64 and 65+, numbered 0 to 4. Every age is in
+// exactly one bracket.
+type Brackets []int
+
+// ParseBrackets parses comma-separated edges, such as 18,26,41,65.
+func ParseBrackets(s string) (Brackets, error) {
+	var b Brackets
+	for _, field := range strings.Split(s, ",") {
+		edge, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("%w: edge %q is not an integer", ErrBrackets, field)
+		}
+		b = append(b, edge)
+	}
+	return b, b.check(MaxBits)
+}
+
+// check validates that the edges are strictly increasing, the first one at
+// least 1 so that the first bracket is not empty, and fit in bits.
+func (b Brackets) check(bits int) error {
+	if len(b) == 0 {
+		return fmt.Errorf("%w: no edges", ErrBrackets)
+	}
+	for i, edge := range b {
+		if err := checkFits("edge", edge, bits); err != nil {
+			return err
+		}
+		switch {
+		case i == 0 && edge < 1:
+			return fmt.Errorf("%w: the first edge must be at least 1", ErrBrackets)
+		case i > 0 && edge <= b[i-1]:
+			return fmt.Errorf("%w: edges %d and %d are not increasing", ErrBrackets, b[i-1], edge)
+		}
+	}
+	return nil
+}
+
+// Index returns the number of the bracket age is in.
+func (b Brackets) Index(age int) int {
+	i := 0
+	for i < len(b) && b[i] <= age {
+		i++
+	}
+	return i
+}
+
+// Label names bracket i, e.g. "<18", "26-40" or "65+".
+func (b Brackets) Label(i int) string {
+	switch {
+	case i < 0 || i > len(b):
+		return fmt.Sprintf("bracket(%d)", i)
+	case i == 0:
+		return fmt.Sprintf("<%d", b[0])
+	case i == len(b):
+		return fmt.Sprintf("%d+", b[i-1])
+	}
+	return fmt.Sprintf("%d-%d", b[i-1], b[i]-1)
+}
+
+func (b Brackets) String() string {
+	labels := make([]string, len(b)+1)
+	for i := range labels {
+		labels[i] = b.Label(i)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// BracketCircuit proves which of several public age brackets Age is in,
+// revealing the bracket and nothing else about Age. Bracket is a public
+// output: the prover computes it from Age, the circuit checks it, and the
+// verifier reads it from the proof (see ReadBracket) instead of supplying
+// it.
+type BracketCircuit struct {
+	// Private input: the user's age
+	Age frontend.Variable `gnark:"age"`
+
+	// Public inputs: the bracket edges, strictly increasing
+	Edges []frontend.Variable `gnark:",public"`
+
+	// Public output: the number of the bracket Age is in, 0 to len(Edges)
+	Bracket frontend.Variable `gnark:",public"`
+
+	Challenge frontend.Variable `gnark:",public"`
+
+	bits int
+}
+
+// NewBracketCircuit returns a circuit definition with the given number of
+// bracket edges, bounding all values to the given number of bits.
+func NewBracketCircuit(bits, edges int) (*BracketCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	if edges < 1 {
+		return nil, fmt.Errorf("%w: %d edges (must be at least 1)", ErrBrackets, edges)
+	}
+	return newBracket(bits, edges), nil
+}
+
+func newBracket(bits, edges int) *BracketCircuit {
+	return &BracketCircuit{Edges: make([]frontend.Variable, edges), bits: bits}
+}
+
+// Slots returns the number of edges the circuit takes.
+func (c *BracketCircuit) Slots() int {
+	return len(c.Edges)
+}
+
+// ID identifies the circuit shape.
+func (c *BracketCircuit) ID() string {
+	return fmt.Sprintf("age-bracket/%dx%d", c.Slots(), c.bits)
+}
+
+// Assign validates the inputs and returns the witness assignment, with
+// Bracket set to the bracket age is in.
+func (c *BracketCircuit) Assign(age int, brackets Brackets) (*BracketCircuit, error) {
+	if err := checkPrivateFits("Age", age, c.bits); err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(brackets, brackets.Index(age), nil)
+	if err != nil {
+		return nil, err
+	}
+	assignment.Age = age
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only, for a
+// proof that Age is in the given bracket. A nil challenge stands for zero.
+func (c *BracketCircuit) PublicAssignment(brackets Brackets, bracket int, challenge *big.Int) (*BracketCircuit, error) {
+	if len(brackets) != c.Slots() {
+		return nil, fmt.Errorf("%w: got %d edges (circuit takes %d)", ErrBrackets, len(brackets), c.Slots())
+	}
+	if err := brackets.check(c.bits); err != nil {
+		return nil, err
+	}
+	if bracket < 0 || bracket > len(brackets) {
+		return nil, fmt.Errorf("%w: bracket %d (there are %d)", ErrBrackets, bracket, len(brackets)+1)
+	}
+	assignment := newBracket(c.bits, c.Slots())
+	for i, edge := range brackets {
+		assignment.Edges[i] = edge
+	}
+	assignment.Bracket = bracket
+	assignment.Challenge = challengeOrZero(challenge)
+	return assignment, nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *BracketCircuit) WithChallenge(challenge *big.Int) *BracketCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// ReadBracket returns the brackets and the bracket number carried by the
+// public witness of a proof.
+func (c *BracketCircuit) ReadBracket(public witness.Witness) (Brackets, int, error) {
+	values, err := publicValues(public)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Public inputs in declaration order: Edges, Bracket, Challenge
+	if len(values) != c.Slots()+2 {
+		return nil, 0, fmt.Errorf("%w: %d public inputs, %s has %d", ErrBrackets, len(values), c.ID(), c.Slots()+2)
+	}
+	small := make([]int, c.Slots()+1)
+	for i, v := range values[:c.Slots()+1] {
+		if !v.IsInt64() || v.Int64() >= 1<<c.bits {
+			return nil, 0, fmt.Errorf("%w: %s does not fit in %d bits", ErrBrackets, v, c.bits)
+		}
+		small[i] = int(v.Int64())
+	}
+	brackets, bracket := Brackets(small[:c.Slots()]), small[c.Slots()]
+	if err := brackets.check(c.bits); err != nil {
+		return nil, 0, err
+	}
+	return brackets, bracket, nil
+}
+
+// Define: enforce Edges[Bracket-1] ≤ Age < Edges[Bracket], the outer
+// brackets being open-ended
+func (c *BracketCircuit) Define(api frontend.API) error {
+	if err := validateBits(c.bits); err != nil {
+		return err
+	}
+	if len(c.Edges) == 0 {
+		return fmt.Errorf("%w: no edges", ErrBrackets)
+	}
+
+	gadgets.AssertBitLen(api, c.Age, c.bits)
+
+	// Strictly increasing edges, the first at least 1, make the brackets a
+	// partition of [0, 2^bits): the bracket of Age is then unique.
+	gadgets.AssertBitLen(api, c.Edges[0], c.bits)
+	gadgets.AssertBitLen(api, api.Sub(c.Edges[0], 1), c.bits)
+	for i := 1; i < len(c.Edges); i++ {
+		gadgets.AssertBitLen(api, c.Edges[i], c.bits)
+		gadgets.AssertLessOrEqualBounded(api, api.Add(c.Edges[i-1], 1), c.Edges[i], c.bits)
+	}
+
+	// Bracket i spans [lows[i], highs[i]]. Mux also constrains Bracket to
+	// be one of 0 … len(Edges).
+	lows := make([]frontend.Variable, len(c.Edges)+1)
+	highs := make([]frontend.Variable, len(c.Edges)+1)
+	lows[0] = 0
+	for i, edge := range c.Edges {
+		lows[i+1] = edge
+		highs[i] = api.Sub(edge, 1)
+	}
+	highs[len(c.Edges)] = 1<<c.bits - 1
+	lo := selector.Mux(api, c.Bracket, lows...)
+	hi := selector.Mux(api, c.Bracket, highs...)
+	gadgets.AssertLessOrEqualBounded(api, lo, c.Age, c.bits)
+	gadgets.AssertLessOrEqualBounded(api, c.Age, hi, c.bits)
+
+	// Bind the challenge, as in defineRange.
+	api.Mul(c.Challenge, c.Challenge)
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package circuit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/commitment"
+)
+
+// CommittedRangeCircuit proves that Min ≤ Age ≤ Max for the age hidden in a
+// public commitment C = H(Age, Salt) issued by a registrar. Every proof about
+// the same commitment is linkable to the same registered age.
+type CommittedRangeCircuit struct {
+	// Private inputs: the commitment opening
+	Age  frontend.Variable `gnark:"age"`
+	Salt frontend.Variable `gnark:"salt"`
+
+	// Public inputs: range bounds, verifier challenge and the commitment
+	Min        frontend.Variable `gnark:",public"`
+	Max        frontend.Variable `gnark:",public"`
+	Challenge  frontend.Variable `gnark:",public"`
+	Commitment frontend.Variable `gnark:",public"`
+
+	bits int
+}
+
+// NewCommittedRangeCircuit returns a circuit definition bounding all values to
+// the given number of bits.
+func NewCommittedRangeCircuit(bits int) (*CommittedRangeCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	return &CommittedRangeCircuit{bits: bits}, nil
+}
+
+// ID identifies the circuit shape.
+func (c *CommittedRangeCircuit) ID() string {
+	return fmt.Sprintf("committed-range/%d", c.bits)
+}
+
+// Assign validates the opening and bounds and returns the witness assignment.
+func (c *CommittedRangeCircuit) Assign(opening *commitment.Opening, min, max int) (*CommittedRangeCircuit, error) {
+	if err := opening.Verify(); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateFits("Age", opening.Age, c.bits); err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(min, max, nil, opening.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	assignment.Age = opening.Age
+	assignment.Salt = opening.Salt
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only.
+func (c *CommittedRangeCircuit) PublicAssignment(min, max int, challenge, committed *big.Int) (*CommittedRangeCircuit, error) {
+	if err := checkBounds(min, max, c.bits); err != nil {
+		return nil, err
+	}
+	return &CommittedRangeCircuit{
+		Min:        min,
+		Max:        max,
+		Challenge:  challengeOrZero(challenge),
+		Commitment: committed,
+		bits:       c.bits,
+	}, nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *CommittedRangeCircuit) WithChallenge(challenge *big.Int) *CommittedRangeCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// Define: enforce Min ≤ Age ≤ Max and H(Age, Salt) = Commitment
+func (c *CommittedRangeCircuit) Define(api frontend.API) error {
+	if err := defineRange(api, c.Age, c.Min, c.Max, c.Challenge, c.bits); err != nil {
+		return err
+	}
+
+	digest, err := commitment.Hash(api, c.Age, c.Salt)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(digest, c.Commitment)
+
+	return nil
+}
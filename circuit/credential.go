@@ -0,0 +1,307 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+const (
+	// DefaultCountrySlots is the size of the allowed-country set of the
+	// registered "credential" circuit. Shorter sets are padded by repeating
+	// the last country.
+	DefaultCountrySlots = 8
+
+	// CountryBits bounds an encoded country code: 26² codes fit in 10 bits.
+	CountryBits = 10
+
+	// TierBits bounds an encoded membership tier.
+	TierBits = 2
+)
+
+var (
+	// ErrAttribute is returned for an attribute value that has no encoding.
+	ErrAttribute = errors.New("invalid attribute")
+
+	// ErrCountryCount is returned when a disclosed country set is larger
+	// than the circuit's slots.
+	ErrCountryCount = errors.New("invalid number of countries")
+
+	// ErrPredicate is returned when a credential does not satisfy a
+	// predicate it is asked to disclose.
+	ErrPredicate = errors.New("credential does not satisfy the predicate")
+)
+
+// Tier is a membership tier, ordered from lowest to highest.
+type Tier int
+
+const (
+	TierBasic Tier = iota
+	TierSilver
+	TierGold
+	TierPlatinum
+)
+
+var tierNames = []string{"basic", "silver", "gold", "platinum"}
+
+// ParseTier parses a tier name, e.g. "gold".
+func ParseTier(s string) (Tier, error) {
+	i := slices.Index(tierNames, strings.ToLower(strings.TrimSpace(s)))
+	if i < 0 {
+		return 0, fmt.Errorf("%w: tier %q (known: %s)", ErrAttribute, s, strings.Join(tierNames, ", "))
+	}
+	return Tier(i), nil
+}
+
+func (t Tier) String() string {
+	if t < 0 || int(t) >= len(tierNames) {
+		return fmt.Sprintf("tier(%d)", int(t))
+	}
+	return tierNames[t]
+}
+
+func (t Tier) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Tier) UnmarshalText(b []byte) error {
+	parsed, err := ParseTier(string(b))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// EncodeCountry encodes an ISO 3166-1 alpha-2 code, e.g. "DE", as a number
+// in [0, 26²).
+func EncodeCountry(code string) (int, error) {
+	c := strings.ToUpper(strings.TrimSpace(code))
+	if len(c) != 2 || c[0] < 'A' || c[0] > 'Z' || c[1] < 'A' || c[1] > 'Z' {
+		return 0, fmt.Errorf("%w: country %q is not a two-letter code", ErrAttribute, code)
+	}
+	return int(c[0]-'A')*26 + int(c[1]-'A'), nil
+}
+
+// DecodeCountry is the inverse of EncodeCountry.
+func DecodeCountry(v int) string {
+	if v < 0 || v >= 26*26 {
+		return fmt.Sprintf("country(%d)", v)
+	}
+	return string([]byte{byte('A' + v/26), byte('A' + v%26)})
+}
+
+// Credential holds the private attributes of a credential.
+type Credential struct {
+	Age     int    `json:"age"`
+	Country string `json:"country"`
+	Tier    Tier   `json:"tier"`
+}
+
+// Disclosure picks the predicates a credential proof discloses; nil or
+// empty fields are not disclosed, and the proof says nothing about them.
+type Disclosure struct {
+	Age       *Bounds  // Min ≤ Age ≤ Max
+	Countries []string // Country ∈ Countries
+	MinTier   *Tier    // Tier ≥ MinTier
+}
+
+func (d Disclosure) String() string {
+	var parts []string
+	if d.Age != nil {
+		parts = append(parts, fmt.Sprintf("%d ≤ Age ≤ %d", d.Age.Min, d.Age.Max))
+	}
+	if len(d.Countries) > 0 {
+		parts = append(parts, fmt.Sprintf("Country ∈ {%s}", strings.Join(d.Countries, ", ")))
+	}
+	if d.MinTier != nil {
+		parts = append(parts, fmt.Sprintf("Tier ≥ %s", *d.MinTier))
+	}
+	if len(parts) == 0 {
+		return "nothing disclosed"
+	}
+	return strings.Join(parts, " ∧ ")
+}
+
+// CredentialCircuit proves a conjunction of predicates over the private
+// attributes of one credential: an age range, membership of the country in
+// a public set and a minimum tier. Each predicate is switched on by a public
+// flag, so one setup serves every selection and the verifier sees exactly
+// which predicates were disclosed.
+type CredentialCircuit struct {
+	// Private inputs: the credential's attributes, encoded
+	Age     frontend.Variable `gnark:"age"`
+	Country frontend.Variable `gnark:"country"`
+	Tier    frontend.Variable `gnark:"tier"`
+
+	// Public inputs: one flag (0 or 1) per predicate, and its parameters.
+	// The parameters of a predicate that is not disclosed are zero.
+	DiscloseAge     frontend.Variable   `gnark:",public"`
+	MinAge          frontend.Variable   `gnark:",public"`
+	MaxAge          frontend.Variable   `gnark:",public"`
+	DiscloseCountry frontend.Variable   `gnark:",public"`
+	Countries       []frontend.Variable `gnark:",public"`
+	DiscloseTier    frontend.Variable   `gnark:",public"`
+	MinTier         frontend.Variable   `gnark:",public"`
+
+	Challenge frontend.Variable `gnark:",public"`
+
+	bits int
+}
+
+// NewCredentialCircuit returns a circuit definition with the given number of
+// country slots, bounding ages to the given number of bits.
+func NewCredentialCircuit(bits, slots int) (*CredentialCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	if slots < 1 {
+		return nil, fmt.Errorf("%w: %d slots (must be at least 1)", ErrCountryCount, slots)
+	}
+	return newCredential(bits, slots), nil
+}
+
+func newCredential(bits, slots int) *CredentialCircuit {
+	return &CredentialCircuit{Countries: make([]frontend.Variable, slots), bits: bits}
+}
+
+// Slots returns the size of the allowed-country set the circuit takes.
+func (c *CredentialCircuit) Slots() int {
+	return len(c.Countries)
+}
+
+// Bits returns the bit width ages are bounded to.
+func (c *CredentialCircuit) Bits() int {
+	return c.bits
+}
+
+// ID identifies the circuit shape.
+func (c *CredentialCircuit) ID() string {
+	return fmt.Sprintf("credential/%dx%d", c.Slots(), c.bits)
+}
+
+// Assign checks that the credential satisfies every disclosed predicate and
+// returns the witness assignment.
+func (c *CredentialCircuit) Assign(cred Credential, d Disclosure) (*CredentialCircuit, error) {
+	if err := checkPrivateFits("Age", cred.Age, c.bits); err != nil {
+		return nil, err
+	}
+	country, err := EncodeCountry(cred.Country)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrivateFits("Tier", int(cred.Tier), TierBits); err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(d, nil)
+	if err != nil {
+		return nil, err
+	}
+	if d.Age != nil && (cred.Age < d.Age.Min || cred.Age > d.Age.Max) {
+		return nil, fmt.Errorf("%w: the private Age is not in %s", ErrPredicate, d.Age)
+	}
+	if len(d.Countries) > 0 && !slices.ContainsFunc(d.Countries, func(s string) bool {
+		v, _ := EncodeCountry(s)
+		return v == country
+	}) {
+		return nil, fmt.Errorf("%w: the private Country is not in {%s}", ErrPredicate, strings.Join(d.Countries, ", "))
+	}
+	if d.MinTier != nil && cred.Tier < *d.MinTier {
+		return nil, fmt.Errorf("%w: the private Tier is below %s", ErrPredicate, *d.MinTier)
+	}
+	assignment.Age = cred.Age
+	assignment.Country = country
+	assignment.Tier = int(cred.Tier)
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only. A nil
+// challenge stands for zero.
+func (c *CredentialCircuit) PublicAssignment(d Disclosure, challenge *big.Int) (*CredentialCircuit, error) {
+	assignment := newCredential(c.bits, c.Slots())
+	assignment.DiscloseAge, assignment.MinAge, assignment.MaxAge = 0, 0, 0
+	assignment.DiscloseCountry = 0
+	for i := range assignment.Countries {
+		assignment.Countries[i] = 0
+	}
+	assignment.DiscloseTier, assignment.MinTier = 0, 0
+
+	if d.Age != nil {
+		if err := checkBounds(d.Age.Min, d.Age.Max, c.bits); err != nil {
+			return nil, err
+		}
+		assignment.DiscloseAge, assignment.MinAge, assignment.MaxAge = 1, d.Age.Min, d.Age.Max
+	}
+	if len(d.Countries) > c.Slots() {
+		return nil, fmt.Errorf("%w: got %d (circuit takes up to %d)", ErrCountryCount, len(d.Countries), c.Slots())
+	}
+	if len(d.Countries) > 0 {
+		assignment.DiscloseCountry = 1
+		for i := range assignment.Countries {
+			v, err := EncodeCountry(d.Countries[min(i, len(d.Countries)-1)])
+			if err != nil {
+				return nil, err
+			}
+			assignment.Countries[i] = v
+		}
+	}
+	if d.MinTier != nil {
+		if err := checkFits("MinTier", int(*d.MinTier), TierBits); err != nil {
+			return nil, err
+		}
+		assignment.DiscloseTier, assignment.MinTier = 1, int(*d.MinTier)
+	}
+
+	assignment.Challenge = challengeOrZero(challenge)
+	return assignment, nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *CredentialCircuit) WithChallenge(challenge *big.Int) *CredentialCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// Define: enforce each disclosed predicate. A predicate that is not
+// disclosed is checked against bounds every encoded attribute satisfies.
+func (c *CredentialCircuit) Define(api frontend.API) error {
+	if err := validateBits(c.bits); err != nil {
+		return err
+	}
+	if len(c.Countries) == 0 {
+		return fmt.Errorf("%w: no country slots", ErrCountryCount)
+	}
+	api.AssertIsBoolean(c.DiscloseAge)
+	api.AssertIsBoolean(c.DiscloseCountry)
+	api.AssertIsBoolean(c.DiscloseTier)
+
+	// Age ∈ [MinAge, MaxAge], or [0, 2^bits) when not disclosed
+	lo := api.Select(c.DiscloseAge, c.MinAge, 0)
+	hi := api.Select(c.DiscloseAge, c.MaxAge, 1<<c.bits-1)
+	gadgets.AssertInRange(api, c.Age, lo, hi, c.bits)
+
+	// Country ∈ Countries: the product of the differences vanishes
+	gadgets.AssertBitLen(api, c.Country, CountryBits)
+	var product frontend.Variable = 1
+	for _, allowed := range c.Countries {
+		product = api.Mul(product, api.Sub(c.Country, allowed))
+	}
+	api.AssertIsEqual(api.Mul(c.DiscloseCountry, product), 0)
+
+	// Tier ≥ MinTier, or ≥ 0 when not disclosed
+	gadgets.AssertBitLen(api, c.Tier, TierBits)
+	gadgets.AssertBitLen(api, c.MinTier, TierBits)
+	gadgets.AssertLessOrEqualBounded(api, api.Select(c.DiscloseTier, c.MinTier, 0), c.Tier, TierBits)
+
+	// Bind the challenge, as in defineRange.
+	api.Mul(c.Challenge, c.Challenge)
+
+	return nil
+}
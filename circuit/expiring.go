@@ -0,0 +1,214 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+// TimestampBits bounds the Unix timestamps, in seconds, of a validity window:
+// 2^40 seconds is some 34,000 years.
+const TimestampBits = 40
+
+var (
+	// ErrWindow is returned for a validity window that is not
+	// IssuedAt ≤ AsOf ≤ ExpiresAt within TimestampBits.
+	ErrWindow = errors.New("invalid validity window")
+
+	// ErrExpired is returned when a proof is checked after its window.
+	ErrExpired = errors.New("proof has expired")
+
+	// ErrNotYetValid is returned when a proof is checked before its window.
+	ErrNotYetValid = errors.New("proof is not yet valid")
+
+	// ErrWindowTooLong is returned for a window longer than a verifier
+	// accepts.
+	ErrWindowTooLong = errors.New("validity window is too long")
+)
+
+// Window is the validity window of a proof, in Unix seconds: it is valid
+// from IssuedAt to ExpiresAt and was made as of AsOf.
+type Window struct {
+	AsOf      int64 `json:"as_of"`
+	IssuedAt  int64 `json:"issued_at"`
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// NewWindow returns the window of a proof made at asOf, valid from issuedAt
+// for ttl.
+func NewWindow(asOf, issuedAt time.Time, ttl time.Duration) Window {
+	return Window{AsOf: asOf.Unix(), IssuedAt: issuedAt.Unix(), ExpiresAt: issuedAt.Add(ttl).Unix()}
+}
+
+// TTL returns how long the window is.
+func (w Window) TTL() time.Duration {
+	return time.Duration(w.ExpiresAt-w.IssuedAt) * time.Second
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%s to %s", time.Unix(w.IssuedAt, 0).UTC().Format(time.RFC3339), time.Unix(w.ExpiresAt, 0).UTC().Format(time.RFC3339))
+}
+
+// Check enforces the window against the verifier's clock, tolerating skew
+// either way.
+func (w Window) Check(now time.Time, skew time.Duration) error {
+	if now.Add(skew).Unix() < w.IssuedAt {
+		return fmt.Errorf("%w: valid from %s", ErrNotYetValid, time.Unix(w.IssuedAt, 0).UTC().Format(time.RFC3339))
+	}
+	if now.Add(-skew).Unix() > w.ExpiresAt {
+		return fmt.Errorf("%w: valid until %s", ErrExpired, time.Unix(w.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// CheckTTL rejects a window longer than max: the prover picks the window,
+// and could otherwise make a proof that never expires in practice.
+func (w Window) CheckTTL(max time.Duration) error {
+	if w.TTL() > max {
+		return fmt.Errorf("%w: valid for %v, at most %v accepted", ErrWindowTooLong, w.TTL(), max)
+	}
+	return nil
+}
+
+func (w Window) check() error {
+	for _, t := range []int64{w.AsOf, w.IssuedAt, w.ExpiresAt} {
+		if t < 0 || t >= 1<<TimestampBits {
+			return fmt.Errorf("%w: timestamp %d does not fit in %d bits", ErrWindow, t, TimestampBits)
+		}
+	}
+	if !(w.IssuedAt <= w.AsOf && w.AsOf <= w.ExpiresAt) {
+		return fmt.Errorf("%w: as of %d is not between %d and %d", ErrWindow, w.AsOf, w.IssuedAt, w.ExpiresAt)
+	}
+	return nil
+}
+
+// ExpiringRangeCircuit proves Min ≤ Age ≤ Max as a RangeCircuit does, with a
+// public validity window bound into the proof: the window cannot be changed
+// without invalidating the proof, and a verifier rejects the proof once its
+// clock is past ExpiresAt.
+type ExpiringRangeCircuit struct {
+	// Private input: the user's age
+	Age frontend.Variable `gnark:"age"`
+
+	// Public inputs: range bounds and challenge, as in RangeCircuit
+	Min       frontend.Variable `gnark:",public"`
+	Max       frontend.Variable `gnark:",public"`
+	Challenge frontend.Variable `gnark:",public"`
+
+	// Public inputs: the validity window, in Unix seconds
+	AsOf      frontend.Variable `gnark:",public"`
+	IssuedAt  frontend.Variable `gnark:",public"`
+	ExpiresAt frontend.Variable `gnark:",public"`
+
+	bits int
+}
+
+// NewExpiringRangeCircuit returns a circuit definition bounding Age, Min and
+// Max to the given number of bits.
+func NewExpiringRangeCircuit(bits int) (*ExpiringRangeCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	return &ExpiringRangeCircuit{bits: bits}, nil
+}
+
+// ID identifies the circuit shape.
+func (c *ExpiringRangeCircuit) ID() string {
+	return fmt.Sprintf("expiring-range/%d", c.bits)
+}
+
+// Assign validates the inputs and returns the witness assignment.
+func (c *ExpiringRangeCircuit) Assign(age, min, max int, window Window) (*ExpiringRangeCircuit, error) {
+	if err := checkPrivateFits("Age", age, c.bits); err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(min, max, nil, window)
+	if err != nil {
+		return nil, err
+	}
+	assignment.Age = age
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only. A nil
+// challenge stands for zero.
+func (c *ExpiringRangeCircuit) PublicAssignment(min, max int, challenge *big.Int, window Window) (*ExpiringRangeCircuit, error) {
+	if err := checkBounds(min, max, c.bits); err != nil {
+		return nil, err
+	}
+	if err := window.check(); err != nil {
+		return nil, err
+	}
+	return &ExpiringRangeCircuit{
+		Min: min, Max: max, Challenge: challengeOrZero(challenge),
+		AsOf: window.AsOf, IssuedAt: window.IssuedAt, ExpiresAt: window.ExpiresAt,
+		bits: c.bits,
+	}, nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *ExpiringRangeCircuit) WithChallenge(challenge *big.Int) *ExpiringRangeCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// ReadWindow returns the validity window held by the public witness of an
+// ExpiringRangeCircuit proof.
+func (c *ExpiringRangeCircuit) ReadWindow(public witness.Witness) (Window, error) {
+	values, err := publicValues(public)
+	if err != nil {
+		return Window{}, err
+	}
+	// Public inputs in declaration order: Min, Max, Challenge, AsOf,
+	// IssuedAt, ExpiresAt
+	if len(values) != 6 {
+		return Window{}, fmt.Errorf("%w: %d public inputs, %s has 6", ErrWindow, len(values), c.ID())
+	}
+	var ts [3]int64
+	for i, v := range values[3:] {
+		if !v.IsInt64() {
+			return Window{}, fmt.Errorf("%w: timestamp %s", ErrWindow, v)
+		}
+		ts[i] = v.Int64()
+	}
+	w := Window{AsOf: ts[0], IssuedAt: ts[1], ExpiresAt: ts[2]}
+	return w, w.check()
+}
+
+// Define: enforce Min ≤ Age ≤ Max and IssuedAt ≤ AsOf ≤ ExpiresAt
+func (c *ExpiringRangeCircuit) Define(api frontend.API) error {
+	if err := defineRange(api, c.Age, c.Min, c.Max, c.Challenge, c.bits); err != nil {
+		return err
+	}
+	gadgets.AssertBitLen(api, c.AsOf, TimestampBits)
+	gadgets.AssertBitLen(api, c.IssuedAt, TimestampBits)
+	gadgets.AssertBitLen(api, c.ExpiresAt, TimestampBits)
+	gadgets.AssertLessOrEqualBounded(api, c.IssuedAt, c.AsOf, TimestampBits)
+	gadgets.AssertLessOrEqualBounded(api, c.AsOf, c.ExpiresAt, TimestampBits)
+	return nil
+}
+
+// publicValues returns the values of a public witness, whatever the curve.
+func publicValues(public witness.Witness) ([]*big.Int, error) {
+	vector := reflect.ValueOf(public.Vector())
+	if vector.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("unexpected witness vector %T", public.Vector())
+	}
+	values := make([]*big.Int, vector.Len())
+	for i := range values {
+		e, ok := vector.Index(i).Addr().Interface().(interface{ BigInt(*big.Int) *big.Int })
+		if !ok {
+			return nil, fmt.Errorf("unexpected witness element %s", vector.Index(i).Type())
+		}
+		values[i] = e.BigInt(new(big.Int))
+	}
+	return values, nil
+}
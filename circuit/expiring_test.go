@@ -0,0 +1,30 @@
+package circuit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewWindowIssuedAt(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	issued := now.Add(-time.Hour)
+	w := NewWindow(now, issued, 2*time.Hour)
+	if w.IssuedAt != issued.Unix() || w.ExpiresAt != issued.Add(2*time.Hour).Unix() {
+		t.Errorf("NewWindow = %+v, want %v to %v", w, issued.Unix(), issued.Add(2*time.Hour).Unix())
+	}
+	if w.TTL() != 2*time.Hour {
+		t.Errorf("TTL = %v, want 2h", w.TTL())
+	}
+}
+
+func TestWindowCheckTTL(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	w := NewWindow(now, now, 24*time.Hour)
+	if err := w.CheckTTL(24 * time.Hour); err != nil {
+		t.Errorf("CheckTTL(24h) of a 24h window: %v", err)
+	}
+	if err := w.CheckTTL(time.Hour); !errors.Is(err, ErrWindowTooLong) {
+		t.Errorf("CheckTTL(1h) of a 24h window = %v, want ErrWindowTooLong", err)
+	}
+}
@@ -0,0 +1,150 @@
+package circuit
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/expr"
+)
+
+// ExprPrefix starts the circuit name of a statement written in the expr
+// language, as in "expr:age >= min && age % 2 == 0".
+const ExprPrefix = "expr:"
+
+// ExprCircuit proves a statement of the expr language, compiled at runtime.
+// Its inputs are the statement's variables, private or public as the
+// statement declares them, in order of first use.
+type ExprCircuit struct {
+	// Private inputs: the private variables
+	Private []frontend.Variable `gnark:"private"`
+
+	// Public inputs: the public variables
+	Public []frontend.Variable `gnark:",public"`
+
+	Challenge frontend.Variable `gnark:",public"`
+
+	statement *expr.Statement `gnark:"-"`
+}
+
+// NewExprCircuit parses src and returns the circuit proving it, bounding
+// every variable to the given number of bits.
+func NewExprCircuit(src string, bits int) (*ExprCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	s, err := expr.Parse(src, bits)
+	if err != nil {
+		return nil, err
+	}
+	return newExpr(s), nil
+}
+
+func newExpr(s *expr.Statement) *ExprCircuit {
+	return &ExprCircuit{
+		Private:   make([]frontend.Variable, len(s.Private())),
+		Public:    make([]frontend.Variable, len(s.Public())),
+		statement: s,
+	}
+}
+
+// Statement returns the parsed statement.
+func (c *ExprCircuit) Statement() *expr.Statement {
+	return c.statement
+}
+
+// ID identifies the circuit by a hash of the canonical statement, which
+// fixes every constraint.
+func (c *ExprCircuit) ID() string {
+	sum := sha256.Sum256([]byte(c.statement.String()))
+	return fmt.Sprintf("expr/%d/%x", c.statement.Bits(), sum[:6])
+}
+
+// Assign checks that the statement holds for values, which must hold every
+// variable, and returns the witness assignment. The assignment is not bound
+// to a challenge; use WithChallenge for that.
+func (c *ExprCircuit) Assign(values map[string]int) (*ExprCircuit, error) {
+	all := make(map[string]*big.Int, len(values))
+	for name, v := range values {
+		all[name] = big.NewInt(int64(v))
+	}
+	if err := c.check(values, c.statement.Vars()); err != nil {
+		return nil, err
+	}
+	if err := c.statement.Eval(all); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOutOfRange, err)
+	}
+	assignment, err := c.PublicAssignment(values, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range c.statement.Private() {
+		assignment.Private[i] = values[name]
+	}
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only, from
+// values holding at least every public variable. A nil challenge stands for
+// zero.
+func (c *ExprCircuit) PublicAssignment(values map[string]int, challenge *big.Int) (*ExprCircuit, error) {
+	if err := c.check(values, c.statement.Public()); err != nil {
+		return nil, err
+	}
+	assignment := newExpr(c.statement)
+	for i, name := range c.statement.Public() {
+		assignment.Public[i] = values[name]
+	}
+	assignment.Challenge = challengeOrZero(challenge)
+	return assignment, nil
+}
+
+// check rejects missing values and values that do not fit in bits.
+func (c *ExprCircuit) check(values map[string]int, names []string) error {
+	for _, name := range names {
+		v, ok := values[name]
+		if !ok {
+			return fmt.Errorf("%w: no value for %s", ErrOutOfRange, name)
+		}
+		check := checkFits
+		if slices.Contains(c.statement.Private(), name) {
+			check = checkPrivateFits
+		}
+		if err := check(name, v, c.statement.Bits()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *ExprCircuit) WithChallenge(challenge *big.Int) *ExprCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// Define: enforce the statement
+func (c *ExprCircuit) Define(api frontend.API) error {
+	if len(c.Private) != len(c.statement.Private()) || len(c.Public) != len(c.statement.Public()) {
+		return fmt.Errorf("%d private and %d public inputs for %s", len(c.Private), len(c.Public), c.statement)
+	}
+	vars := make(map[string]frontend.Variable, len(c.Private)+len(c.Public))
+	for i, name := range c.statement.Private() {
+		vars[name] = c.Private[i]
+	}
+	for i, name := range c.statement.Public() {
+		vars[name] = c.Public[i]
+	}
+	if err := c.statement.Define(api, vars); err != nil {
+		return err
+	}
+
+	// Bind the challenge, as in defineRange.
+	api.Mul(c.Challenge, c.Challenge)
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// ToField maps an input value to the scalar field of the given order, which
+// gnark would otherwise do silently by reducing it: -1 becomes the order
+// minus one, and a value at or above the order aliases a smaller one, so the
+// proof is about another number than the caller meant. Values must be Go
+// integers, big.Ints, decimal or 0x-prefixed strings or field elements, and
+// lie in [0, field). Errors wrap ErrOutOfRange and name the input.
+func ToField(name string, v any, field *big.Int) (*big.Int, error) {
+	return toField(name, v, field, false)
+}
+
+// CheckAssignment runs ToField on every input of an assignment before it is
+// turned into a witness. With publicOnly, only the public inputs are
+// checked and the private ones may be unset, as in a verifier's statement.
+// Errors about a private input name it but leave out its value.
+func CheckAssignment(assignment frontend.Circuit, field *big.Int, publicOnly bool) error {
+	tVariable := reflect.TypeOf((*frontend.Variable)(nil)).Elem()
+	_, err := schema.Walk(assignment, tVariable, func(leaf schema.LeafInfo, value reflect.Value) error {
+		private := leaf.Visibility != schema.Public
+		if publicOnly && private {
+			return nil
+		}
+		v := value.Interface()
+		if v == nil {
+			return fmt.Errorf("%w: %s is not assigned", ErrOutOfRange, leaf.FullName())
+		}
+		_, err := toField(leaf.FullName(), v, field, private)
+		return err
+	})
+	return err
+}
+
+// toField is ToField, with errors that do not show v when it is private.
+func toField(name string, v any, field *big.Int, private bool) (*big.Int, error) {
+	x, err := toBig(v)
+	if err != nil {
+		if private {
+			return nil, fmt.Errorf("%w: private %s is not an integer", ErrOutOfRange, name)
+		}
+		return nil, fmt.Errorf("%w: %s: %v", ErrOutOfRange, name, err)
+	}
+	if x.Sign() < 0 {
+		if private {
+			return nil, fmt.Errorf("%w: private %s is negative", ErrOutOfRange, name)
+		}
+		return nil, fmt.Errorf("%w: %s = %s is negative; in the scalar field it would wrap to p - %s, so pass a non-negative value",
+			ErrOutOfRange, name, x, new(big.Int).Neg(x))
+	}
+	if x.Cmp(field) >= 0 {
+		if private {
+			return nil, fmt.Errorf("%w: private %s is not below the %d-bit scalar field order", ErrOutOfRange, name, field.BitLen())
+		}
+		return nil, fmt.Errorf("%w: %s = %s is not below the %d-bit scalar field order; it would be reduced to %s, so reduce it first or use a smaller value",
+			ErrOutOfRange, name, x, field.BitLen(), new(big.Int).Mod(x, field))
+	}
+	return x, nil
+}
+
+// toBig converts the value kinds gnark accepts in a witness.
+func toBig(v any) (*big.Int, error) {
+	switch x := v.(type) {
+	case *big.Int:
+		if x == nil {
+			return nil, errors.New("nil big.Int")
+		}
+		return new(big.Int).Set(x), nil
+	case big.Int:
+		return new(big.Int).Set(&x), nil
+	case string:
+		n, ok := new(big.Int).SetString(x, 0)
+		if !ok {
+			return nil, fmt.Errorf("%q is not an integer", x)
+		}
+		return n, nil
+	case []byte:
+		return new(big.Int).SetBytes(x), nil
+	case interface{ BigInt(*big.Int) *big.Int }:
+		return x.BigInt(new(big.Int)), nil
+	}
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(r.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(r.Uint()), nil
+	case reflect.Array:
+		// A field element passed by value, such as fr.Element.
+		ptr := reflect.New(r.Type())
+		ptr.Elem().Set(r)
+		if e, ok := ptr.Interface().(interface{ BigInt(*big.Int) *big.Int }); ok {
+			return e.BigInt(new(big.Int)), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
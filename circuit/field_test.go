@@ -0,0 +1,118 @@
+package circuit
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestToField(t *testing.T) {
+	p := ecc.BN254.ScalarField()
+	last := new(big.Int).Sub(p, big.NewInt(1))
+	var seven fr.Element
+	seven.SetUint64(7)
+	for _, tc := range []struct {
+		name string
+		v    any
+		want *big.Int // nil: rejected
+	}{
+		{"zero", 0, big.NewInt(0)},
+		{"int", 25, big.NewInt(25)},
+		{"uint64", uint64(1) << 63, new(big.Int).Lsh(big.NewInt(1), 63)},
+		{"p - 1", last, last},
+		{"hex string", "0x10", big.NewInt(16)},
+		{"decimal string", "42", big.NewInt(42)},
+		{"field element", seven, big.NewInt(7)},
+		{"field element pointer", &seven, big.NewInt(7)},
+		// Each of these would silently become another number in the field.
+		{"-1", -1, nil},
+		{"negative big.Int", big.NewInt(-7), nil},
+		{"p", new(big.Int).Set(p), nil},
+		{"p + 7", new(big.Int).Add(p, big.NewInt(7)), nil},
+		{"p as a string", p.String(), nil},
+		{"-1 as a string", "-1", nil},
+		{"not a number", "twenty", nil},
+		{"float", 1.5, nil},
+		{"nil big.Int", (*big.Int)(nil), nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ToField("challenge", tc.v, p)
+			if tc.want == nil {
+				if !errors.Is(err, ErrOutOfRange) {
+					t.Fatalf("ToField(%v) = %v, %v; want ErrOutOfRange", tc.v, got, err)
+				}
+				if !strings.Contains(err.Error(), "challenge") {
+					t.Errorf("error %q does not name the input", err)
+				}
+				return
+			}
+			if err != nil || got.Cmp(tc.want) != 0 {
+				t.Errorf("ToField(%v) = %v, %v; want %v", tc.v, got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckAssignment(t *testing.T) {
+	p := ecc.BN254.ScalarField()
+	withChallenge := func(ch any) *RangeCircuit {
+		c := rawRange(25, 18, 30)
+		c.Challenge = ch
+		return c
+	}
+	for _, tc := range []struct {
+		name       string
+		assignment *RangeCircuit
+		publicOnly bool
+		ok         bool
+	}{
+		{"valid", withChallenge(7), false, true},
+		{"challenge p + 7", withChallenge(new(big.Int).Add(p, big.NewInt(7))), false, false},
+		{"challenge -1", withChallenge(-1), false, false},
+		{"negative age", rawRange(-1, 0, 30), false, false},
+		{"unset age", rawRange(nil, 18, 30), false, false},
+		{"unset age, public only", rawRange(nil, 18, 30), true, true},
+		{"wrapped public input, public only", withChallenge(new(big.Int).Set(p)), true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckAssignment(tc.assignment, p, tc.publicOnly)
+			if tc.ok && err != nil {
+				t.Errorf("CheckAssignment: %v", err)
+			}
+			if !tc.ok && !errors.Is(err, ErrOutOfRange) {
+				t.Errorf("CheckAssignment = %v, want ErrOutOfRange", err)
+			}
+		})
+	}
+}
+
+func TestCheckAssignmentRedactsPrivate(t *testing.T) {
+	p := ecc.BN254.ScalarField()
+	wrapped := new(big.Int).Add(p, big.NewInt(123457))
+	for _, tc := range []struct {
+		name  string
+		age   any
+		value string
+	}{
+		{"negative", -123457, "123457"},
+		{"above the field", wrapped, wrapped.String()},
+		{"malformed", "12x3457", "12x3457"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckAssignment(rawRange(tc.age, 18, 30), p, false)
+			if !errors.Is(err, ErrOutOfRange) {
+				t.Fatalf("CheckAssignment = %v, want ErrOutOfRange", err)
+			}
+			if !strings.Contains(err.Error(), "private age") {
+				t.Errorf("error %q does not name the input", err)
+			}
+			if strings.Contains(err.Error(), tc.value) {
+				t.Errorf("error %q shows the private value", err)
+			}
+		})
+	}
+}
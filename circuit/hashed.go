@@ -0,0 +1,124 @@
+package circuit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/commitment"
+)
+
+// HashedRangeCircuit proves the statement of RangeCircuit with a single
+// public input: the digest of Min, Max, Challenge and Domain (see
+// commitment.HashInputs). The four values become private inputs the verifier
+// knows; it recomputes the digest from them off-circuit, and a Groth16
+// verifier, on chain in particular, pays for one public input instead of
+// four. Digests are computed natively on BN254 only, as commitments are.
+type HashedRangeCircuit struct {
+	// Private input: the user's age
+	Age frontend.Variable `gnark:"age"`
+
+	// Private inputs the verifier knows: the inputs of RangeCircuit
+	Min       frontend.Variable
+	Max       frontend.Variable
+	Challenge frontend.Variable
+	Domain    frontend.Variable
+
+	// Public input: H(Min, Max, Challenge, Domain)
+	Digest frontend.Variable `gnark:",public"`
+
+	bits int
+
+	// challenge and domain keep the values Digest was computed from.
+	challenge *big.Int `gnark:"-"`
+	domain    *big.Int `gnark:"-"`
+}
+
+// NewHashedRangeCircuit returns a circuit definition bounding all values to
+// the given number of bits.
+func NewHashedRangeCircuit(bits int) (*HashedRangeCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	return &HashedRangeCircuit{bits: bits}, nil
+}
+
+// ID identifies the circuit shape.
+func (c *HashedRangeCircuit) ID() string {
+	return fmt.Sprintf("hashed-range/%d", c.bits)
+}
+
+// Assign validates the inputs and returns the witness assignment, bound to
+// no challenge or domain; use WithChallenge and WithDomain for those.
+func (c *HashedRangeCircuit) Assign(age, min, max int) (*HashedRangeCircuit, error) {
+	if err := (AgeWitness{Age: age, Min: min, Max: max}).Validate(c.bits); err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(min, max, nil)
+	if err != nil {
+		return nil, err
+	}
+	assignment.Age = age
+	return assignment, nil
+}
+
+// PublicAssignment returns the assignment a verifier builds from its own
+// bounds and challenge (nil for none): only Digest is set, as only Digest
+// is public.
+func (c *HashedRangeCircuit) PublicAssignment(min, max int, challenge *big.Int) (*HashedRangeCircuit, error) {
+	if err := checkBounds(min, max, c.bits); err != nil {
+		return nil, err
+	}
+	assignment := &HashedRangeCircuit{Min: min, Max: max, bits: c.bits}
+	return assignment.WithChallenge(challenge), nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given
+// challenge, with the digest recomputed.
+func (c *HashedRangeCircuit) WithChallenge(challenge *big.Int) *HashedRangeCircuit {
+	bound := *c
+	bound.challenge = challenge
+	return bound.seal()
+}
+
+// WithDomain returns a copy of the assignment bound to the given domain
+// hash, as returned by domain.Hash (nil for none), with the digest
+// recomputed.
+func (c *HashedRangeCircuit) WithDomain(domain *big.Int) *HashedRangeCircuit {
+	bound := *c
+	bound.domain = domain
+	return bound.seal()
+}
+
+// seal sets Challenge, Domain and the digest of the public values.
+func (c *HashedRangeCircuit) seal() *HashedRangeCircuit {
+	c.Challenge, c.Domain = challengeOrZero(c.challenge), challengeOrZero(c.domain)
+	values := make([]*big.Int, 4)
+	for i, v := range []frontend.Variable{c.Min, c.Max, c.Challenge, c.Domain} {
+		switch v := v.(type) {
+		case int:
+			values[i] = big.NewInt(int64(v))
+		case *big.Int:
+			values[i] = v
+		}
+	}
+	c.Digest = commitment.InputsDigest(values...)
+	return c
+}
+
+// Define: enforce Min ≤ Age ≤ Max and H(Min, Max, Challenge, Domain) = Digest
+func (c *HashedRangeCircuit) Define(api frontend.API) error {
+	if err := defineRange(api, c.Age, c.Min, c.Max, c.Challenge, c.bits); err != nil {
+		return err
+	}
+
+	// The digest binds every value the verifier knows, Domain included.
+	digest, err := commitment.HashInputs(api, c.Min, c.Max, c.Challenge, c.Domain)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(digest, c.Digest)
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package circuit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+// MonthsPerYear divides an age in months into whole years and months.
+const MonthsPerYear = 12
+
+// MonthsCircuit proves that Min ≤ Years ≤ Max for an age known to the month,
+// AgeMonths = 12·Years + Months with 0 ≤ Months < 12, revealing neither
+// AgeMonths nor the split. Years is the age in whole years, rounded down as
+// a birthday is: 215 months is 17 years 11 months, under 18.
+//
+// Years and Months are not inputs. The prover's solver computes them from
+// AgeMonths with a hint (package hints), outside the circuit, and the circuit
+// checks the split with a few constraints instead of dividing; see
+// gadgets.DivMod.
+type MonthsCircuit struct {
+	// Private input: the user's age in whole months
+	AgeMonths frontend.Variable `gnark:"age_months"`
+
+	// Public inputs: bounds on the age in whole years
+	Min frontend.Variable `gnark:",public"`
+	Max frontend.Variable `gnark:",public"`
+
+	Challenge frontend.Variable `gnark:",public"`
+
+	bits int
+}
+
+// NewMonthsCircuit returns a circuit definition bounding Years, Min and Max
+// to the given number of bits.
+func NewMonthsCircuit(bits int) (*MonthsCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	return &MonthsCircuit{bits: bits}, nil
+}
+
+// ID identifies the circuit shape.
+func (c *MonthsCircuit) ID() string {
+	return fmt.Sprintf("age-months/%d", c.bits)
+}
+
+// Assign validates the inputs and returns the witness assignment for an age
+// of months months.
+func (c *MonthsCircuit) Assign(months, min, max int) (*MonthsCircuit, error) {
+	if months < 0 {
+		return nil, fmt.Errorf("%w: private AgeMonths is negative", ErrOutOfRange)
+	}
+	if err := checkPrivateFits("Years", months/MonthsPerYear, c.bits); err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(min, max, nil)
+	if err != nil {
+		return nil, err
+	}
+	assignment.AgeMonths = months
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only. A nil
+// challenge stands for zero.
+func (c *MonthsCircuit) PublicAssignment(min, max int, challenge *big.Int) (*MonthsCircuit, error) {
+	if err := checkBounds(min, max, c.bits); err != nil {
+		return nil, err
+	}
+	return &MonthsCircuit{Min: min, Max: max, Challenge: challengeOrZero(challenge), bits: c.bits}, nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *MonthsCircuit) WithChallenge(challenge *big.Int) *MonthsCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// Define: split AgeMonths = 12·Years + Months, then enforce
+// Min ≤ Years ≤ Max
+func (c *MonthsCircuit) Define(api frontend.API) error {
+	if err := validateBits(c.bits); err != nil {
+		return err
+	}
+
+	// Years < 2^bits and Months < 12 bound AgeMonths too: no value outside
+	// [0, 12·2^bits) splits.
+	maxYears := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(c.bits)), big.NewInt(1))
+	years, _, err := gadgets.DivMod(api, c.AgeMonths, big.NewInt(MonthsPerYear), maxYears)
+	if err != nil {
+		return err
+	}
+	return defineRange(api, years, c.Min, c.Max, c.Challenge, c.bits)
+}
@@ -0,0 +1,140 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/commitment"
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+// DefaultBlocklistDepth is the Merkle depth of the registered non-membership
+// circuit: room for 1023 blocked identifiers.
+const DefaultBlocklistDepth = 10
+
+// ErrBlocklistShape is returned when a blocklist does not have the bit width
+// and depth the circuit was built for.
+var ErrBlocklistShape = errors.New("blocklist shape does not match the circuit")
+
+// NonMembershipCircuit proves that a private identifier is not in a
+// blocklist, given only the blocklist's public Merkle root (see
+// commitment.Blocklist): the identifier lies strictly between the two
+// neighbours of some leaf of the tree. Nothing ties the identifier to a
+// credential, so the proof says only that some identifier is not blocked;
+// cmd/issuer checks a credential's revocation against its claim instead.
+type NonMembershipCircuit struct {
+	// Private inputs: the identifier and the gap it falls into
+	Identifier frontend.Variable   `gnark:"id"`
+	Low        frontend.Variable   `gnark:"low"`
+	High       frontend.Variable   `gnark:"high"`
+	Index      frontend.Variable   `gnark:"index"`
+	Path       []frontend.Variable `gnark:"path"`
+
+	// Public inputs: verifier challenge and the blocklist root
+	Challenge frontend.Variable `gnark:",public"`
+	Root      frontend.Variable `gnark:",public"`
+
+	bits  int
+	depth int
+}
+
+// NewNonMembershipCircuit returns a circuit definition for identifiers of the
+// given bit width and blocklists of 2^depth leaves.
+func NewNonMembershipCircuit(bits, depth int) (*NonMembershipCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	if depth < 1 {
+		return nil, fmt.Errorf("invalid blocklist depth %d", depth)
+	}
+	return newNonMembership(bits, depth), nil
+}
+
+func newNonMembership(bits, depth int) *NonMembershipCircuit {
+	return &NonMembershipCircuit{Path: make([]frontend.Variable, depth), bits: bits, depth: depth}
+}
+
+// ID identifies the circuit shape.
+func (c *NonMembershipCircuit) ID() string {
+	return fmt.Sprintf("non-membership/%dx%d", c.bits, c.depth)
+}
+
+// Assign looks up the gap id falls into and returns the witness assignment.
+// It fails with commitment.ErrBlocked if id is in the blocklist.
+func (c *NonMembershipCircuit) Assign(list *commitment.Blocklist, id int) (*NonMembershipCircuit, error) {
+	if list.Bits != c.bits || list.Depth != c.depth {
+		return nil, fmt.Errorf("%w: %d-bit identifiers at depth %d, circuit expects %d at depth %d",
+			ErrBlocklistShape, list.Bits, list.Depth, c.bits, c.depth)
+	}
+	gap, err := list.NonMembership(id)
+	if err != nil {
+		return nil, err
+	}
+	assignment := c.PublicAssignment(nil, list.Root())
+	assignment.Identifier = id
+	assignment.Low = gap.Low
+	assignment.High = gap.High
+	assignment.Index = gap.Index
+	for i, sibling := range gap.Path {
+		assignment.Path[i] = sibling
+	}
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only.
+func (c *NonMembershipCircuit) PublicAssignment(challenge, root *big.Int) *NonMembershipCircuit {
+	assignment := newNonMembership(c.bits, c.depth)
+	assignment.Challenge = challengeOrZero(challenge)
+	assignment.Root = root
+	return assignment
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *NonMembershipCircuit) WithChallenge(challenge *big.Int) *NonMembershipCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// Define: enforce Low < Identifier < High and that H(Low, High) is leaf
+// Index of the tree with root Root
+func (c *NonMembershipCircuit) Define(api frontend.API) error {
+	if err := validateBits(c.bits); err != nil {
+		return err
+	}
+	if len(c.Path) != c.depth {
+		return fmt.Errorf("path has %d siblings, circuit depth is %d", len(c.Path), c.depth)
+	}
+
+	gadgets.AssertBitLen(api, c.Identifier, c.bits)
+	gadgets.AssertBitLen(api, c.Low, c.bits)
+	gadgets.AssertBitLen(api, c.High, c.bits)
+
+	// Low + 1 ≤ Identifier and Identifier + 1 ≤ High. The left operand may
+	// reach 2^bits, one past the bound, which still cannot wrap: the
+	// difference then is negative and fails the bit decomposition.
+	gadgets.AssertLessOrEqualBounded(api, api.Add(c.Low, 1), c.Identifier, c.bits)
+	gadgets.AssertLessOrEqualBounded(api, api.Add(c.Identifier, 1), c.High, c.bits)
+
+	node, err := commitment.HashGap(api, c.Low, c.High)
+	if err != nil {
+		return err
+	}
+	directions := api.ToBinary(c.Index, c.depth)
+	for i, sibling := range c.Path {
+		left := api.Select(directions[i], sibling, node)
+		right := api.Select(directions[i], node, sibling)
+		if node, err = commitment.HashNode(api, left, right); err != nil {
+			return err
+		}
+	}
+	api.AssertIsEqual(node, c.Root)
+
+	// Bind the challenge, as in defineRange.
+	api.Mul(c.Challenge, c.Challenge)
+
+	return nil
+}
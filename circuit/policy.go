@@ -0,0 +1,90 @@
+package circuit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/commitment"
+)
+
+// PolicyRangeCircuit proves that Min ≤ Age ≤ Max for bounds that are
+// themselves private: only the verifier's policy commitment
+// P = H(Min ‖ Max, Salt) is public, so the exact thresholds are not revealed.
+type PolicyRangeCircuit struct {
+	// Private inputs: the user's age and the policy opening
+	Age        frontend.Variable `gnark:"age"`
+	Min        frontend.Variable `gnark:"min"`
+	Max        frontend.Variable `gnark:"max"`
+	PolicySalt frontend.Variable `gnark:"policy_salt"`
+
+	// Public inputs: verifier challenge and the policy commitment
+	Challenge frontend.Variable `gnark:",public"`
+	Policy    frontend.Variable `gnark:",public"`
+
+	bits int
+}
+
+// NewPolicyRangeCircuit returns a circuit definition bounding all values to
+// the given number of bits.
+func NewPolicyRangeCircuit(bits int) (*PolicyRangeCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	return &PolicyRangeCircuit{bits: bits}, nil
+}
+
+// ID identifies the circuit shape.
+func (c *PolicyRangeCircuit) ID() string {
+	return fmt.Sprintf("policy-range/%d", c.bits)
+}
+
+// Assign validates the age against the policy opening and returns the
+// witness assignment.
+func (c *PolicyRangeCircuit) Assign(age int, policy *commitment.Policy) (*PolicyRangeCircuit, error) {
+	if err := policy.Verify(); err != nil {
+		return nil, err
+	}
+	if err := checkPrivateFits("Age", age, c.bits); err != nil {
+		return nil, err
+	}
+	if err := checkBounds(policy.Min, policy.Max, c.bits); err != nil {
+		return nil, err
+	}
+	assignment := c.PublicAssignment(nil, policy.Commitment)
+	assignment.Age = age
+	assignment.Min = policy.Min
+	assignment.Max = policy.Max
+	assignment.PolicySalt = policy.Salt
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only.
+func (c *PolicyRangeCircuit) PublicAssignment(challenge, policy *big.Int) *PolicyRangeCircuit {
+	return &PolicyRangeCircuit{Challenge: challengeOrZero(challenge), Policy: policy, bits: c.bits}
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *PolicyRangeCircuit) WithChallenge(challenge *big.Int) *PolicyRangeCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// Define: enforce Min ≤ Age ≤ Max and H(Min ‖ Max, PolicySalt) = Policy
+func (c *PolicyRangeCircuit) Define(api frontend.API) error {
+	if err := defineRange(api, c.Age, c.Min, c.Max, c.Challenge, c.bits); err != nil {
+		return err
+	}
+
+	// defineRange bounds Min and Max to bits ≤ MaxBits < 64, as HashPolicy
+	// requires.
+	digest, err := commitment.HashPolicy(api, c.Min, c.Max, c.PolicySalt)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(digest, c.Policy)
+
+	return nil
+}
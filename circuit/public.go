@@ -0,0 +1,172 @@
+package circuit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// ErrPublicInputs is returned when named public inputs do not match the
+// public inputs a circuit declares.
+var ErrPublicInputs = errors.New("public inputs do not match the circuit")
+
+// PublicInputs are the public inputs of a statement by name. A public
+// witness is a bare list of field elements whose meaning depends on the
+// order the circuit declares its public fields in; a verifier that builds
+// one by hand, from another language say, gets a proof rejected (or worse,
+// a different statement accepted) by swapping two of them. Named inputs are
+// put in that order by the schema instead.
+//
+// Canonical inputs list every public input once, in declaration order, with
+// its value in decimal: their JSON encoding is the same for the same
+// statement.
+type PublicInputs struct {
+	Circuit string        `json:"circuit"`
+	Inputs  []PublicInput `json:"inputs"`
+}
+
+// PublicInput is one named public input. Value is a decimal integer, or hex
+// with a 0x prefix; canonical inputs hold it in decimal.
+type PublicInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PublicSchema returns the names of the public inputs of a circuit, in the
+// order its public witness holds them. Slices count one input per element,
+// named like Mins_0, Mins_1 …
+func PublicSchema(definition Definition) ([]string, error) {
+	var names []string
+	err := walkPublic(definition, func(name string, _ reflect.Value) error {
+		names = append(names, name)
+		return nil
+	})
+	return names, err
+}
+
+// PublicInputsOf names the public values of an assignment of definition,
+// such as one returned by a PublicAssignment method.
+func PublicInputsOf(definition Definition, assignment frontend.Circuit) (PublicInputs, error) {
+	inputs := PublicInputs{Circuit: definition.ID()}
+	err := walkPublic(assignment, func(name string, value reflect.Value) error {
+		v := value.Interface()
+		if v == nil {
+			return fmt.Errorf("%w: %s is not assigned", ErrPublicInputs, name)
+		}
+		x, err := toBig(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		inputs.Inputs = append(inputs.Inputs, PublicInput{Name: name, Value: x.String()})
+		return nil
+	})
+	if err != nil {
+		return PublicInputs{}, err
+	}
+	return inputs.Canonical(definition)
+}
+
+// ReadPublicInputs names the values of the public witness of a proof for
+// definition, as carried by an envelope.
+func ReadPublicInputs(definition Definition, public witness.Witness) (PublicInputs, error) {
+	names, err := PublicSchema(definition)
+	if err != nil {
+		return PublicInputs{}, err
+	}
+	values, err := publicValues(public)
+	if err != nil {
+		return PublicInputs{}, err
+	}
+	if len(values) != len(names) {
+		return PublicInputs{}, fmt.Errorf("%w: the witness has %d public inputs, %s has %d", ErrPublicInputs, len(values), definition.ID(), len(names))
+	}
+	inputs := PublicInputs{Circuit: definition.ID(), Inputs: make([]PublicInput, len(names))}
+	for i, name := range names {
+		inputs.Inputs[i] = PublicInput{Name: name, Value: values[i].String()}
+	}
+	return inputs, nil
+}
+
+// Canonical returns the inputs in the order of the schema of definition,
+// with decimal values. It fails, wrapping ErrPublicInputs, if the inputs are
+// for another circuit, or if any input is missing, unknown, given twice or
+// not an integer.
+func (p PublicInputs) Canonical(definition Definition) (PublicInputs, error) {
+	if p.Circuit != definition.ID() {
+		return PublicInputs{}, fmt.Errorf("%w: inputs are for %q, not %q", ErrPublicInputs, p.Circuit, definition.ID())
+	}
+	names, err := PublicSchema(definition)
+	if err != nil {
+		return PublicInputs{}, err
+	}
+	byName := make(map[string]*big.Int, len(p.Inputs))
+	for _, in := range p.Inputs {
+		if _, ok := byName[in.Name]; ok {
+			return PublicInputs{}, fmt.Errorf("%w: %s is given twice", ErrPublicInputs, in.Name)
+		}
+		x, ok := parseValue(in.Value)
+		if !ok {
+			return PublicInputs{}, fmt.Errorf("%w: %s = %q is not an integer", ErrPublicInputs, in.Name, in.Value)
+		}
+		byName[in.Name] = x
+	}
+	canonical := PublicInputs{Circuit: p.Circuit, Inputs: make([]PublicInput, len(names))}
+	for i, name := range names {
+		x, ok := byName[name]
+		if !ok {
+			return PublicInputs{}, fmt.Errorf("%w: %s is missing (%s takes %v)", ErrPublicInputs, name, definition.ID(), names)
+		}
+		delete(byName, name)
+		canonical.Inputs[i] = PublicInput{Name: name, Value: x.String()}
+	}
+	for name := range byName {
+		return PublicInputs{}, fmt.Errorf("%w: %s has no public input %s (it takes %v)", ErrPublicInputs, definition.ID(), name, names)
+	}
+	return canonical, nil
+}
+
+// Values returns the values in order, for canonical inputs.
+func (p PublicInputs) Values() []*big.Int {
+	values := make([]*big.Int, len(p.Inputs))
+	for i, in := range p.Inputs {
+		values[i], _ = parseValue(in.Value)
+	}
+	return values
+}
+
+// ParsePublicInputs decodes inputs from JSON.
+func ParsePublicInputs(data []byte) (PublicInputs, error) {
+	var p PublicInputs
+	if err := json.Unmarshal(data, &p); err != nil {
+		return PublicInputs{}, fmt.Errorf("%w: %v", ErrPublicInputs, err)
+	}
+	return p, nil
+}
+
+// parseValue parses a decimal value, or a hex one prefixed by 0x.
+func parseValue(s string) (*big.Int, bool) {
+	if hex, ok := strings.CutPrefix(strings.ToLower(s), "0x"); ok {
+		return new(big.Int).SetString(hex, 16)
+	}
+	return new(big.Int).SetString(s, 10)
+}
+
+// walkPublic calls f with the name and value of every public input of c, in
+// witness order.
+func walkPublic(c frontend.Circuit, f func(name string, value reflect.Value) error) error {
+	tVariable := reflect.TypeOf((*frontend.Variable)(nil)).Elem()
+	_, err := schema.Walk(c, tVariable, func(leaf schema.LeafInfo, value reflect.Value) error {
+		if leaf.Visibility != schema.Public {
+			return nil
+		}
+		return f(leaf.FullName(), value)
+	})
+	return err
+}
@@ -0,0 +1,100 @@
+package circuit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func rangeInputs(inputs ...string) PublicInputs {
+	p := PublicInputs{Circuit: "range/16"}
+	for i := 0; i < len(inputs); i += 2 {
+		p.Inputs = append(p.Inputs, PublicInput{Name: inputs[i], Value: inputs[i+1]})
+	}
+	return p
+}
+
+func TestPublicSchema(t *testing.T) {
+	c, err := NewRangeCircuit(DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := PublicSchema(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Min", "Max", "Challenge", "Domain"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("PublicSchema(range) = %v, want %v", names, want)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	c, err := NewRangeCircuit(DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := rangeInputs("Min", "18", "Max", "30", "Challenge", "255", "Domain", "0")
+	for _, tc := range []struct {
+		name   string
+		inputs PublicInputs
+	}{
+		{"in order", want},
+		{"reordered", rangeInputs("Domain", "0", "Challenge", "255", "Max", "30", "Min", "18")},
+		{"hex values", rangeInputs("Min", "0x12", "Max", "0X1e", "Challenge", "0xff", "Domain", "0")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.inputs.Canonical(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Canonical = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCanonicalMismatch(t *testing.T) {
+	c, err := NewRangeCircuit(DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := rangeInputs("Min", "18", "Max", "30", "Challenge", "0", "Domain", "0")
+	other.Circuit = "range/8"
+	for _, tc := range []struct {
+		name   string
+		inputs PublicInputs
+	}{
+		{"other circuit", other},
+		{"missing", rangeInputs("Min", "18", "Max", "30", "Challenge", "0")},
+		{"unknown", rangeInputs("Min", "18", "Max", "30", "Challenge", "0", "Domain", "0", "Age", "25")},
+		{"given twice", rangeInputs("Min", "18", "Max", "30", "Min", "19", "Challenge", "0", "Domain", "0")},
+		{"wrong case", rangeInputs("min", "18", "Max", "30", "Challenge", "0", "Domain", "0")},
+		{"not an integer", rangeInputs("Min", "eighteen", "Max", "30", "Challenge", "0", "Domain", "0")},
+		{"empty value", rangeInputs("Min", "", "Max", "30", "Challenge", "0", "Domain", "0")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.inputs.Canonical(c); !errors.Is(err, ErrPublicInputs) {
+				t.Errorf("Canonical = %v, want ErrPublicInputs", err)
+			}
+		})
+	}
+}
+
+func TestPublicInputsOf(t *testing.T) {
+	c, err := NewRangeCircuit(DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := c.PublicAssignment(18, 30, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := PublicInputsOf(c, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := rangeInputs("Min", "18", "Max", "30", "Challenge", "0", "Domain", "0"); !reflect.DeepEqual(got, want) {
+		t.Errorf("PublicInputsOf = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,216 @@
+// Package circuit holds the gnark circuits proven by hello-zkp.
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+const (
+	// DefaultBits is the bit width used when nothing else is requested;
+	// plenty for realistic ages.
+	DefaultBits = 16
+
+	// MaxBits is the largest supported bit width. Every bounded value must
+	// fit in a non-negative Go int, and 2^MaxBits stays far below the scalar
+	// field order of every supported curve, so differences cannot wrap.
+	MaxBits = 62
+)
+
+var (
+	// ErrInvalidBits is returned when a bit width is outside [1, MaxBits].
+	ErrInvalidBits = errors.New("invalid bit width")
+
+	// ErrOutOfRange is returned when an input does not fit in the circuit's
+	// bit width.
+	ErrOutOfRange = errors.New("value out of range")
+)
+
+// BoundsError is returned when the public bounds are inconsistent, i.e.
+// Min > Max. No Age can satisfy such a statement, so it is rejected before
+// any proving work instead of leaving the verifier to interpret it.
+type BoundsError struct {
+	Min, Max int
+}
+
+func (e *BoundsError) Error() string {
+	return fmt.Sprintf("invalid bounds: Min = %d is greater than Max = %d", e.Min, e.Max)
+}
+
+// RangeCircuit proves that Min ≤ Age ≤ Max without revealing Age.
+type RangeCircuit struct {
+	// Private input: the user's age
+	Age frontend.Variable `gnark:"age"`
+
+	// Public inputs: range bounds
+	Min frontend.Variable `gnark:",public"`
+	Max frontend.Variable `gnark:",public"`
+
+	// Public input: verifier-issued nonce the proof is bound to, so that a
+	// proof presented to one verifier cannot be replayed to another. Zero
+	// means "not bound to any challenge".
+	Challenge frontend.Variable `gnark:",public"`
+
+	// Public input: hash of the application context the proof was made for
+	// (see package domain), so that a proof made for one relying party is
+	// rejected by another asking for the same bounds. Zero means "no domain".
+	Domain frontend.Variable `gnark:",public"`
+
+	// bits is the width every value is bounded to. It is part of the circuit
+	// shape, not of the witness, so it is unexported and ignored by gnark.
+	bits int
+}
+
+// NewRangeCircuit returns a circuit definition bounding all values to the
+// given number of bits.
+func NewRangeCircuit(bits int) (*RangeCircuit, error) {
+	if err := validateBits(bits); err != nil {
+		return nil, err
+	}
+	return &RangeCircuit{bits: bits}, nil
+}
+
+// Bits returns the bit width the circuit was built with.
+func (c *RangeCircuit) Bits() int {
+	return c.bits
+}
+
+// ID identifies the circuit shape. Proofs and keys are only interchangeable
+// between circuits with the same ID.
+func (c *RangeCircuit) ID() string {
+	return fmt.Sprintf("range/%d", c.bits)
+}
+
+// Assign validates the inputs against the circuit's bit width and returns the
+// matching witness assignment. The assignment is not bound to a challenge;
+// use WithChallenge for that. It is AgeWitness.Build without a challenge.
+func (c *RangeCircuit) Assign(age, min, max int) (*RangeCircuit, error) {
+	return AgeWitness{Age: age, Min: min, Max: max}.Build(c, nil)
+}
+
+// PublicAssignment returns an assignment of the public inputs only, as a
+// verifier would build it from its own policy and the challenge it issued.
+// A nil challenge stands for zero.
+func (c *RangeCircuit) PublicAssignment(min, max int, challenge *big.Int) (*RangeCircuit, error) {
+	if err := checkBounds(min, max, c.bits); err != nil {
+		return nil, err
+	}
+	return &RangeCircuit{Min: min, Max: max, Challenge: challengeOrZero(challenge), Domain: 0, bits: c.bits}, nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *RangeCircuit) WithChallenge(challenge *big.Int) *RangeCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// WithDomain returns a copy of the assignment bound to the given domain
+// hash, as returned by domain.Hash (nil for none).
+func (c *RangeCircuit) WithDomain(domain *big.Int) *RangeCircuit {
+	bound := *c
+	bound.Domain = challengeOrZero(domain)
+	return &bound
+}
+
+// ReadDomain returns the domain hash held by the public witness of a
+// RangeCircuit proof, zero if it was made for no domain.
+func (c *RangeCircuit) ReadDomain(public witness.Witness) (*big.Int, error) {
+	values, err := publicValues(public)
+	if err != nil {
+		return nil, err
+	}
+	// Public inputs in declaration order: Min, Max, Challenge, Domain
+	if len(values) != 4 {
+		return nil, fmt.Errorf("%d public inputs, %s has 4", len(values), c.ID())
+	}
+	return values[3], nil
+}
+
+// Define: enforce Min ≤ Age ≤ Max
+func (c *RangeCircuit) Define(api frontend.API) error {
+	if err := defineRange(api, c.Age, c.Min, c.Max, c.Challenge, c.bits); err != nil {
+		return err
+	}
+	// Bind the domain, as defineRange binds the challenge.
+	api.Mul(c.Domain, c.Domain)
+	return nil
+}
+
+// defineRange holds the constraints shared by every range statement.
+func defineRange(api frontend.API, age, min, max, challenge frontend.Variable, bits int) error {
+	if err := validateBits(bits); err != nil {
+		return err
+	}
+
+	gadgets.AssertInRange(api, age, min, max, bits)
+
+	// Max - Min ≥ 0: the public bounds must be consistent on their own,
+	// independently of the private Age.
+	gadgets.AssertLessOrEqualBounded(api, min, max, bits)
+
+	// Challenge takes no part in the statement, but Groth16 does not bind a
+	// public input that appears in no constraint: the proof would verify for
+	// any value. Squaring it puts the wire into a constraint.
+	api.Mul(challenge, challenge)
+
+	return nil
+}
+
+func challengeOrZero(challenge *big.Int) frontend.Variable {
+	if challenge == nil {
+		return 0
+	}
+	return challenge
+}
+
+func validateBits(bits int) error {
+	if bits < 1 || bits > MaxBits {
+		return fmt.Errorf("%w: %d (must be between 1 and %d)", ErrInvalidBits, bits, MaxBits)
+	}
+	return nil
+}
+
+// checkBounds validates the public bounds of a range statement.
+func checkBounds(min, max, bits int) error {
+	if err := checkFits("Min", min, bits); err != nil {
+		return err
+	}
+	if err := checkFits("Max", max, bits); err != nil {
+		return err
+	}
+	if min > max {
+		return &BoundsError{Min: min, Max: max}
+	}
+	return nil
+}
+
+// checkPrivateFits is checkFits for private inputs. Its errors leave the
+// value out, since they end up on the terminal and in logs.
+func checkPrivateFits(name string, v, bits int) error {
+	if v < 0 {
+		return fmt.Errorf("%w: private %s is negative", ErrOutOfRange, name)
+	}
+	if v >= 1<<bits {
+		return fmt.Errorf("%w: private %s does not fit in %d bits (max %d)", ErrOutOfRange, name, bits, 1<<bits-1)
+	}
+	return nil
+}
+
+// checkFits rejects values outside [0, 2^bits) before they reach the circuit,
+// where they would only surface as an opaque unsatisfied constraint.
+func checkFits(name string, v, bits int) error {
+	if v < 0 {
+		return fmt.Errorf("%w: %s = %d is negative", ErrOutOfRange, name, v)
+	}
+	if v >= 1<<bits {
+		return fmt.Errorf("%w: %s = %d does not fit in %d bits (max %d)", ErrOutOfRange, name, v, bits, 1<<bits-1)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package circuit
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+// fuzzBits keeps the fuzzed values close to the edges of the range.
+const fuzzBits = 8
+
+// FuzzRangeStatement checks that the circuit accepts (age, min, max) iff
+// 0 ≤ min ≤ age ≤ max < 2^bits, for raw assignments that skip the checks of
+// Assign, so negative values reach the circuit as field elements near p.
+func FuzzRangeStatement(f *testing.F) {
+	for _, seed := range [][3]int64{
+		{25, 18, 30}, {18, 18, 30}, {30, 18, 30}, {17, 18, 30}, {31, 18, 30},
+		{0, 0, 0}, {255, 0, 255}, {256, 0, 255}, {-1, 0, 30}, {5, -1, 30}, {20, 30, 18},
+	} {
+		f.Add(seed[0], seed[1], seed[2])
+	}
+	definition := &RangeCircuit{bits: fuzzBits}
+	f.Fuzz(func(t *testing.T, age, min, max int64) {
+		inRange := func(v int64) bool { return v >= 0 && v < 1<<fuzzBits }
+		want := inRange(age) && inRange(min) && inRange(max) && min <= age && age <= max
+		assignment := &RangeCircuit{Age: age, Min: min, Max: max, Challenge: 0, Domain: 0, bits: fuzzBits}
+		err := test.IsSolved(definition, assignment, ecc.BN254.ScalarField())
+		if (err == nil) != want {
+			t.Fatalf("Min=%d Age=%d Max=%d: solved = %v, want %t", min, age, max, err, want)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package circuit
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/test"
+)
+
+var testCurves = []ecc.ID{ecc.BN254, ecc.BLS12_381}
+
+// rawRange assigns the range circuit without the checks of Assign, the way
+// a malicious prover would.
+func rawRange(age, min, max any) *RangeCircuit {
+	return &RangeCircuit{Age: age, Min: min, Max: max, Challenge: 0, Domain: 0, bits: DefaultBits}
+}
+
+func TestRangeCircuitSolved(t *testing.T) {
+	top := 1<<DefaultBits - 1
+	for _, tc := range []struct {
+		name          string
+		age, min, max any
+		ok            bool
+	}{
+		{"inside", 25, 18, 30, true},
+		{"age == min", 18, 18, 30, true},
+		{"age == max", 30, 18, 30, true},
+		{"min == max", 21, 21, 21, true},
+		{"whole width", top, 0, top, true},
+		{"below min", 17, 18, 30, false},
+		{"above max", 31, 18, 30, false},
+		{"age overflows bits", top + 1, 0, top, false},
+		{"max overflows bits", 5, 0, top + 1, false},
+		{"min above max", 20, 30, 18, false},
+	} {
+		for _, curve := range testCurves {
+			t.Run(tc.name+"/"+curve.String(), func(t *testing.T) {
+				err := test.IsSolved(&RangeCircuit{bits: DefaultBits}, rawRange(tc.age, tc.min, tc.max), curve.ScalarField())
+				if (err == nil) != tc.ok {
+					t.Errorf("Min=%v Age=%v Max=%v: solved = %v, want %t", tc.min, tc.age, tc.max, err, tc.ok)
+				}
+			})
+		}
+	}
+}
+
+func TestRangeCircuitNegativeAge(t *testing.T) {
+	for _, curve := range testCurves {
+		// -1 in the field is p - 1: a huge value, not a small negative one.
+		minusOne := new(big.Int).Sub(curve.ScalarField(), big.NewInt(1))
+		if err := test.IsSolved(&RangeCircuit{bits: DefaultBits}, rawRange(minusOne, 0, 30), curve.ScalarField()); err == nil {
+			t.Errorf("%s: Age = -1 satisfies 0 ≤ Age ≤ 30", curve)
+		}
+	}
+}
+
+func TestRangeCircuitAssignRejects(t *testing.T) {
+	c, err := NewRangeCircuit(DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name          string
+		age, min, max int
+		want          error
+	}{
+		{"negative age", -1, 0, 30, ErrOutOfRange},
+		{"overflowing age", 1 << DefaultBits, 0, 30, ErrOutOfRange},
+		{"negative min", 5, -1, 30, ErrOutOfRange},
+		{"overflowing max", 5, 0, 1 << DefaultBits, ErrOutOfRange},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := c.Assign(tc.age, tc.min, tc.max); !errors.Is(err, tc.want) {
+				t.Errorf("Assign(%d, %d, %d) = %v, want %v", tc.age, tc.min, tc.max, err, tc.want)
+			}
+		})
+	}
+	var bounds *BoundsError
+	if _, err := c.Assign(20, 30, 18); !errors.As(err, &bounds) {
+		t.Errorf("Assign with Min > Max = %v, want a BoundsError", err)
+	}
+}
+
+// TestRangeCircuitCheck compiles the circuit for both curves and solves it;
+// with -tags prover_checks, gnark also proves and verifies with Groth16.
+func TestRangeCircuitCheck(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&RangeCircuit{bits: DefaultBits},
+		test.WithValidAssignment(rawRange(25, 18, 30)),
+		test.WithValidAssignment(rawRange(18, 18, 30)),
+		test.WithValidAssignment(rawRange(30, 18, 30)),
+		test.WithInvalidAssignment(rawRange(17, 18, 30)),
+		test.WithInvalidAssignment(rawRange(31, 18, 30)),
+		test.WithCurves(ecc.BN254, ecc.BLS12_381),
+		test.WithBackends(backend.GROTH16),
+		test.NoFuzzing(),
+	)
+}
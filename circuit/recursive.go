@@ -0,0 +1,100 @@
+package circuit
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+)
+
+// The recursive circuit verifies an inner Groth16 proof in an outer one. On
+// a 2-chain the scalar field of the outer curve is the base field of the
+// inner one, so the inner pairing is native arithmetic rather than emulated
+// field arithmetic: BLS12-377 inside BW6-761 costs tens of thousands of
+// constraints where BN254 inside BN254 would cost millions.
+var (
+	InnerCurve = ecc.BLS12_377
+	OuterCurve = ecc.BW6_761
+)
+
+// RecursiveRangeCircuit proves "I hold a valid range proof": a Groth16 proof
+// of a RangeCircuit on InnerCurve that verifies against a fixed verifying
+// key, for the public Min, Max, Challenge and Domain (in that order) exposed again
+// as the outer public inputs. The inner proof itself stays private, so the
+// outer proof can be shown without the inner one.
+type RecursiveRangeCircuit struct {
+	// Private input: the inner proof
+	Proof stdgroth16.Proof[sw_bls12377.G1Affine, sw_bls12377.G2Affine]
+
+	// Public inputs: the inner public inputs
+	Inner stdgroth16.Witness[sw_bls12377.ScalarField] `gnark:",public"`
+
+	// The inner verifying key is compiled into the circuit, so the outer
+	// setup is bound to one inner setup.
+	vk stdgroth16.VerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT] `gnark:"-"`
+	id string
+}
+
+// NewRecursiveRangeCircuit returns the outer circuit for proofs of inner, a
+// RangeCircuit compiled on InnerCurve, under the inner verifying key vk.
+func NewRecursiveRangeCircuit(inner *RangeCircuit, innerCcs constraint.ConstraintSystem, vk groth16.VerifyingKey) (*RecursiveRangeCircuit, error) {
+	if vk.CurveID() != InnerCurve {
+		return nil, fmt.Errorf("inner verifying key is on %s, recursion needs %s", vk.CurveID(), InnerCurve)
+	}
+	fixed, err := stdgroth16.ValueOfVerifyingKeyFixed[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](vk)
+	if err != nil {
+		return nil, err
+	}
+	return &RecursiveRangeCircuit{
+		Inner: stdgroth16.PlaceholderWitness[sw_bls12377.ScalarField](innerCcs),
+		vk:    fixed,
+		id:    "recursive/" + inner.ID(),
+	}, nil
+}
+
+// ID identifies the circuit shape. The inner verifying key is part of the
+// compiled circuit too, so key caches keyed on the constraint system tell
+// inner setups apart.
+func (c *RecursiveRangeCircuit) ID() string {
+	return c.id
+}
+
+// Assign returns the witness assignment for an inner proof and its public
+// witness.
+func (c *RecursiveRangeCircuit) Assign(proof groth16.Proof, public witness.Witness) (*RecursiveRangeCircuit, error) {
+	p, err := stdgroth16.ValueOfProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](proof)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(public)
+	if err != nil {
+		return nil, err
+	}
+	assignment.Proof = p
+	return assignment, nil
+}
+
+// PublicAssignment returns an assignment of the public inputs only, from
+// the inner public witness the verifier expects.
+func (c *RecursiveRangeCircuit) PublicAssignment(public witness.Witness) (*RecursiveRangeCircuit, error) {
+	w, err := stdgroth16.ValueOfWitness[sw_bls12377.ScalarField](public)
+	if err != nil {
+		return nil, err
+	}
+	return &RecursiveRangeCircuit{Inner: w, vk: c.vk, id: c.id}, nil
+}
+
+// Define: enforce that Proof verifies against the fixed inner key for the
+// public inputs Inner
+func (c *RecursiveRangeCircuit) Define(api frontend.API) error {
+	verifier, err := stdgroth16.NewVerifier[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](api)
+	if err != nil {
+		return err
+	}
+	return verifier.AssertProof(c.vk, c.Proof, c.Inner)
+}
@@ -0,0 +1,138 @@
+package circuit
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrUnknownCircuit is returned when a circuit name is not registered.
+var ErrUnknownCircuit = errors.New("unknown circuit")
+
+// Definition is a circuit shape that can be compiled, set up and identified.
+type Definition interface {
+	frontend.Circuit
+	ID() string
+}
+
+// registered is a circuit as known to the command line: its constructor,
+// and the parameters it takes beyond Bits, with their default and limit. A
+// zero default means the circuit does not take the parameter.
+type registered struct {
+	build           func(s StatementSpec) (Definition, error)
+	slots, maxSlots int
+	depth, maxDepth int
+	hashes          bool
+}
+
+// registry maps circuit names, as used on the command line, to circuits.
+var registry = map[string]registered{
+	"range": {build: func(s StatementSpec) (Definition, error) {
+		c, err := NewRangeCircuit(s.Bits)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"hashed-range": {hashes: true, build: func(s StatementSpec) (Definition, error) {
+		c, err := NewHashedRangeCircuit(s.Bits)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"committed-range": {hashes: true, build: func(s StatementSpec) (Definition, error) {
+		c, err := NewCommittedRangeCircuit(s.Bits)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"policy-range": {hashes: true, build: func(s StatementSpec) (Definition, error) {
+		c, err := NewPolicyRangeCircuit(s.Bits)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"any-range": {slots: DefaultRangeSlots, maxSlots: MaxRangeSlots, build: func(s StatementSpec) (Definition, error) {
+		c, err := NewAnyRangeCircuit(s.Bits, s.Slots)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"age-bracket": {slots: DefaultBracketEdges, maxSlots: MaxBracketEdges, build: func(s StatementSpec) (Definition, error) {
+		c, err := NewBracketCircuit(s.Bits, s.Slots)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"age-months": {build: func(s StatementSpec) (Definition, error) {
+		c, err := NewMonthsCircuit(s.Bits)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"expiring-range": {build: func(s StatementSpec) (Definition, error) {
+		c, err := NewExpiringRangeCircuit(s.Bits)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"credential": {slots: DefaultCountrySlots, maxSlots: MaxCountrySlots, hashes: true, build: func(s StatementSpec) (Definition, error) {
+		c, err := NewCredentialCircuit(s.Bits, s.Slots)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+	"non-membership": {depth: DefaultBlocklistDepth, maxDepth: MaxBlocklistDepth, hashes: true, build: func(s StatementSpec) (Definition, error) {
+		c, err := NewNonMembershipCircuit(s.Bits, s.Depth)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}},
+}
+
+// New returns the definition of the named circuit, bounding values to bits.
+// The name may carry further parameters (see SpecFor). A name starting with
+// ExprPrefix is a statement of the expr language, compiled on the fly.
+func New(name string, bits int) (Definition, error) {
+	spec, err := SpecFor(name, bits)
+	if err != nil {
+		return nil, err
+	}
+	return spec.Build()
+}
+
+// SpecFor parses a circuit name as given to -circuit (see ParseSpec) into a
+// spec bounding values to bits, unless the name sets bits itself.
+func SpecFor(name string, bits int) (StatementSpec, error) {
+	spec, err := ParseSpec(name)
+	if err != nil {
+		return StatementSpec{}, err
+	}
+	if spec.Bits == 0 {
+		if err := validateBits(bits); err != nil {
+			return StatementSpec{}, err
+		}
+		spec.Bits = bits
+	}
+	return spec.Normalize()
+}
+
+// Names lists the registered circuits.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
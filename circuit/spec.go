@@ -0,0 +1,179 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ananthanir/hello-zkp/commitment"
+)
+
+// Limits on the runtime parameters of a StatementSpec. Constraint counts,
+// and with them setup time and proving key size, grow linearly with each;
+// the limits keep a spec taken from a command line or a request from asking
+// for a circuit that takes hours to set up.
+const (
+	MaxRangeSlots     = 64
+	MaxCountrySlots   = 256
+	MaxBracketEdges   = 64
+	MaxBlocklistDepth = commitment.MaxDepth
+)
+
+// Poseidon2 is the hash every hashing circuit uses, and so far the only Hash
+// a StatementSpec can name.
+const Poseidon2 = "poseidon2"
+
+// ErrSpec is returned for a StatementSpec with an unknown parameter, a
+// parameter the circuit does not take, or a value outside its limits.
+var ErrSpec = errors.New("invalid statement spec")
+
+// StatementSpec names a circuit and the parameters of its shape, so the
+// shape can be chosen at runtime instead of in Go source. Zero fields take
+// the defaults of the registered circuit. Two specs with the same Key build
+// the same circuit, and compile to the same constraint system.
+type StatementSpec struct {
+	// Circuit is a registered name, or ExprPrefix followed by a statement.
+	Circuit string `json:"circuit"`
+
+	// Bits is the bit width of every bounded value (DefaultBits).
+	Bits int `json:"bits,omitempty"`
+
+	// Slots is the number of ranges of any-range (DefaultRangeSlots), of
+	// allowed countries of credential (DefaultCountrySlots) or of bracket
+	// edges of age-bracket (DefaultBracketEdges).
+	Slots int `json:"slots,omitempty"`
+
+	// Depth is the blocklist Merkle depth of non-membership
+	// (DefaultBlocklistDepth).
+	Depth int `json:"depth,omitempty"`
+
+	// Hash is the hash of the circuits that commit or hash (Poseidon2).
+	Hash string `json:"hash,omitempty"`
+}
+
+// ParseSpec parses a circuit name with optional parameters in URL query
+// form, as taken by -circuit:
+//
+//	any-range?slots=8&bits=8
+//	non-membership?depth=16
+//
+// An expr: statement is taken whole, with no parameters.
+func ParseSpec(s string) (StatementSpec, error) {
+	if strings.HasPrefix(s, ExprPrefix) {
+		return StatementSpec{Circuit: s}, nil
+	}
+	name, query, _ := strings.Cut(s, "?")
+	spec := StatementSpec{Circuit: name}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return StatementSpec{}, fmt.Errorf("%w: %q: %v", ErrSpec, s, err)
+	}
+	for key, v := range values {
+		value := v[len(v)-1]
+		var field *int
+		switch key {
+		case "bits":
+			field = &spec.Bits
+		case "slots":
+			field = &spec.Slots
+		case "depth":
+			field = &spec.Depth
+		case "hash":
+			spec.Hash = value
+			continue
+		default:
+			return StatementSpec{}, fmt.Errorf("%w: %q: unknown parameter %q (bits, slots, depth or hash)", ErrSpec, s, key)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return StatementSpec{}, fmt.Errorf("%w: %q: %s must be a positive integer", ErrSpec, s, key)
+		}
+		*field = n
+	}
+	return spec, nil
+}
+
+// Normalize fills in the defaults and checks every parameter against the
+// circuit and the limits.
+func (s StatementSpec) Normalize() (StatementSpec, error) {
+	if s.Bits == 0 {
+		s.Bits = DefaultBits
+	}
+	if err := validateBits(s.Bits); err != nil {
+		return StatementSpec{}, err
+	}
+	if strings.HasPrefix(s.Circuit, ExprPrefix) {
+		if s.Slots != 0 || s.Depth != 0 || s.Hash != "" {
+			return StatementSpec{}, fmt.Errorf("%w: expr statements take only bits", ErrSpec)
+		}
+		return s, nil
+	}
+	p, ok := registry[s.Circuit]
+	if !ok {
+		return StatementSpec{}, fmt.Errorf("%w: %q (known: %v, or %s<statement>)", ErrUnknownCircuit, s.Circuit, Names(), ExprPrefix)
+	}
+	var err error
+	if s.Slots, err = param(s.Circuit, "slots", s.Slots, p.slots, p.maxSlots); err != nil {
+		return StatementSpec{}, err
+	}
+	if s.Depth, err = param(s.Circuit, "depth", s.Depth, p.depth, p.maxDepth); err != nil {
+		return StatementSpec{}, err
+	}
+	switch {
+	case !p.hashes && s.Hash != "":
+		return StatementSpec{}, fmt.Errorf("%w: %s hashes nothing, hash does not apply", ErrSpec, s.Circuit)
+	case p.hashes && s.Hash == "":
+		s.Hash = Poseidon2
+	case p.hashes && s.Hash != Poseidon2:
+		return StatementSpec{}, fmt.Errorf("%w: hash %q (only %s is supported)", ErrSpec, s.Hash, Poseidon2)
+	}
+	return s, nil
+}
+
+// param defaults and bounds one integer parameter; a zero default means the
+// circuit does not take it.
+func param(circuit, name string, v, def, limit int) (int, error) {
+	switch {
+	case def == 0 && v != 0:
+		return 0, fmt.Errorf("%w: %s does not apply to %s", ErrSpec, name, circuit)
+	case v == 0:
+		return def, nil
+	case v < 1 || v > limit:
+		return 0, fmt.Errorf("%w: %s = %d for %s (must be between 1 and %d)", ErrSpec, name, v, circuit, limit)
+	}
+	return v, nil
+}
+
+// Key returns the canonical form of a normalized spec, with every
+// parameter spelled out: specs building the same circuit have the same key.
+func (s StatementSpec) Key() string {
+	key := fmt.Sprintf("%s?bits=%d", s.Circuit, s.Bits)
+	if s.Slots != 0 {
+		key += fmt.Sprintf("&slots=%d", s.Slots)
+	}
+	if s.Depth != 0 {
+		key += fmt.Sprintf("&depth=%d", s.Depth)
+	}
+	if s.Hash != "" {
+		key += "&hash=" + s.Hash
+	}
+	return key
+}
+
+// Build normalizes the spec and returns the definition of its circuit.
+func (s StatementSpec) Build() (Definition, error) {
+	s, err := s.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if src, ok := strings.CutPrefix(s.Circuit, ExprPrefix); ok {
+		c, err := NewExprCircuit(src, s.Bits)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return registry[s.Circuit].build(s)
+}
@@ -0,0 +1,278 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+var (
+	// ErrInvalidGrid is returned for a puzzle or solution that is not a 9×9
+	// grid of digits, or a solution that breaks the rules.
+	ErrInvalidGrid = errors.New("invalid sudoku grid")
+
+	// ErrNoSolution is returned by Solve for a puzzle with no solution.
+	ErrNoSolution = errors.New("sudoku has no solution")
+)
+
+// Grid is a 9×9 sudoku grid in row order. In a puzzle, 0 marks a blank cell.
+type Grid [81]int
+
+// ParseGrid reads a grid from text: 81 cells given as the digits 1 to 9,
+// with 0 or '.' for a blank. Whitespace and the separators '|', '-' and '+'
+// are skipped, so both a one-line puzzle and a drawn 9-line grid parse.
+func ParseGrid(text string) (Grid, error) {
+	var g Grid
+	n := 0
+	for _, r := range text {
+		switch {
+		case r == '.' || (r >= '0' && r <= '9'):
+			if n == len(g) {
+				return Grid{}, fmt.Errorf("%w: more than %d cells", ErrInvalidGrid, len(g))
+			}
+			if r != '.' {
+				g[n] = int(r - '0')
+			}
+			n++
+		case strings.ContainsRune(" \t\r\n|-+", r):
+		default:
+			return Grid{}, fmt.Errorf("%w: unexpected character %q", ErrInvalidGrid, r)
+		}
+	}
+	if n != len(g) {
+		return Grid{}, fmt.Errorf("%w: %d cells, expected %d", ErrInvalidGrid, n, len(g))
+	}
+	return g, nil
+}
+
+// String draws the grid over 9 lines, with '.' for blanks.
+func (g Grid) String() string {
+	var b strings.Builder
+	for row := 0; row < 9; row++ {
+		if row > 0 && row%3 == 0 {
+			b.WriteString("------+-------+------\n")
+		}
+		for col := 0; col < 9; col++ {
+			if col > 0 && col%3 == 0 {
+				b.WriteString("| ")
+			}
+			if v := g[row*9+col]; v == 0 {
+				b.WriteString(".")
+			} else {
+				b.WriteByte(byte('0' + v))
+			}
+			if col < 8 {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// sudokuGroups lists the cells of every row, column and 3×3 box: the 27
+// groups that must each hold the digits 1 to 9 once.
+var sudokuGroups = func() [][]int {
+	var groups [][]int
+	for i := 0; i < 9; i++ {
+		row, col, box := make([]int, 9), make([]int, 9), make([]int, 9)
+		for j := 0; j < 9; j++ {
+			row[j] = i*9 + j
+			col[j] = j*9 + i
+			box[j] = (i/3*3+j/3)*9 + i%3*3 + j%3
+		}
+		groups = append(groups, row, col, box)
+	}
+	return groups
+}()
+
+// sudokuPairs lists every pair of distinct cells sharing a group, once: 810
+// pairs, against 972 if the groups were taken one by one.
+var sudokuPairs = func() [][2]int {
+	seen := make(map[[2]int]bool)
+	var pairs [][2]int
+	for _, group := range sudokuGroups {
+		for a := 0; a < len(group); a++ {
+			for b := a + 1; b < len(group); b++ {
+				pair := [2]int{min(group[a], group[b]), max(group[a], group[b])}
+				if !seen[pair] {
+					seen[pair] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+	return pairs
+}()
+
+// CheckSolution checks off-circuit that solution fills puzzle by the rules,
+// so that a wrong solution is reported before any proving work. Solutions
+// are private: errors name the broken rule, not the digits.
+func (puzzle Grid) CheckSolution(solution Grid) error {
+	if err := puzzle.checkClues(); err != nil {
+		return err
+	}
+	for i, v := range solution {
+		if v < 1 || v > 9 {
+			return fmt.Errorf("%w: solution has a blank or non-digit cell", ErrInvalidGrid)
+		}
+		if puzzle[i] != 0 && puzzle[i] != v {
+			return fmt.Errorf("%w: solution does not match the clue at row %d, column %d", ErrInvalidGrid, i/9+1, i%9+1)
+		}
+	}
+	for _, pair := range sudokuPairs {
+		if solution[pair[0]] == solution[pair[1]] {
+			return fmt.Errorf("%w: solution repeats a digit in a row, column or box", ErrInvalidGrid)
+		}
+	}
+	return nil
+}
+
+// checkClues checks that every cell of a puzzle is blank or a digit.
+func (puzzle Grid) checkClues() error {
+	for i, v := range puzzle {
+		if v < 0 || v > 9 {
+			return fmt.Errorf("%w: clue %d at row %d, column %d", ErrInvalidGrid, v, i/9+1, i%9+1)
+		}
+	}
+	return nil
+}
+
+// Solve returns a solution of the puzzle by backtracking, the first one
+// found if there are several.
+func (puzzle Grid) Solve() (Grid, error) {
+	if err := puzzle.checkClues(); err != nil {
+		return Grid{}, err
+	}
+	g := puzzle
+	for _, pair := range sudokuPairs {
+		if g[pair[0]] != 0 && g[pair[0]] == g[pair[1]] {
+			return Grid{}, fmt.Errorf("%w: the clues repeat a digit", ErrNoSolution)
+		}
+	}
+	if !g.fill(0) {
+		return Grid{}, ErrNoSolution
+	}
+	return g, nil
+}
+
+// fill fills the blanks from cell i onwards, reporting whether it could.
+func (g *Grid) fill(i int) bool {
+	for i < len(g) && g[i] != 0 {
+		i++
+	}
+	if i == len(g) {
+		return true
+	}
+	row, col := i/9, i%9
+	for v := 1; v <= 9; v++ {
+		if g.allows(row, col, v) {
+			g[i] = v
+			if g.fill(i + 1) {
+				return true
+			}
+		}
+	}
+	g[i] = 0
+	return false
+}
+
+// allows reports whether v can go at row, col without a repeat.
+func (g *Grid) allows(row, col, v int) bool {
+	box := row/3*27 + col/3*3
+	for j := 0; j < 9; j++ {
+		if g[row*9+j] == v || g[j*9+col] == v || g[box+j/3*9+j%3] == v {
+			return false
+		}
+	}
+	return true
+}
+
+// SudokuCircuit proves "I know a solution of this sudoku" without revealing
+// it: the private solution holds a digit from 1 to 9 in every cell, no
+// digit twice in a row, column or box, and agrees with every public clue.
+type SudokuCircuit struct {
+	// Private input: the solution, in row order
+	Solution [81]frontend.Variable `gnark:"solution"`
+
+	// Public inputs: the puzzle, 0 for a blank cell, and the verifier
+	// challenge
+	Clues     [81]frontend.Variable `gnark:",public"`
+	Challenge frontend.Variable     `gnark:",public"`
+}
+
+// NewSudokuCircuit returns the circuit definition. Grids are always 9×9, so
+// there is no shape to choose.
+func NewSudokuCircuit() *SudokuCircuit {
+	return &SudokuCircuit{}
+}
+
+// ID identifies the circuit shape.
+func (c *SudokuCircuit) ID() string {
+	return "sudoku/9x9"
+}
+
+// Assign checks the solution against the puzzle and returns the witness
+// assignment, bound to no challenge; use WithChallenge for that.
+func (c *SudokuCircuit) Assign(puzzle, solution Grid) (*SudokuCircuit, error) {
+	if err := puzzle.CheckSolution(solution); err != nil {
+		return nil, err
+	}
+	assignment, err := c.PublicAssignment(puzzle, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range solution {
+		assignment.Solution[i] = v
+	}
+	return assignment, nil
+}
+
+// PublicAssignment returns the assignment a verifier builds from the puzzle
+// and its challenge (nil for none).
+func (c *SudokuCircuit) PublicAssignment(puzzle Grid, challenge *big.Int) (*SudokuCircuit, error) {
+	if err := puzzle.checkClues(); err != nil {
+		return nil, err
+	}
+	assignment := &SudokuCircuit{Challenge: challengeOrZero(challenge)}
+	for i, v := range puzzle {
+		assignment.Clues[i] = v
+	}
+	return assignment, nil
+}
+
+// WithChallenge returns a copy of the assignment bound to the given challenge.
+func (c *SudokuCircuit) WithChallenge(challenge *big.Int) *SudokuCircuit {
+	bound := *c
+	bound.Challenge = challengeOrZero(challenge)
+	return &bound
+}
+
+// Define: enforce that Solution is a valid grid that agrees with Clues
+func (c *SudokuCircuit) Define(api frontend.API) error {
+	for i, cell := range c.Solution {
+		// 1 ≤ cell ≤ 9, both bounds fitting in 4 bits
+		gadgets.AssertInRange(api, cell, 1, 9, 4)
+
+		// A clue is 0 or equal to the cell: Clue · (Cell − Clue) = 0. A
+		// clue that is neither cannot be satisfied, so the public grid
+		// needs no range check of its own.
+		api.AssertIsEqual(api.Mul(c.Clues[i], api.Sub(cell, c.Clues[i])), 0)
+	}
+
+	// With every cell in [1, 9], nine pairwise distinct cells in a group
+	// are exactly the digits 1 to 9. Each inequality costs one inverse.
+	for _, pair := range sudokuPairs {
+		api.AssertIsDifferent(c.Solution[pair[0]], c.Solution[pair[1]])
+	}
+
+	// Bind the challenge, as defineRange does.
+	api.Mul(c.Challenge, c.Challenge)
+
+	return nil
+}
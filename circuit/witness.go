@@ -0,0 +1,35 @@
+package circuit
+
+import "math/big"
+
+// AgeWitness holds the inputs of a range statement as plain integers, so they
+// can be validated with clear errors before they reach the circuit, where a
+// negative age or Min > Max would only surface as an unsatisfied constraint.
+type AgeWitness struct {
+	Age int `json:"age"`
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Validate checks that every value is non-negative and fits in bits, and
+// that Min ≤ Max. Errors wrap ErrInvalidBits or ErrOutOfRange, or are a
+// *BoundsError. It does not check Min ≤ Age ≤ Max: that is the statement
+// being proven.
+func (w AgeWitness) Validate(bits int) error {
+	if err := validateBits(bits); err != nil {
+		return err
+	}
+	if err := checkPrivateFits("Age", w.Age, bits); err != nil {
+		return err
+	}
+	return checkBounds(w.Min, w.Max, bits)
+}
+
+// Build validates the witness for c and returns its assignment, bound to the
+// given challenge (nil for none).
+func (w AgeWitness) Build(c *RangeCircuit, challenge *big.Int) (*RangeCircuit, error) {
+	if err := w.Validate(c.bits); err != nil {
+		return nil, err
+	}
+	return &RangeCircuit{Age: w.Age, Min: w.Min, Max: w.Max, Challenge: challengeOrZero(challenge), Domain: 0, bits: c.bits}, nil
+}
@@ -0,0 +1,300 @@
+// Command issuer is the issuing authority of the three-party model. It
+// checks a subject's age once, signs a credential the holder keeps, revokes
+// subjects and publishes its public key and revocation root for verifiers.
+//
+//	go run ./cmd/issuer keygen
+//	go run ./cmd/issuer issue -age 25 -subject 42 -out credential.json
+//	go run ./cmd/issuer revoke -subject 7
+//	go run ./cmd/issuer publish -out issuer.json
+//	go run ./cmd/issuer check -issuer issuer.json -claim claim.json
+//
+// The credential is also a commitment opening: the holder proves with
+// hello-zkp prove -circuit committed-range -opening credential.json. check
+// rejects a claim whose subject is in the publication's revocation list.
+//
+// It exits 0 on success, 1 if check rejected the claim, 2 for usage errors,
+// 3 for invalid input and 4 when a file cannot be read or written.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/commitment"
+	"github.com/ananthanir/hello-zkp/issuer"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+const usage = `usage: issuer <step> [flags]
+
+steps:
+  keygen   generate the issuer's signing key
+  issue    sign a credential for a subject's age
+  revoke   add subjects to, or remove them from, the revocation list
+  publish  write the public key and revocation root verifiers trust
+  check    verify a claim's signature and revocation against a publication (verifier)
+
+Run 'issuer <step> -h' for the flags of a step.
+`
+
+const (
+	exitRejected     = 1
+	exitUsage        = 2
+	exitInvalidInput = 3
+	exitIO           = 4
+)
+
+// File names inside the issuer directory.
+const (
+	keyFile         = "issuer.key"
+	revocationsFile = "revocations.json"
+)
+
+// steps maps each step to its implementation.
+var steps = map[string]func(args []string) error{
+	"keygen":  runKeygen,
+	"issue":   runIssue,
+	"revoke":  runRevoke,
+	"publish": runPublish,
+	"check":   runCheck,
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitUsage)
+	}
+	run, ok := steps[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown step %q\n\n%s", os.Args[1], usage)
+		os.Exit(exitUsage)
+	}
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "issuer %s: %v\n", os.Args[1], err)
+		switch {
+		case errors.Is(err, zkp.ErrVerificationFailed):
+			os.Exit(exitRejected)
+		case errors.Is(err, zkp.ErrIO):
+			os.Exit(exitIO)
+		default:
+			os.Exit(exitInvalidInput)
+		}
+	}
+}
+
+func runKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	dir := fs.String("dir", "issuer-state", "directory to keep the issuer's key and revocation list in")
+	force := fs.Bool("force", false, "replace an existing key (credentials it signed stop verifying)")
+	fs.Parse(args)
+
+	path := filepath.Join(*dir, keyFile)
+	if _, err := os.Stat(path); err == nil && !*force {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("%s exists (use -force to replace it)", path))
+	}
+	key, err := issuer.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	if err := key.Save(path); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	fmt.Printf("Issuer key: ✅ wrote %s\n", path)
+	fmt.Printf("Public key: %s\n", key.Public())
+	return nil
+}
+
+func runIssue(args []string) error {
+	fs := flag.NewFlagSet("issue", flag.ExitOnError)
+	dir := fs.String("dir", "issuer-state", "issuer directory")
+	age := fs.Int("age", 0, "the subject's age, as checked by the issuer")
+	subject := fs.Int("subject", 0, "numeric subject identifier")
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width the age and subject must fit in")
+	out := fs.String("out", "credential.json", "file to write the holder's credential to")
+	claim := fs.String("claim", "claim.json", "file to write the public claim to (empty: none)")
+	fs.Parse(args)
+
+	for _, v := range []struct {
+		name  string
+		value int
+	}{{"age", *age}, {"subject", *subject}} {
+		if v.value < 0 || *bits < 1 || *bits > circuit.MaxBits || v.value >= 1<<*bits {
+			return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("-%s %d does not fit in %d bits", v.name, v.value, *bits))
+		}
+	}
+	key, err := loadKey(*dir)
+	if err != nil {
+		return err
+	}
+	revoked, err := loadRevocations(*dir, *bits)
+	if err != nil {
+		return err
+	}
+	// A subject the list cannot hold could never be revoked.
+	if err := revoked.CheckID(*subject); err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("-subject: %w", err))
+	}
+	if revoked.Contains(*subject) {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("subject %d is revoked", *subject))
+	}
+
+	cred, err := key.Issue(*age, *subject)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	if err := cred.Save(*out); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	fmt.Printf("Credential: ✅ wrote %s for subject %d (keep it private)\n", *out, *subject)
+	fmt.Printf("Commitment: %s\n", commitment.Format(cred.Commitment))
+	if *claim != "" {
+		if err := cred.Claim().Save(*claim); err != nil {
+			return zkp.Wrap(zkp.ErrIO, err)
+		}
+		fmt.Printf("Claim: ✅ wrote %s (shown to verifiers)\n", *claim)
+	}
+	return nil
+}
+
+func runRevoke(args []string) error {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	dir := fs.String("dir", "issuer-state", "issuer directory")
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of subject identifiers, for a new list")
+	add := fs.String("subject", "", "comma-separated subjects to revoke")
+	remove := fs.String("restore", "", "comma-separated subjects to reinstate")
+	fs.Parse(args)
+
+	revoked, err := loadRevocations(*dir, *bits)
+	if err != nil {
+		return err
+	}
+	added, err := parseSubjects(*add)
+	if err != nil {
+		return err
+	}
+	removed, err := parseSubjects(*remove)
+	if err != nil {
+		return err
+	}
+	for _, s := range added {
+		if err := revoked.Add(s); err != nil {
+			return zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+	}
+	for _, s := range removed {
+		revoked.Remove(s)
+	}
+	path := filepath.Join(*dir, revocationsFile)
+	if err := revoked.Save(path); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	fmt.Printf("Revocation root: %s\n", commitment.Format(revoked.Root()))
+	fmt.Printf("%d subjects revoked, written to %s (run publish to update verifiers)\n", len(revoked.IDs), path)
+	return nil
+}
+
+func runPublish(args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ExitOnError)
+	dir := fs.String("dir", "issuer-state", "issuer directory")
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of subject identifiers, for a new list")
+	out := fs.String("out", "issuer.json", "file to write the publication to")
+	fs.Parse(args)
+
+	key, err := loadKey(*dir)
+	if err != nil {
+		return err
+	}
+	revoked, err := loadRevocations(*dir, *bits)
+	if err != nil {
+		return err
+	}
+	pub := key.Publish(revoked)
+	if err := pub.Save(*out); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	fmt.Printf("Publication: ✅ wrote %s\n", *out)
+	fmt.Printf("Public key:      %s\n", pub.PublicKey)
+	fmt.Printf("Revocation root: %s (%d revoked)\n", pub.RevocationRoot, pub.Revoked)
+	return nil
+}
+
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	in := fs.String("issuer", "issuer.json", "publication of the issuer to trust")
+	file := fs.String("claim", "claim.json", "claim (or credential) to check")
+	fs.Parse(args)
+
+	pub, err := issuer.LoadPublication(*in)
+	if err != nil {
+		return wrapLoad(err)
+	}
+	claim, err := issuer.LoadClaim(*file)
+	if err != nil {
+		return wrapLoad(err)
+	}
+	if err := claim.Verify(pub.PublicKey); err != nil {
+		fmt.Println("Claim: ❌ REJECTED")
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	if err := pub.CheckRevocation(claim.Subject); err != nil {
+		fmt.Println("Claim: ❌ REVOKED")
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	fmt.Printf("Claim: ✅ subject %d, commitment %s, signed by this issuer and not revoked\n", claim.Subject, commitment.Format(claim.Commitment))
+	fmt.Printf("Pin -commitment %s when verifying its proofs.\n", commitment.Format(claim.Commitment))
+	return nil
+}
+
+func loadKey(dir string) (*issuer.Key, error) {
+	key, err := issuer.LoadKey(filepath.Join(dir, keyFile))
+	if err != nil {
+		return nil, wrapLoad(err)
+	}
+	return key, nil
+}
+
+// loadRevocations reads the revocation list, or starts an empty one.
+func loadRevocations(dir string, bits int) (*commitment.Blocklist, error) {
+	list, err := commitment.LoadBlocklist(filepath.Join(dir, revocationsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		list, err = commitment.NewBlocklist(issuer.Curve, bits, circuit.DefaultBlocklistDepth)
+	}
+	if err != nil {
+		return nil, wrapLoad(err)
+	}
+	return list, nil
+}
+
+// wrapLoad classifies a failure to load a file.
+func wrapLoad(err error) error {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	return zkp.Wrap(zkp.ErrInvalidWitness, err)
+}
+
+// parseSubjects parses a comma-separated list of subject identifiers.
+func parseSubjects(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var ids []int
+	for _, field := range strings.Split(s, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,358 @@
+// Command prover is the holder's side of the two-process demo. It keeps the
+// private age and the proving key, listens on a socket and answers each
+// verifier request with a proof bound to the verifier's challenge.
+//
+//	AGE=25 go run ./cmd/prover -age-from env:AGE -keys keys -listen 127.0.0.1:7420
+//
+// -age-from reads the age from a secret reference (a file, an environment
+// variable or the OS keyring), which keeps it out of the process arguments
+// and shell history; -age is there for quick experiments.
+//
+// With -metrics it also serves Prometheus metrics on /metrics, and with
+// -pprof the net/http/pprof profiles under /debug/pprof/. With
+// -otlp-endpoint each request is traced, as a span with the witness and
+// prove spans as children, and with -audit-log every proof issued or
+// refused is recorded (see 'hello-zkp audit').
+//
+// The age never leaves this process. See cmd/verifier for the other side.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/ananthanir/hello-zkp/audit"
+	"github.com/ananthanir/hello-zkp/challenge"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/config"
+	"github.com/ananthanir/hello-zkp/domain"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/logging"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/secret"
+	"github.com/ananthanir/hello-zkp/session"
+	"github.com/ananthanir/hello-zkp/tracing"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// curve is the curve the CLI proves on.
+var curve = ecc.BN254
+
+// timeout bounds a whole exchange with one verifier.
+const timeout = 30 * time.Second
+
+// maxAbandoned is how many proofs, given up on when their exchange timed
+// out, may still be running before new requests are turned away: gnark
+// cannot stop them, so a verifier that keeps timing out would otherwise pile
+// them up.
+var maxAbandoned = int64(runtime.NumCPU())
+
+// maxProving is how many proofs run at once. Proving is CPU bound, so more
+// would only slow each other down; further requests wait for a slot.
+var maxProving = runtime.NumCPU()
+
+// errNotProvable is all a verifier is told when proving fails.
+var errNotProvable = errors.New("the statement does not hold for this holder")
+
+// errBusy turns requests away while too many timed-out proofs still run.
+var errBusy = errors.New("the prover is busy, try again later")
+
+// holder is everything the prover needs to answer requests.
+type holder struct {
+	age        int
+	definition *circuit.RangeCircuit
+	ccs        constraint.ConstraintSystem
+	pk         groth16.ProvingKey
+	vk         groth16.VerifyingKey
+	metrics    *metrics
+	audit      *audit.Log    // nil without -audit-log
+	slots      chan struct{} // one per proof allowed to run, see maxProving
+}
+
+func main() {
+	// Logs stay disabled unless the config file or -log-level sets a level
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "prover: %v\n", err)
+		os.Exit(2)
+	}
+
+	age := flag.Int("age", 0, "private age to prove statements about (visible to other local users: prefer -age-from)")
+	ageFrom := flag.String("age-from", "", "read the private age from "+secret.RefHelp+" instead of -age")
+	bits := flag.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	keys := flag.String("keys", "keys", "directory holding pk.bin and vk.bin")
+	listen := flag.String("listen", "127.0.0.1:7420", "address to listen on: host:port, or unix:<path>")
+	metricsAddr := flag.String("metrics", "", "host:port to serve /metrics on (empty: no metrics)")
+	withPprof := flag.Bool("pprof", false, "also serve net/http/pprof under /debug/pprof/ on the -metrics address")
+	auditLog := flag.String("audit-log", "", "append a record of every proof issued or refused to this audit log (empty: none)")
+	logs := logging.AddFlags(flag.CommandLine)
+	traces := tracing.AddFlags(flag.CommandLine)
+	if err := cfg.Apply(flag.CommandLine); err != nil {
+		fmt.Fprintf(os.Stderr, "prover: %v\n", err)
+		os.Exit(2)
+	}
+	flag.Parse()
+	if err := logs.Setup(); err != nil {
+		fmt.Fprintf(os.Stderr, "prover: %v\n", err)
+		os.Exit(2)
+	}
+	if err := traces.Setup("hello-zkp-prover"); err != nil {
+		fmt.Fprintf(os.Stderr, "prover: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *ageFrom != "" {
+		if *age, err = secret.ReadInt(*ageFrom); err != nil {
+			fmt.Fprintf(os.Stderr, "prover: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	if *withPprof && *metricsAddr == "" {
+		fmt.Fprintln(os.Stderr, "prover: -pprof needs -metrics")
+		os.Exit(2)
+	}
+	err = run(*age, *bits, *keys, *listen, *metricsAddr, *auditLog, *withPprof)
+	shutdown, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := tracing.Shutdown(shutdown); err != nil {
+		fmt.Fprintf(os.Stderr, "prover: tracing: %v\n", err)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "prover: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(age, bits int, keys, listen, metricsAddr, auditLog string, withPprof bool) error {
+	definition, err := circuit.NewRangeCircuit(bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	ccs, err := prover.Compile(curve, definition)
+	if err != nil {
+		return err
+	}
+	h := &holder{age: age, definition: definition, ccs: ccs,
+		pk: groth16.NewProvingKey(curve), vk: groth16.NewVerifyingKey(curve), metrics: newMetrics(),
+		slots: make(chan struct{}, maxProving)}
+	if err := keyfile.Read(filepath.Join(keys, "pk.bin"), h.pk); err != nil {
+		return err
+	}
+	if err := keyfile.Read(filepath.Join(keys, "vk.bin"), h.vk); err != nil {
+		return err
+	}
+	if auditLog != "" {
+		if h.audit, err = audit.Open(auditLog); err != nil {
+			return zkp.Wrap(zkp.ErrIO, err)
+		}
+		defer h.audit.Close()
+	}
+
+	l, err := session.Listen(listen)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	if metricsAddr != "" {
+		go func() {
+			if err := serveMetrics(metricsAddr, h.metrics, withPprof); err != nil {
+				fmt.Fprintf(os.Stderr, "prover: metrics: %v\n", err)
+			}
+		}()
+		fmt.Printf("Prover: metrics on http://%s/metrics\n", metricsAddr)
+	}
+	fmt.Printf("Prover: listening on %s for circuit %s\n", listen, definition.ID())
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return zkp.Wrap(zkp.ErrIO, err)
+		}
+		go h.serve(conn)
+	}
+}
+
+// serve answers a single verifier request.
+func (h *holder) serve(conn net.Conn) {
+	defer conn.Close()
+	// Proving stops being waited for when the exchange times out.
+	deadline := time.Now().Add(timeout)
+	conn.SetDeadline(deadline)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "request", "peer", conn.RemoteAddr().String())
+	var spanErr error
+	defer func() { span.End(spanErr) }()
+
+	var req session.Request
+	if err := session.Receive(conn, &req); err != nil {
+		spanErr = err
+		h.metrics.failed(failRejected)
+		fmt.Fprintf(os.Stderr, "%s: %v\n", conn.RemoteAddr(), err)
+		return
+	}
+	span.SetAttributes("circuit", req.Circuit, "min", req.Min, "max", req.Max)
+	nonce, err := h.check(req)
+	if err != nil {
+		spanErr = err
+		h.metrics.failed(failRejected)
+		fmt.Printf("%s: ❌ rejected request: %v\n", conn.RemoteAddr(), err)
+		session.Send(conn, session.Response{Error: err.Error()})
+		return
+	}
+	if zkp.Abandoned() >= maxAbandoned {
+		spanErr = errBusy
+		h.metrics.failed(failRejected)
+		fmt.Printf("%s: ❌ rejected request: %v\n", conn.RemoteAddr(), errBusy)
+		session.Send(conn, session.Response{Error: errBusy.Error()})
+		return
+	}
+	release, err := h.acquire(ctx)
+	if err != nil {
+		spanErr = errBusy
+		h.metrics.failed(failRejected)
+		fmt.Printf("%s: ❌ rejected request: %v\n", conn.RemoteAddr(), errBusy)
+		session.Send(conn, session.Response{Error: errBusy.Error()})
+		return
+	}
+	defer release()
+	start := time.Now()
+	env, err := h.prove(ctx, req, nonce)
+	// A proof that cannot be recorded is not issued.
+	if aerr := h.record(conn, req, nonce, env, err); aerr != nil {
+		spanErr = aerr
+		h.metrics.failed(failError)
+		fmt.Fprintf(os.Stderr, "%s: audit log: %v\n", conn.RemoteAddr(), aerr)
+		session.Send(conn, session.Response{Error: "the prover could not record the proof"})
+		return
+	}
+	if err != nil {
+		spanErr = err
+		if errors.Is(err, zkp.ErrInvalidWitness) {
+			h.metrics.failed(failRefused)
+		} else {
+			h.metrics.failed(failError)
+		}
+		// The details could reveal the age; the verifier only learns that
+		// the statement does not hold.
+		fmt.Printf("%s: ❌ refused %d ≤ Age ≤ %d: %v\n", conn.RemoteAddr(), req.Min, req.Max, err)
+		session.Send(conn, session.Response{Error: errNotProvable.Error()})
+		return
+	}
+	h.metrics.proved(time.Since(start))
+	fmt.Printf("%s: ✅ proved %d ≤ Age ≤ %d\n", conn.RemoteAddr(), req.Min, req.Max)
+	if err := session.Send(conn, session.Response{Envelope: env}); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", conn.RemoteAddr(), err)
+	}
+}
+
+// acquire waits for a proving slot, counting the request in the queue
+// until it gets one, and returns the func that frees the slot. It fails if
+// ctx ends first.
+func (h *holder) acquire(ctx context.Context) (func(), error) {
+	dequeue := h.metrics.enqueue()
+	defer dequeue()
+	select {
+	case h.slots <- struct{}{}:
+		return func() { <-h.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// record appends the outcome of proving to the audit log, if any. A
+// refused request is recorded with the public inputs it asked for.
+func (h *holder) record(conn net.Conn, req session.Request, nonce *big.Int, env *envelope.Envelope, err error) error {
+	if h.audit == nil {
+		return nil
+	}
+	var r audit.Record
+	if env != nil {
+		_, public, _ := env.Open(h.definition.ID(), h.vk)
+		r = audit.NewRecord(audit.Proved, h.definition, env, public, err)
+	} else {
+		r = audit.NewRecord(audit.Proved, h.definition, nil, nil, err)
+		r.Curve = curve.String()
+		if asked, aerr := h.definition.PublicAssignment(req.Min, req.Max, nonce); aerr == nil {
+			if inputs, aerr := circuit.PublicInputsOf(h.definition, asked.WithDomain(domain.Hash(req.Domain))); aerr == nil {
+				r.PublicInputs = inputs.Inputs
+			}
+		}
+	}
+	r.Peer = conn.RemoteAddr().String()
+	return h.audit.Append(r)
+}
+
+// check validates the public part of a request, which involves nothing
+// private, and returns its challenge.
+func (h *holder) check(req session.Request) (*big.Int, error) {
+	if req.Circuit != h.definition.ID() {
+		return nil, fmt.Errorf("circuit %q requested, this prover serves %q", req.Circuit, h.definition.ID())
+	}
+	nonce, err := challenge.Parse(req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	if nonce.Sign() == 0 {
+		return nil, errors.New("a non-zero challenge is required")
+	}
+	if _, err := h.definition.PublicAssignment(req.Min, req.Max, nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// prove builds the envelope answering a checked request.
+func (h *holder) prove(ctx context.Context, req session.Request, nonce *big.Int) (*envelope.Envelope, error) {
+	assignment, err := h.definition.Assign(h.age, req.Min, req.Max)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	witness, publicWitness, err := prover.NewWitnessContext(ctx, curve, assignment.WithChallenge(nonce).WithDomain(domain.Hash(req.Domain)))
+	if err != nil {
+		return nil, err
+	}
+	proof, err := prover.ProveContext(ctx, h.ccs, h.pk, witness)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.New(h.definition.ID(), h.vk, proof, publicWitness)
+}
+
+// loadConfig reads the config file, if any, and applies its curve and
+// logging. Logs go to stderr.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Find()
+	if err != nil {
+		return nil, err
+	}
+	if curve, err = cfg.CurveID(); err != nil {
+		return nil, err
+	}
+	if err := logging.Setup(cfg.LogLevel, cfg.LogFormat); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
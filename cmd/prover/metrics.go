@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Failure reasons, as the reason label of hello_zkp_failures_total.
+const (
+	failRejected = "rejected" // malformed or unsupported request
+	failRefused  = "refused"  // the statement does not hold: the verifier sees a failure
+	failError    = "error"    // proving or I/O failed
+)
+
+// proveBuckets are the upper bounds, in seconds, of the prove latency
+// histogram.
+var proveBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metrics holds the prover's counters, exposed in the Prometheus text format.
+// The handful of series needed does not warrant the client library.
+type metrics struct {
+	mu       sync.Mutex
+	proofs   uint64
+	failures map[string]uint64
+	queue    int64
+
+	buckets []uint64 // cumulative counts per proveBuckets bound
+	count   uint64
+	sum     float64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		failures: map[string]uint64{failRejected: 0, failRefused: 0, failError: 0},
+		buckets:  make([]uint64, len(proveBuckets)),
+	}
+}
+
+// enqueue counts a request as waiting for a proving slot; the returned func
+// takes it off the queue.
+func (m *metrics) enqueue() func() {
+	m.mu.Lock()
+	m.queue++
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		m.queue--
+		m.mu.Unlock()
+	}
+}
+
+// proved records a proof and how long it took.
+func (m *metrics) proved(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.proofs++
+	s := d.Seconds()
+	for i, bound := range proveBuckets {
+		if s <= bound {
+			m.buckets[i]++
+		}
+	}
+	m.count++
+	m.sum += s
+}
+
+// failed records a request that got no proof.
+func (m *metrics) failed(reason string) {
+	m.mu.Lock()
+	m.failures[reason]++
+	m.mu.Unlock()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.writeTo(w)
+}
+
+func (m *metrics) writeTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP hello_zkp_proofs_total Proofs sent to verifiers.")
+	fmt.Fprintln(w, "# TYPE hello_zkp_proofs_total counter")
+	fmt.Fprintf(w, "hello_zkp_proofs_total %d\n", m.proofs)
+
+	fmt.Fprintln(w, "# HELP hello_zkp_failures_total Requests answered without a proof, by reason.")
+	fmt.Fprintln(w, "# TYPE hello_zkp_failures_total counter")
+	reasons := make([]string, 0, len(m.failures))
+	for reason := range m.failures {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "hello_zkp_failures_total{reason=%q} %d\n", reason, m.failures[reason])
+	}
+
+	fmt.Fprintln(w, "# HELP hello_zkp_queue_depth Requests waiting for a proving slot.")
+	fmt.Fprintln(w, "# TYPE hello_zkp_queue_depth gauge")
+	fmt.Fprintf(w, "hello_zkp_queue_depth %d\n", m.queue)
+
+	fmt.Fprintln(w, "# HELP hello_zkp_prove_seconds Time spent building the witness and proving.")
+	fmt.Fprintln(w, "# TYPE hello_zkp_prove_seconds histogram")
+	for i, bound := range proveBuckets {
+		fmt.Fprintf(w, "hello_zkp_prove_seconds_bucket{le=%q} %d\n", strconv.FormatFloat(bound, 'g', -1, 64), m.buckets[i])
+	}
+	fmt.Fprintf(w, "hello_zkp_prove_seconds_bucket{le=\"+Inf\"} %d\n", m.count)
+	fmt.Fprintf(w, "hello_zkp_prove_seconds_sum %g\n", m.sum)
+	fmt.Fprintf(w, "hello_zkp_prove_seconds_count %d\n", m.count)
+}
+
+// serveMetrics serves /metrics, and the net/http/pprof endpoints under
+// /debug/pprof/ if withPprof is set, on addr until the server fails. The
+// cmdline endpoint is left out: it would hand the flags, -age among them,
+// to anyone who can reach the address.
+func serveMetrics(addr string, m *metrics, withPprof bool) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	if withPprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	return http.ListenAndServe(addr, mux)
+}
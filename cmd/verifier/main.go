@@ -0,0 +1,165 @@
+// Command verifier is the relying party's side of the two-process demo. It
+// holds only the verifying key: it picks the statement, issues a fresh
+// challenge, asks a running cmd/prover for a proof over a socket and checks
+// the answer against the public inputs it chose itself.
+//
+//	go run ./cmd/verifier -min 18 -max 30 -keys keys -connect 127.0.0.1:7420
+//
+// It exits 0 if the proof verified and 1 otherwise. With -audit-log the
+// outcome is recorded (see 'hello-zkp audit').
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/audit"
+	"github.com/ananthanir/hello-zkp/challenge"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/config"
+	"github.com/ananthanir/hello-zkp/domain"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/logging"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/session"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// curve is the curve the CLI proves on.
+var curve = ecc.BN254
+
+func main() {
+	// Logs stay disabled unless the config file or -log-level sets a level
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verifier: %v\n", err)
+		os.Exit(2)
+	}
+
+	min := flag.Int("min", 18, "public Min bound to demand")
+	max := flag.Int("max", 120, "public Max bound to demand")
+	bits := flag.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	keys := flag.String("keys", "keys", "directory holding vk.bin")
+	app := flag.String("domain", "", "application context to bind the proof to, e.g. bar-entry-check-v1")
+	connect := flag.String("connect", "127.0.0.1:7420", "prover address: host:port, or unix:<path>")
+	timeout := flag.Duration("timeout", 30*time.Second, "time allowed for the whole exchange")
+	auditLog := flag.String("audit-log", "", "append a record of the verification to this audit log (empty: none)")
+	logs := logging.AddFlags(flag.CommandLine)
+	if err := cfg.Apply(flag.CommandLine); err != nil {
+		fmt.Fprintf(os.Stderr, "verifier: %v\n", err)
+		os.Exit(2)
+	}
+	flag.Parse()
+	if err := logs.Setup(); err != nil {
+		fmt.Fprintf(os.Stderr, "verifier: %v\n", err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if err := run(ctx, *min, *max, *bits, *keys, *app, *connect, *auditLog); err != nil {
+		fmt.Printf("Verification: ❌ FAILED (%v)\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Verification: ✅ SUCCESS (Min ≤ Age ≤ Max proven zero-knowledge)")
+}
+
+func run(ctx context.Context, min, max, bits int, keys, app, connect, auditLog string) error {
+	definition, err := circuit.NewRangeCircuit(bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	vk := groth16.NewVerifyingKey(curve)
+	if err := keyfile.Read(filepath.Join(keys, "vk.bin"), vk); err != nil {
+		return err
+	}
+	var l *audit.Log
+	if auditLog != "" {
+		if l, err = audit.Open(auditLog); err != nil {
+			return zkp.Wrap(zkp.ErrIO, err)
+		}
+		defer l.Close()
+	}
+	env, err := exchange(ctx, definition, vk, min, max, app, connect)
+	if l == nil {
+		return err
+	}
+	var public witness.Witness
+	if env != nil {
+		_, public, _ = env.Open(definition.ID(), vk)
+	}
+	r := audit.NewRecord(audit.Verified, definition, env, public, err)
+	r.Pinned, r.Peer = true, connect
+	if aerr := l.Append(r); aerr != nil {
+		return errors.Join(err, zkp.Wrap(zkp.ErrIO, fmt.Errorf("audit log: %w", aerr)))
+	}
+	return err
+}
+
+// exchange asks the prover at connect for a proof and verifies it. It
+// returns the envelope received, if any, with the outcome.
+func exchange(ctx context.Context, definition *circuit.RangeCircuit, vk groth16.VerifyingKey, min, max int, app, connect string) (*envelope.Envelope, error) {
+	// The public inputs are fixed here, before talking to the prover, and
+	// the proof is checked against them rather than against its own.
+	nonce, err := challenge.New()
+	if err != nil {
+		return nil, err
+	}
+	expected, err := definition.PublicAssignment(min, max, nonce)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	publicWitness, err := prover.NewPublicWitness(curve, expected.WithDomain(domain.Hash(app)))
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := session.Dial(ctx, connect)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	fmt.Printf("Verifier: asking %s to prove %d ≤ Age ≤ %d (challenge %s)\n", connect, min, max, challenge.Format(nonce))
+	req := session.Request{Circuit: definition.ID(), Min: min, Max: max, Challenge: challenge.Format(nonce), Domain: app}
+	if err := session.Send(conn, req); err != nil {
+		return nil, err
+	}
+	var resp session.Response
+	if err := session.Receive(conn, &resp); err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	return resp.Envelope, resp.Envelope.VerifyStatement(definition.ID(), vk, publicWitness)
+}
+
+// loadConfig reads the config file, if any, and applies its curve and
+// logging. Logs go to stderr.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Find()
+	if err != nil {
+		return nil, err
+	}
+	if curve, err = cfg.CurveID(); err != nil {
+		return nil, err
+	}
+	if err := logging.Setup(cfg.LogLevel, cfg.LogFormat); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
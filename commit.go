@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/ananthanir/hello-zkp/commitment"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runCommit plays the registrar: it commits to an age with a fresh salt,
+// writes the opening for the holder and prints the public commitment.
+func runCommit(args []string) error {
+	fs := flag.NewFlagSet("commit", flag.ExitOnError)
+	age := fs.Int("age", 0, "age to commit to")
+	out := fs.String("out", "opening.json", "file to write the private opening to (mode 0600)")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	opening, err := commitment.New(curve, *age)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	if err := opening.Save(*out); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+
+	report.set("commitment", commitment.Format(opening.Commitment))
+	report.set("opening", *out)
+	report.printf("Commitment: %s\n", commitment.Format(opening.Commitment))
+	report.printf("Opening written to %s — keep it private\n", *out)
+	return nil
+}
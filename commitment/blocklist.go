@@ -0,0 +1,239 @@
+package commitment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// A blocklist publisher commits to a set of identifiers with a Merkle root,
+// so a holder can prove its identifier is not in the set without revealing
+// it. The tree does not hold the identifiers themselves but the gaps between
+// them: with the set sorted and framed by the sentinels 0 and 2^bits - 1,
+// every pair of neighbours (low, high) is a leaf H(low, high). An identifier
+// is absent exactly when some leaf has low < id < high, so a non-membership
+// proof is a single Merkle path. Unused leaves are zero, which is no hash of
+// any gap.
+//
+// Leaves and inner nodes use their own domain tags, so neither can be passed
+// off as the other.
+const (
+	gapTag  = 2
+	nodeTag = 3
+)
+
+// Limits on the blocklist shape: identifiers must fit in a non-negative Go
+// int, and the whole tree is rebuilt natively on every change.
+const (
+	maxIDBits = 62
+
+	// MaxDepth is the deepest blocklist tree.
+	MaxDepth = 20
+)
+
+var (
+	// ErrBlocked is returned when asked to prove non-membership of an
+	// identifier that is in the blocklist.
+	ErrBlocked = errors.New("identifier is in the blocklist")
+
+	// ErrBlocklistFull is returned when the identifiers no longer fit in the
+	// tree.
+	ErrBlocklistFull = errors.New("blocklist is full")
+
+	// ErrIdentifier is returned for identifiers outside [1, 2^bits - 2].
+	ErrIdentifier = errors.New("identifier out of range")
+)
+
+// Blocklist is the publisher's sorted set of blocked identifiers. Its Root is
+// what verifiers pin.
+type Blocklist struct {
+	Curve string `json:"curve"`
+	Bits  int    `json:"bits"`
+	Depth int    `json:"depth"`
+	IDs   []int  `json:"ids"`
+}
+
+// Gap is the Merkle proof that an identifier falls between two neighbours
+// of the blocklist.
+type Gap struct {
+	Low, High int
+	// Index is the position of the leaf; bit i of Index tells whether the
+	// node at level i is a right child.
+	Index int
+	// Path holds the siblings from the leaf up to the root.
+	Path []*big.Int
+}
+
+// NewBlocklist returns an empty blocklist for identifiers of the given bit
+// width, in a tree of 2^depth leaves, holding up to 2^depth - 1 identifiers.
+func NewBlocklist(curve ecc.ID, bits, depth int) (*Blocklist, error) {
+	if curve != ecc.BN254 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurve, curve)
+	}
+	if bits < 2 || bits > maxIDBits {
+		return nil, fmt.Errorf("identifier width %d must be between 2 and %d bits", bits, maxIDBits)
+	}
+	if depth < 1 || depth > MaxDepth {
+		return nil, fmt.Errorf("depth %d must be between 1 and %d", depth, MaxDepth)
+	}
+	return &Blocklist{Curve: curve.String(), Bits: bits, Depth: depth, IDs: []int{}}, nil
+}
+
+// Add blocks an identifier. Adding one already blocked is a no-op.
+func (b *Blocklist) Add(id int) error {
+	if err := b.CheckID(id); err != nil {
+		return err
+	}
+	i, found := slices.BinarySearch(b.IDs, id)
+	if found {
+		return nil
+	}
+	if len(b.IDs)+1 >= 1<<b.Depth {
+		return fmt.Errorf("%w: %d identifiers in a tree of depth %d", ErrBlocklistFull, len(b.IDs), b.Depth)
+	}
+	b.IDs = slices.Insert(b.IDs, i, id)
+	return nil
+}
+
+// Remove unblocks an identifier. Removing one not blocked is a no-op.
+func (b *Blocklist) Remove(id int) {
+	if i, found := slices.BinarySearch(b.IDs, id); found {
+		b.IDs = slices.Delete(b.IDs, i, i+1)
+	}
+}
+
+// Contains reports whether an identifier is blocked.
+func (b *Blocklist) Contains(id int) bool {
+	_, found := slices.BinarySearch(b.IDs, id)
+	return found
+}
+
+// Root returns the Merkle root committing to the blocklist.
+func (b *Blocklist) Root() *big.Int {
+	levels := b.tree()
+	return levels[len(levels)-1][0]
+}
+
+// NonMembership returns the Merkle proof that id is not blocked.
+func (b *Blocklist) NonMembership(id int) (*Gap, error) {
+	if err := b.CheckID(id); err != nil {
+		return nil, err
+	}
+	i, found := slices.BinarySearch(b.IDs, id)
+	if found {
+		return nil, ErrBlocked
+	}
+	bounds := b.bounds()
+	gap := &Gap{Low: bounds[i], High: bounds[i+1], Index: i}
+	levels := b.tree()
+	for level, index := 0, i; level < b.Depth; level, index = level+1, index/2 {
+		gap.Path = append(gap.Path, levels[level][index^1])
+	}
+	return gap, nil
+}
+
+// Verify checks that the blocklist is well formed: sorted, without
+// duplicates, in range and within capacity.
+func (b *Blocklist) Verify() error {
+	if b.Curve != ecc.BN254.String() {
+		return fmt.Errorf("%w: %s", ErrUnsupportedCurve, b.Curve)
+	}
+	if _, err := NewBlocklist(ecc.BN254, b.Bits, b.Depth); err != nil {
+		return err
+	}
+	if len(b.IDs) >= 1<<b.Depth {
+		return fmt.Errorf("%w: %d identifiers in a tree of depth %d", ErrBlocklistFull, len(b.IDs), b.Depth)
+	}
+	for i, id := range b.IDs {
+		if err := b.CheckID(id); err != nil {
+			return err
+		}
+		if i > 0 && b.IDs[i-1] >= id {
+			return fmt.Errorf("identifiers are not sorted and unique at %d", id)
+		}
+	}
+	return nil
+}
+
+// Save writes the blocklist to path. It is public, unlike openings.
+func (b *Blocklist) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadBlocklist reads a blocklist written by Save and checks it is well
+// formed.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Blocklist
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := b.Verify(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// HashGap constrains and returns the leaf H(low, high) inside a circuit.
+func HashGap(api frontend.API, low, high frontend.Variable) (frontend.Variable, error) {
+	return permute(api, low, high, gapTag)
+}
+
+// HashNode constrains and returns the inner node H(left, right) inside a
+// circuit.
+func HashNode(api frontend.API, left, right frontend.Variable) (frontend.Variable, error) {
+	return permute(api, left, right, nodeTag)
+}
+
+// CheckID returns ErrIdentifier unless id fits between the two sentinels, so
+// that it can be blocked.
+func (b *Blocklist) CheckID(id int) error {
+	if id < 1 || id > 1<<b.Bits-2 {
+		return fmt.Errorf("%w: %d (must be between 1 and %d)", ErrIdentifier, id, 1<<b.Bits-2)
+	}
+	return nil
+}
+
+// bounds returns the identifiers framed by the two sentinels.
+func (b *Blocklist) bounds() []int {
+	bounds := make([]int, 0, len(b.IDs)+2)
+	bounds = append(bounds, 0)
+	bounds = append(bounds, b.IDs...)
+	return append(bounds, 1<<b.Bits-1)
+}
+
+// tree returns every level of the Merkle tree, leaves first.
+func (b *Blocklist) tree() [][]*big.Int {
+	bounds := b.bounds()
+	leaves := make([]*big.Int, 1<<b.Depth)
+	for i := range leaves {
+		if i+1 < len(bounds) {
+			leaves[i] = permuteBN254(big.NewInt(int64(bounds[i])), big.NewInt(int64(bounds[i+1])), gapTag)
+		} else {
+			leaves[i] = new(big.Int)
+		}
+	}
+	levels := [][]*big.Int{leaves}
+	for len(levels[len(levels)-1]) > 1 {
+		below := levels[len(levels)-1]
+		level := make([]*big.Int, len(below)/2)
+		for i := range level {
+			level[i] = permuteBN254(below[2*i], below[2*i+1], nodeTag)
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
@@ -0,0 +1,185 @@
+// Package commitment implements the salted Poseidon2 commitment to an age.
+//
+// A registrar checks the holder's age once and publishes C = H(age, salt).
+// The holder keeps the opening (age and salt) private and can then prove range
+// statements about the committed age any number of times; every proof refers
+// to the same public C, so the proofs are linkable to one registered age
+// without ever revealing it.
+//
+// The same construction commits to a verifier's private policy bounds; see
+// Policy. Blocklists are committed to as a Merkle tree of the same hash; see
+// Blocklist. Lists of public inputs are hashed into one digest the same way;
+// see HashInputs.
+//
+// The same hash is implemented twice, natively (New, Opening.Verify) and as
+// constraints (Hash), and both must stay in lockstep.
+package commitment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	poseidon2bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	"github.com/consensys/gnark/frontend"
+	poseidon2 "github.com/consensys/gnark/std/permutation/poseidon2"
+)
+
+// Poseidon2 parameters: a width-3 state (two rate elements, one capacity
+// element), x^5 S-box and the round numbers recommended for BN254.
+const (
+	width         = 3
+	sboxDegree    = 5
+	fullRounds    = 8
+	partialRounds = 56
+	seed          = "hello-zkp/commitment"
+)
+
+// Domain tags placed in the capacity element. Age commitments predate the
+// tags and keep the zero capacity they always had.
+const (
+	ageTag    = 0
+	policyTag = 1
+)
+
+var (
+	// ErrUnsupportedCurve is returned for curves without a native
+	// implementation of the commitment.
+	ErrUnsupportedCurve = errors.New("unsupported curve for commitments")
+
+	// ErrMismatch is returned when an opening does not match its commitment.
+	ErrMismatch = errors.New("opening does not match commitment")
+)
+
+// Opening is what the holder keeps secret: the committed age and its salt,
+// together with the public commitment they open.
+type Opening struct {
+	Curve      string   `json:"curve"`
+	Age        int      `json:"age"`
+	Salt       *big.Int `json:"salt"`
+	Commitment *big.Int `json:"commitment"`
+}
+
+// New commits to age with a fresh random salt on the given curve.
+func New(curve ecc.ID, age int) (*Opening, error) {
+	if curve != ecc.BN254 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurve, curve)
+	}
+	if age < 0 {
+		return nil, errors.New("age is negative")
+	}
+	var salt fr.Element
+	if _, err := salt.SetRandom(); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	o := &Opening{Curve: curve.String(), Age: age, Salt: salt.BigInt(new(big.Int))}
+	o.Commitment = hashBN254(big.NewInt(int64(age)), o.Salt)
+	return o, nil
+}
+
+// Verify recomputes the commitment from the age and salt.
+func (o *Opening) Verify() error {
+	if o.Curve != ecc.BN254.String() {
+		return fmt.Errorf("%w: %s", ErrUnsupportedCurve, o.Curve)
+	}
+	if o.Salt == nil || o.Commitment == nil {
+		return ErrMismatch
+	}
+	if hashBN254(big.NewInt(int64(o.Age)), o.Salt).Cmp(o.Commitment) != 0 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// Save writes the opening to path, readable by the owner only: the salt is
+// what keeps the committed age hidden.
+func (o *Opening) Save(path string) error {
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Load reads an opening written by Save and checks it is consistent.
+func Load(path string) (*Opening, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var o Opening
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := o.Verify(); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Hash constrains and returns H(age, salt) inside a circuit.
+func Hash(api frontend.API, age, salt frontend.Variable) (frontend.Variable, error) {
+	return permute(api, age, salt, ageTag)
+}
+
+// hashBN254 is the native counterpart of Hash on BN254.
+func hashBN254(age, salt *big.Int) *big.Int {
+	return permuteBN254(age, salt, ageTag)
+}
+
+// permute constrains and returns the first element of the permuted state
+// (a, b, tag). The capacity element carries a tag telling apart the kinds of
+// values committed to, so an opening of one kind never opens another.
+func permute(api frontend.API, a, b frontend.Variable, tag int) (frontend.Variable, error) {
+	curve, err := curveOf(api.Compiler().Field())
+	if err != nil {
+		return nil, err
+	}
+	h := poseidon2.NewHash(width, sboxDegree, fullRounds, partialRounds, seed, curve)
+	state := []frontend.Variable{a, b, tag}
+	if err := h.Permutation(api, state); err != nil {
+		return nil, err
+	}
+	return state[0], nil
+}
+
+// permuteBN254 is the native counterpart of permute on BN254.
+func permuteBN254(a, b *big.Int, tag int) *big.Int {
+	h := poseidon2bn254.NewHash(width, fullRounds, partialRounds, seed)
+	state := make([]fr.Element, width)
+	state[0].SetBigInt(a)
+	state[1].SetBigInt(b)
+	state[2].SetInt64(int64(tag))
+	if err := h.Permutation(state); err != nil {
+		// only fails on a wrongly sized state, which is fixed above
+		panic(err)
+	}
+	return state[0].BigInt(new(big.Int))
+}
+
+func curveOf(field *big.Int) (ecc.ID, error) {
+	for _, id := range ecc.Implemented() {
+		if id.ScalarField().Cmp(field) == 0 {
+			return id, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("%w: field %s", ErrUnsupportedCurve, field)
+}
+
+// Format returns the hex form of a commitment, as published by a registrar.
+func Format(c *big.Int) string {
+	return c.Text(16)
+}
+
+// Parse reads a commitment in the form produced by Format.
+func Parse(s string) (*big.Int, error) {
+	c, ok := new(big.Int).SetString(s, 16)
+	if !ok || c.Sign() < 0 {
+		return nil, fmt.Errorf("malformed commitment %q", s)
+	}
+	return c, nil
+}
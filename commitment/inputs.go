@@ -0,0 +1,39 @@
+package commitment
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Public inputs can be hashed into one digest, so that a verifier checks a
+// single public input instead of many (on chain, every public input costs a
+// scalar multiplication) and recomputes the digest from the values it
+// expects. Values are absorbed one at a time, starting from their count:
+//
+//	d₀ = n, dᵢ = H(dᵢ₋₁, vᵢ), digest = dₙ
+//
+// so lists of different lengths never share a chain.
+const inputsTag = 4
+
+// HashInputs constrains and returns the digest of values inside a circuit.
+func HashInputs(api frontend.API, values ...frontend.Variable) (frontend.Variable, error) {
+	var d frontend.Variable = len(values)
+	for _, v := range values {
+		var err error
+		if d, err = permute(api, d, v, inputsTag); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// InputsDigest is the native counterpart of HashInputs on BN254. Values must
+// be below the BN254 scalar field order.
+func InputsDigest(values ...*big.Int) *big.Int {
+	d := big.NewInt(int64(len(values)))
+	for _, v := range values {
+		d = permuteBN254(d, v, inputsTag)
+	}
+	return d
+}
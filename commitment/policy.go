@@ -0,0 +1,101 @@
+package commitment
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+)
+
+// A verifier that does not want to reveal its exact thresholds publishes a
+// policy commitment P = H(Min ‖ Max, salt) instead, and hands the opening to
+// provers privately. Proofs then show Min ≤ Age ≤ Max against P alone.
+//
+// Min and Max are packed into one field element as Min + Max·2^64, which is
+// injective for bounds below 2^64.
+const packShift = 64
+
+// Policy is the opening of a policy commitment: the private bounds and salt,
+// together with the public commitment they open.
+type Policy struct {
+	Curve      string   `json:"curve"`
+	Min        int      `json:"min"`
+	Max        int      `json:"max"`
+	Salt       *big.Int `json:"salt"`
+	Commitment *big.Int `json:"commitment"`
+}
+
+// NewPolicy commits to the bounds [min, max] with a fresh random salt.
+func NewPolicy(curve ecc.ID, min, max int) (*Policy, error) {
+	if curve != ecc.BN254 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurve, curve)
+	}
+	if min < 0 || max < 0 {
+		return nil, fmt.Errorf("bounds %d and %d must not be negative", min, max)
+	}
+	var salt fr.Element
+	if _, err := salt.SetRandom(); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	p := &Policy{Curve: curve.String(), Min: min, Max: max, Salt: salt.BigInt(new(big.Int))}
+	p.Commitment = hashPolicyBN254(min, max, p.Salt)
+	return p, nil
+}
+
+// Verify recomputes the commitment from the bounds and salt.
+func (p *Policy) Verify() error {
+	if p.Curve != ecc.BN254.String() {
+		return fmt.Errorf("%w: %s", ErrUnsupportedCurve, p.Curve)
+	}
+	if p.Salt == nil || p.Commitment == nil || p.Min < 0 || p.Max < 0 {
+		return ErrMismatch
+	}
+	if hashPolicyBN254(p.Min, p.Max, p.Salt).Cmp(p.Commitment) != 0 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// Save writes the policy opening to path, readable by the owner only.
+func (p *Policy) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// LoadPolicy reads a policy opening written by Save and checks it is
+// consistent.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := p.Verify(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// HashPolicy constrains and returns H(min ‖ max, salt) inside a circuit. The
+// caller must bound min and max to fewer than 64 bits.
+func HashPolicy(api frontend.API, min, max, salt frontend.Variable) (frontend.Variable, error) {
+	packed := api.Add(min, api.Mul(max, new(big.Int).Lsh(big.NewInt(1), packShift)))
+	return permute(api, packed, salt, policyTag)
+}
+
+// hashPolicyBN254 is the native counterpart of HashPolicy on BN254.
+func hashPolicyBN254(min, max int, salt *big.Int) *big.Int {
+	packed := new(big.Int).Lsh(big.NewInt(int64(max)), packShift)
+	packed.Add(packed, big.NewInt(int64(min)))
+	return permuteBN254(packed, salt, policyTag)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ananthanir/hello-zkp/config"
+	"github.com/ananthanir/hello-zkp/logging"
+	"github.com/ananthanir/hello-zkp/tracing"
+)
+
+// cfg holds the defaults read from the config file, if any.
+var cfg = &config.Config{}
+
+// loadConfig reads the config file and applies its curve and logging.
+func loadConfig() error {
+	c, err := config.Find()
+	if err != nil {
+		return err
+	}
+	id, err := c.CurveID()
+	if err != nil {
+		return err
+	}
+	// gnark logs to stdout by default, which -json reserves for the report
+	if err := logging.Setup(c.LogLevel, c.LogFormat); err != nil {
+		return err
+	}
+	cfg, curve = c, id
+	return nil
+}
+
+// parseFlags parses args into fs, with the config file supplying the
+// defaults of the flags not given on the command line. Every command gets
+// -log-level, -log-format and -otlp-endpoint this way, and a root span named
+// after it that the spans of its proving steps are children of.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	logs := logging.AddFlags(fs)
+	traces := tracing.AddFlags(fs)
+	if err := cfg.Apply(fs); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", fs.Name(), err)
+		os.Exit(exitUsage)
+	}
+	fs.Parse(args)
+	if err := logs.Setup(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", fs.Name(), err)
+		os.Exit(exitUsage)
+	}
+	if err := traces.Setup("hello-zkp"); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", fs.Name(), err)
+		os.Exit(exitUsage)
+	}
+	endTrace = tracing.StartRoot("hello-zkp "+fs.Name(), "curve", curve.String())
+}
+
+// endTrace ends the root span of the command, once parseFlags started one.
+var endTrace = func(error) {}
+
+// finishTrace ends the root span and exports the spans still queued, giving
+// a collector that went away a few seconds at most.
+func finishTrace(err error) {
+	endTrace(err)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := tracing.Shutdown(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "hello-zkp: tracing: %v\n", err)
+	}
+}
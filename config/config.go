@@ -0,0 +1,187 @@
+// Package config reads the optional file holding defaults for the CLI
+// parameters, so a deployment can keep its curve, bit width, key paths, log
+// level and server addresses in one versioned file instead of on every
+// command line. Flags given on the command line still win.
+//
+// The file is JSON:
+//
+//	{
+//	  "curve": "bn254",
+//	  "backend": "groth16",
+//	  "bits": 8,
+//	  "keys": "/etc/hello-zkp/keys",
+//	  "cache": "",
+//	  "log_level": "info",
+//	  "log_format": "json",
+//	  "server": {"listen": "unix:/run/hello-zkp.sock", "connect": "unix:/run/hello-zkp.sock"}
+//	}
+//
+// Every field is optional.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/rs/zerolog"
+
+	"github.com/ananthanir/hello-zkp/logging"
+)
+
+// File is the config file read from the working directory when EnvVar is
+// not set.
+const File = "hello-zkp.json"
+
+// EnvVar names the environment variable holding the path of the config file.
+const EnvVar = "HELLO_ZKP_CONFIG"
+
+// Backend is the only proving backend the CLI supports.
+const Backend = "groth16"
+
+// ErrInvalid is returned for a config file that cannot be parsed or holds an
+// unsupported value.
+var ErrInvalid = errors.New("invalid config")
+
+// Config holds the defaults read from a config file. Zero values mean the
+// built-in default.
+type Config struct {
+	Curve     string  `json:"curve,omitempty"`
+	Backend   string  `json:"backend,omitempty"`
+	Bits      int     `json:"bits,omitempty"`
+	Keys      string  `json:"keys,omitempty"`
+	Cache     *string `json:"cache,omitempty"` // set to "" to disable the key cache
+	LogLevel  string  `json:"log_level,omitempty"`
+	LogFormat string  `json:"log_format,omitempty"`
+	OTLP      string  `json:"otlp_endpoint,omitempty"`
+	AuditLog  string  `json:"audit_log,omitempty"`
+	Server    Server  `json:"server"`
+}
+
+// Server holds the addresses of the two-process demo (cmd/prover and
+// cmd/verifier).
+type Server struct {
+	Listen  string `json:"listen,omitempty"`
+	Connect string `json:"connect,omitempty"`
+	Metrics string `json:"metrics,omitempty"` // cmd/prover's -metrics address
+}
+
+// Find loads the config file named by $HELLO_ZKP_CONFIG, or File in the
+// working directory if it exists. With neither it returns an empty Config.
+func Find() (*Config, error) {
+	if path, ok := os.LookupEnv(EnvVar); ok && path != "" {
+		return Load(path)
+	}
+	if _, err := os.Stat(File); errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	return Load(File)
+}
+
+// Load reads and checks the config file at path. Unknown fields are
+// rejected so a typo does not silently fall back to a default.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var c Config
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%w: parse %s: %v", ErrInvalid, path, err)
+	}
+	if err := c.check(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalid, path, err)
+	}
+	return &c, nil
+}
+
+func (c *Config) check() error {
+	if _, err := c.CurveID(); err != nil {
+		return err
+	}
+	if c.Backend != "" && c.Backend != Backend {
+		return fmt.Errorf("unsupported backend %q (only %s)", c.Backend, Backend)
+	}
+	if c.Bits < 0 {
+		return fmt.Errorf("invalid bits %d", c.Bits)
+	}
+	if _, err := c.Level(); err != nil {
+		return err
+	}
+	_, err := logging.ParseFormat(c.LogFormat)
+	return err
+}
+
+// CurveID returns the configured curve, BN254 by default.
+func (c *Config) CurveID() (ecc.ID, error) {
+	if c.Curve == "" {
+		return ecc.BN254, nil
+	}
+	id, err := ecc.IDFromString(c.Curve)
+	if err != nil {
+		return ecc.UNKNOWN, fmt.Errorf("unknown curve %q", c.Curve)
+	}
+	return id, nil
+}
+
+// Level returns the configured log level, disabled by default.
+func (c *Config) Level() (zerolog.Level, error) {
+	return logging.ParseLevel(c.LogLevel)
+}
+
+// Apply makes the configured values the defaults of the matching flags in
+// fs, so flags set on the command line override them. Call it before
+// fs.Parse. Flags fs does not define are left alone.
+func (c *Config) Apply(fs *flag.FlagSet) error {
+	defaults := map[string]string{}
+	if c.Bits != 0 {
+		defaults["bits"] = strconv.Itoa(c.Bits)
+	}
+	if c.Keys != "" {
+		defaults["keys"] = c.Keys
+	}
+	if c.Cache != nil {
+		defaults["cache"] = *c.Cache
+	}
+	if c.LogLevel != "" {
+		defaults["log-level"] = c.LogLevel
+	}
+	if c.LogFormat != "" {
+		defaults["log-format"] = c.LogFormat
+	}
+	if c.OTLP != "" {
+		defaults["otlp-endpoint"] = c.OTLP
+	}
+	if c.AuditLog != "" {
+		defaults["audit-log"] = c.AuditLog
+	}
+	if c.Server.Listen != "" {
+		defaults["listen"] = c.Server.Listen
+	}
+	if c.Server.Connect != "" {
+		defaults["connect"] = c.Server.Connect
+	}
+	if c.Server.Metrics != "" {
+		defaults["metrics"] = c.Server.Metrics
+	}
+	for name, value := range defaults {
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		// Setting the Value directly, not through fs.Set, keeps the flag
+		// out of fs.Visit: it still counts as a default.
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalid, name, err)
+		}
+		f.DefValue = value
+	}
+	return nil
+}
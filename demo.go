@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	gnarkbackend "github.com/consensys/gnark/backend"
+
+	"github.com/ananthanir/hello-zkp/challenge"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runDemo runs the whole flow in one process: it gathers the inputs, then
+// compiles, sets up, proves and verifies. With -tour it runs the
+// menu-driven walkthrough instead.
+func runDemo(args []string) error {
+	fs := flag.NewFlagSet("demo", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	cache := fs.String("cache", defaultCacheDir(), "directory caching keys per circuit (empty: always run setup)")
+	inputs := addInputFlags(fs, "age", "min", "max")
+	tour := fs.Bool("tour", false, "walk through each step interactively, with a menu of circuits")
+	lowMemory := fs.Bool("low-memory", false, "lower the peak memory at the cost of speed (see README)")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	if *tour {
+		return runTour(*bits, *cache)
+	}
+
+	definition, err := circuit.NewRangeCircuit(*bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	var opts []gnarkbackend.ProverOption
+	if *lowMemory {
+		opts = prover.LowMemory()
+	}
+
+	// -----------------------------
+	// Gather inputs: flags, -witness file, environment, then stdin
+	// -----------------------------
+	sources, err := inputs.sources()
+	if err != nil {
+		return err
+	}
+	values, err := resolveInputs(sources, "age", "min", "max")
+	if err != nil {
+		return err
+	}
+	age, min, max := values["age"], values["min"], values["max"]
+
+	// Reject inputs that do not fit the circuit before paying for setup
+	assignment, err := definition.Assign(age, min, max) // Age private, Min/Max public
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+
+	// -----------------------------
+	// 1) Compile circuit
+	// -----------------------------
+	start := time.Now()
+	ccs, err := prover.Compile(curve, definition)
+	if err != nil {
+		return err
+	}
+	report.since("compile_ns", start)
+	report.set("circuit", definition.ID())
+	report.set("constraints", ccs.GetNbConstraints())
+	if *lowMemory {
+		prover.Release()
+	}
+
+	// -----------------------------
+	// 2) Trusted setup (Groth16)
+	// -----------------------------
+	start = time.Now()
+	pk, vk, err := setupKeys(ccs, *cache)
+	if err != nil {
+		return err
+	}
+	report.since("setup_ns", start)
+	if *lowMemory {
+		prover.Release()
+	}
+
+	// -----------------------------
+	// 3) Assign inputs (witness)
+	// -----------------------------
+	// The verifier issues a fresh challenge; binding it into the proof makes
+	// the proof worthless to anyone who intercepts it.
+	nonce, err := challenge.New()
+	if err != nil {
+		return err
+	}
+	assignment = assignment.WithChallenge(nonce)
+
+	report.println("\n=== Inputs ===")
+	report.println("Private:  Age (never printed)")
+	report.printf("Public:   Min = %v\n", min)
+	report.printf("Public:   Max = %v\n", max)
+	report.printf("Public:   Challenge = %s\n", challenge.Format(nonce))
+	report.println("Proving statement: Min ≤ Age ≤ Max ?")
+	report.set("inputs", map[string]any{
+		"min":       min,
+		"max":       max,
+		"challenge": challenge.Format(nonce),
+	})
+
+	witness, publicWitness, err := prover.NewWitness(curve, assignment)
+	if err != nil {
+		return err
+	}
+
+	// -----------------------------
+	// 4) Prove
+	// -----------------------------
+	start = time.Now()
+	proof, err := prover.Prove(ccs, pk, witness, opts...)
+	if err != nil {
+		report.println("Prove: ❌ FAILED (witness does not satisfy constraints)")
+		return err
+	}
+	report.since("prove_ns", start)
+	if rss, ok := peakRSS(); ok {
+		report.set("peak_rss_bytes", rss)
+	}
+	if encoded, err := envelope.EncodeProof(proof); err == nil {
+		report.set("proof", encoded)
+	}
+
+	// -----------------------------
+	// 5) Verify
+	// -----------------------------
+	start = time.Now()
+	err = zkp.Verify(proof, vk, publicWitness)
+	report.since("verify_ns", start)
+	return reportVerification(err)
+}
@@ -0,0 +1,51 @@
+// Package domain separates proofs made for different relying parties.
+//
+// Each application names itself with a context string, such as
+// "bar-entry-check-v1", and proofs carry its hash as a public input. A
+// verifier only accepts proofs carrying its own domain, so a proof made for
+// one relying party cannot be presented to another, even when both ask for
+// the same bounds.
+package domain
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Size is the hash length in bytes, truncated from SHA-256 as for
+// challenges: 31 bytes keep every domain below the scalar field order of all
+// supported curves.
+const Size = 31
+
+// ErrMismatch is returned when a proof was made for another domain.
+var ErrMismatch = errors.New("proof is bound to another domain")
+
+// tag prefixes every hashed context string, so domains cannot collide with
+// other SHA-256 values used by hello-zkp.
+const tag = "hello-zkp/domain:"
+
+// Hash returns the public input for an application context string. The
+// empty string means "no domain" and hashes to nil, which circuits read as
+// zero.
+func Hash(context string) *big.Int {
+	if context == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(tag + context))
+	return new(big.Int).SetBytes(sum[:Size])
+}
+
+// Check returns ErrMismatch unless got, the domain public input of a proof,
+// is the hash of context.
+func Check(got *big.Int, context string) error {
+	want := Hash(context)
+	if want == nil {
+		want = new(big.Int)
+	}
+	if got.Cmp(want) != 0 {
+		return fmt.Errorf("%w: expected %q", ErrMismatch, context)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package envelope
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark/backend/groth16"
+
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// VerifyBatch verifies many envelopes against the same circuit and verifying
+// key, spreading the work over all available CPU cores.
+//
+// The returned slice has one entry per envelope: nil if it verified, the
+// reason (wrapping zkp.ErrVerificationFailed) otherwise. The second return
+// value is only set when the batch as a whole could not be checked, e.g.
+// because the key cannot be hashed.
+func VerifyBatch(circuitID string, vk groth16.VerifyingKey, envs []*Envelope) ([]error, error) {
+	vkHash, err := HashVerifyingKey(vk)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(envs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				proof, publicWitness, err := envs[i].open(circuitID, vk, vkHash)
+				if err != nil {
+					errs[i] = zkp.Wrap(zkp.ErrVerificationFailed, err)
+					continue
+				}
+				errs[i] = zkp.Verify(proof, vk, publicWitness)
+			}
+		}()
+	}
+	for i := range envs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errs, nil
+}
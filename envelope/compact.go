@@ -0,0 +1,150 @@
+package envelope
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CompactPrefix starts every compact envelope, and tells scanners and
+// verifiers what the rest of the text is.
+const CompactPrefix = "HZ1:"
+
+// maxCompactSize bounds the decompressed size of a compact envelope; real
+// ones are a few hundred bytes.
+const maxCompactSize = 1 << 16
+
+// base45Alphabet is RFC 9285's alphabet, which is also the QR code
+// alphanumeric character set.
+const base45Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// Compact returns the envelope as short text meant for a QR code: the fields
+// in binary, DEFLATE-compressed, base45-encoded and prefixed with
+// CompactPrefix. Proof points are already in compressed form. The text only
+// uses the QR alphanumeric characters, which QR codes store most densely.
+func (e *Envelope) Compact() (string, error) {
+	vkHash, err := hex.DecodeString(e.VKHash)
+	if err != nil {
+		return "", fmt.Errorf("%w: vk_hash: %v", ErrMalformed, err)
+	}
+	var raw []byte
+	raw = binary.AppendUvarint(raw, uint64(e.Version))
+	for _, field := range [][]byte{[]byte(e.Curve), []byte(e.Circuit), vkHash, e.Proof, e.PublicInputs} {
+		raw = binary.AppendUvarint(raw, uint64(len(field)))
+		raw = append(raw, field...)
+	}
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return CompactPrefix + encodeBase45(buf.Bytes()), nil
+}
+
+// ParseCompact decodes text produced by Compact, as read back from a QR
+// code. Errors wrap ErrMalformed.
+func ParseCompact(s string) (*Envelope, error) {
+	body, ok := strings.CutPrefix(strings.TrimSpace(s), CompactPrefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s prefix", ErrMalformed, CompactPrefix)
+	}
+	compressed, err := decodeBase45(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	raw, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), maxCompactSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if len(raw) > maxCompactSize {
+		return nil, fmt.Errorf("%w: compact envelope larger than %d bytes", ErrMalformed, maxCompactSize)
+	}
+
+	r := bytes.NewReader(raw)
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: version: %v", ErrMalformed, err)
+	}
+	fields := make([][]byte, 5)
+	for i := range fields {
+		n, err := binary.ReadUvarint(r)
+		if err != nil || n > uint64(r.Len()) {
+			return nil, fmt.Errorf("%w: truncated field %d", ErrMalformed, i)
+		}
+		fields[i] = make([]byte, n)
+		io.ReadFull(r, fields[i])
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrMalformed, r.Len())
+	}
+	return &Envelope{
+		Version:      int(version),
+		Curve:        string(fields[0]),
+		Circuit:      string(fields[1]),
+		VKHash:       hex.EncodeToString(fields[2]),
+		Proof:        fields[3],
+		PublicInputs: fields[4],
+	}, nil
+}
+
+// encodeBase45 implements RFC 9285: every two bytes become three characters,
+// a trailing byte two.
+func encodeBase45(data []byte) string {
+	var sb strings.Builder
+	for i := 0; i < len(data); i += 2 {
+		if i+1 == len(data) {
+			n := int(data[i])
+			sb.WriteByte(base45Alphabet[n%45])
+			sb.WriteByte(base45Alphabet[n/45])
+			break
+		}
+		n := int(data[i])<<8 | int(data[i+1])
+		sb.WriteByte(base45Alphabet[n%45])
+		sb.WriteByte(base45Alphabet[n/45%45])
+		sb.WriteByte(base45Alphabet[n/(45*45)])
+	}
+	return sb.String()
+}
+
+func decodeBase45(s string) ([]byte, error) {
+	if len(s)%3 == 1 {
+		return nil, errors.New("base45: invalid length")
+	}
+	out := make([]byte, 0, len(s)/3*2+1)
+	for i := 0; i < len(s); i += 3 {
+		chunk := s[i:min(i+3, len(s))]
+		n, weight := 0, 1
+		for j := 0; j < len(chunk); j++ {
+			d := strings.IndexByte(base45Alphabet, chunk[j])
+			if d < 0 {
+				return nil, fmt.Errorf("base45: invalid character %q", chunk[j])
+			}
+			n += d * weight
+			weight *= 45
+		}
+		if len(chunk) == 3 {
+			if n > 0xffff {
+				return nil, errors.New("base45: value out of range")
+			}
+			out = append(out, byte(n>>8), byte(n))
+		} else {
+			if n > 0xff {
+				return nil, errors.New("base45: value out of range")
+			}
+			out = append(out, byte(n))
+		}
+	}
+	return out, nil
+}
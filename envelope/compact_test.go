@@ -0,0 +1,98 @@
+package envelope
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompactRoundTrip(t *testing.T) {
+	data, err := os.ReadFile(goldenDir + "proof.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := env.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, ok := strings.CutPrefix(compact, CompactPrefix)
+	if !ok {
+		t.Fatalf("%q does not start with %s", compact, CompactPrefix)
+	}
+	if i := strings.IndexFunc(body, func(r rune) bool { return !strings.ContainsRune(base45Alphabet, r) }); i >= 0 {
+		t.Fatalf("character %q is not in the QR alphanumeric set", body[i])
+	}
+	got, err := ParseCompact(compact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, env) {
+		t.Errorf("ParseCompact(Compact()) = %+v, want %+v", got, env)
+	}
+	if _, err := ParseCompact(" " + compact + "\n"); err != nil {
+		t.Errorf("surrounding whitespace: %v", err)
+	}
+}
+
+func TestParseCompactMalformed(t *testing.T) {
+	data, err := os.ReadFile(goldenDir + "proof.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := env.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name string
+		text string
+	}{
+		{"no prefix", strings.TrimPrefix(compact, CompactPrefix)},
+		{"one character too many", compact + "0"},
+		{"not base45", compact[:len(compact)-3] + "abc"},
+		{"truncated", compact[:len(compact)/2/3*3]},
+		{"not DEFLATE", CompactPrefix + encodeBase45([]byte("not deflate"))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseCompact(tc.text); !errors.Is(err, ErrMalformed) {
+				t.Errorf("ParseCompact = %v, want ErrMalformed", err)
+			}
+		})
+	}
+}
+
+func TestBase45(t *testing.T) {
+	// The examples of RFC 9285, section 4.3.
+	for _, tc := range []struct{ data, text string }{
+		{"AB", "BB8"},
+		{"Hello!!", "%69 VD92EX0"},
+		{"base-45", "UJCLQE7W581"},
+		{"ietf!", "QED8WEX0"},
+		{"", ""},
+	} {
+		if got := encodeBase45([]byte(tc.data)); got != tc.text {
+			t.Errorf("encodeBase45(%q) = %q, want %q", tc.data, got, tc.text)
+		}
+		got, err := decodeBase45(tc.text)
+		if err != nil || string(got) != tc.data {
+			t.Errorf("decodeBase45(%q) = %q, %v; want %q", tc.text, got, err, tc.data)
+		}
+	}
+	// GGW is 65535 + 1, which does not fit in two bytes.
+	for _, text := range []string{"GGW", "A", "ab"} {
+		if _, err := decodeBase45(text); err == nil {
+			t.Errorf("decodeBase45(%q) succeeded", text)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// The helpers below turn Groth16 artifacts into base64 strings (and back) so
+// they can be embedded in JSON documents, web responses, config files or QR
+// codes. The bytes are the same as the binary WriteTo encoding.
+//
+// Proofs and public witnesses are treated as untrusted and checked before
+// decoding; verifying keys are expected to come from a trusted source.
+
+// EncodeProof returns the base64 encoding of a proof.
+func EncodeProof(proof groth16.Proof) (string, error) {
+	return encode(proof)
+}
+
+// DecodeProof parses a base64 proof for the given curve.
+func DecodeProof(curve ecc.ID, s string) (groth16.Proof, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: proof: %v", ErrMalformed, err)
+	}
+	return decodeProof(curve, data)
+}
+
+// EncodeVerifyingKey returns the base64 encoding of a verifying key.
+func EncodeVerifyingKey(vk groth16.VerifyingKey) (string, error) {
+	return encode(vk)
+}
+
+// DecodeVerifyingKey parses a base64 verifying key for the given curve.
+func DecodeVerifyingKey(curve ecc.ID, s string) (groth16.VerifyingKey, error) {
+	vk := groth16.NewVerifyingKey(curve)
+	if err := decode(s, vk); err != nil {
+		return nil, fmt.Errorf("decode verifying key: %w", err)
+	}
+	return vk, nil
+}
+
+// EncodePublicWitness returns the base64 encoding of a public witness.
+func EncodePublicWitness(publicWitness witness.Witness) (string, error) {
+	return encode(publicWitness)
+}
+
+// DecodePublicWitness parses a base64 public witness for the given curve.
+func DecodePublicWitness(curve ecc.ID, s string) (witness.Witness, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: public inputs: %v", ErrMalformed, err)
+	}
+	return decodePublicWitness(curve, data)
+}
+
+func encode(v io.WriterTo) (string, error) {
+	var buf bytes.Buffer
+	if _, err := v.WriteTo(&buf); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeProof checks the layout of an untrusted proof encoding, then parses it.
+func decodeProof(curve ecc.ID, data []byte) (groth16.Proof, error) {
+	if err := checkProofLayout(curve, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	proof := groth16.NewProof(curve)
+	if _, err := proof.ReadFrom(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("%w: proof: %v", ErrMalformed, err)
+	}
+	return proof, nil
+}
+
+// decodePublicWitness checks the layout of an untrusted public witness
+// encoding, then parses it.
+func decodePublicWitness(curve ecc.ID, data []byte) (witness.Witness, error) {
+	if err := checkWitnessLayout(curve, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	publicWitness, err := witness.New(curve.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := publicWitness.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("%w: public inputs: %v", ErrMalformed, err)
+	}
+	return publicWitness, nil
+}
+
+func decode(s string, v io.ReaderFrom) error {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	_, err = v.ReadFrom(bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,216 @@
+// Package envelope defines the self-describing container proofs travel in.
+//
+// Raw Groth16 proof bytes say nothing about the circuit, curve or key they
+// were produced for. An Envelope bundles the proof with its public inputs and
+// enough metadata for a verifier to refuse anything it was not set up for.
+package envelope
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// Version is the envelope format version written by this package.
+const Version = 1
+
+// Errors returned when an envelope does not match what the verifier expects.
+var (
+	ErrVersion         = errors.New("unsupported envelope version")
+	ErrCurveMismatch   = errors.New("curve mismatch")
+	ErrCircuitMismatch = errors.New("circuit mismatch")
+	ErrVKMismatch      = errors.New("verifying key mismatch")
+	ErrMalformed       = errors.New("malformed envelope")
+	ErrStatement       = errors.New("public inputs do not match the expected statement")
+)
+
+// Envelope is a proof together with everything needed to check it against
+// the right verifying key. Binary fields are base64 encoded in JSON.
+type Envelope struct {
+	Version      int    `json:"version"`
+	Curve        string `json:"curve"`
+	Circuit      string `json:"circuit"`
+	VKHash       string `json:"vk_hash"`
+	Proof        []byte `json:"proof"`
+	PublicInputs []byte `json:"public_inputs"`
+}
+
+// New seals a proof and its public witness into an envelope.
+func New(circuitID string, vk groth16.VerifyingKey, proof groth16.Proof, publicWitness witness.Witness) (*Envelope, error) {
+	vkHash, err := HashVerifyingKey(vk)
+	if err != nil {
+		return nil, err
+	}
+
+	var proofBuf bytes.Buffer
+	if _, err := proof.WriteTo(&proofBuf); err != nil {
+		return nil, fmt.Errorf("encode proof: %w", err)
+	}
+	publicInputs, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encode public inputs: %w", err)
+	}
+
+	return &Envelope{
+		Version:      Version,
+		Curve:        vk.CurveID().String(),
+		Circuit:      circuitID,
+		VKHash:       vkHash,
+		Proof:        proofBuf.Bytes(),
+		PublicInputs: publicInputs,
+	}, nil
+}
+
+// Open checks the envelope metadata against what the verifier expects and
+// decodes the proof and public witness. It does not verify the proof.
+func (e *Envelope) Open(circuitID string, vk groth16.VerifyingKey) (groth16.Proof, witness.Witness, error) {
+	vkHash, err := HashVerifyingKey(vk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return e.open(circuitID, vk, vkHash)
+}
+
+// open is Open with the verifying key hash precomputed, so batches only hash
+// the key once.
+func (e *Envelope) open(circuitID string, vk groth16.VerifyingKey, vkHash string) (groth16.Proof, witness.Witness, error) {
+	if e.Version != Version {
+		return nil, nil, fmt.Errorf("%w: %d (expected %d)", ErrVersion, e.Version, Version)
+	}
+	curve := vk.CurveID()
+	if e.Curve != curve.String() {
+		return nil, nil, fmt.Errorf("%w: envelope is for %s, verifying key is for %s", ErrCurveMismatch, e.Curve, curve)
+	}
+	if e.Circuit != circuitID {
+		return nil, nil, fmt.Errorf("%w: envelope is for %q, expected %q", ErrCircuitMismatch, e.Circuit, circuitID)
+	}
+	if e.VKHash != vkHash {
+		return nil, nil, fmt.Errorf("%w: envelope expects %s, got %s", ErrVKMismatch, e.VKHash, vkHash)
+	}
+
+	proof, err := decodeProof(curve, e.Proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicWitness, err := decodePublicWitness(curve, e.PublicInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, publicWitness, nil
+}
+
+// Verify opens the envelope and checks the proof it carries.
+//
+// Every failure, metadata mismatches included, wraps zkp.ErrVerificationFailed.
+func (e *Envelope) Verify(circuitID string, vk groth16.VerifyingKey) error {
+	proof, publicWitness, err := e.Open(circuitID, vk)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	return zkp.Verify(proof, vk, publicWitness)
+}
+
+// VerifyStatement is Verify for a verifier that knows which public inputs it
+// expects (its own policy and the challenge it issued). The envelope's public
+// inputs must match them exactly; a proof for any other statement, including
+// one replayed from an earlier challenge, is rejected.
+func (e *Envelope) VerifyStatement(circuitID string, vk groth16.VerifyingKey, expected witness.Witness) error {
+	proof, publicWitness, err := e.Open(circuitID, vk)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	got, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	want, err := expected.MarshalBinary()
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	if !bytes.Equal(got, want) {
+		return zkp.Wrap(zkp.ErrVerificationFailed, ErrStatement)
+	}
+	return zkp.Verify(proof, vk, expected)
+}
+
+// WriteTo writes the envelope as indented JSON.
+func (e *Envelope) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// Read decodes a JSON envelope.
+func Read(r io.Reader) (*Envelope, error) {
+	var e Envelope
+	if err := json.NewDecoder(r).Decode(&e); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return &e, nil
+}
+
+// HashVerifyingKey returns the hex SHA-256 of the key's compressed encoding.
+func HashVerifyingKey(vk groth16.VerifyingKey) (string, error) {
+	h := sha256.New()
+	if _, err := vk.WriteTo(h); err != nil {
+		return "", fmt.Errorf("hash verifying key: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CheckFingerprint checks that the key has the given fingerprint, the hex
+// SHA-256 returned by HashVerifyingKey, optionally prefixed by "sha256:". A
+// verifier that hardcodes the fingerprint detects a substituted key however
+// the key reached it. A mismatch wraps ErrVKMismatch; any other error is a
+// malformed fingerprint.
+func CheckFingerprint(vk groth16.VerifyingKey, fingerprint string) error {
+	want, err := parseFingerprint(fingerprint)
+	if err != nil {
+		return err
+	}
+	got, err := HashVerifyingKey(vk)
+	if err != nil {
+		return err
+	}
+	return compareFingerprints(got, want)
+}
+
+// CheckEncodedFingerprint is CheckFingerprint for the compressed encoding
+// of a key, as vk.WriteTo writes it, so that untrusted bytes are checked
+// before anything decodes them.
+func CheckEncodedFingerprint(encoding []byte, fingerprint string) error {
+	want, err := parseFingerprint(fingerprint)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(encoding)
+	return compareFingerprints(hex.EncodeToString(sum[:]), want)
+}
+
+func parseFingerprint(fingerprint string) (string, error) {
+	want := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(fingerprint), "sha256:"))
+	if _, err := hex.DecodeString(want); err != nil || len(want) != 2*sha256.Size {
+		return "", fmt.Errorf("fingerprint %q is not a hex SHA-256", fingerprint)
+	}
+	return want, nil
+}
+
+func compareFingerprints(got, want string) error {
+	if got != want {
+		return fmt.Errorf("%w: key has fingerprint %s, pinned %s", ErrVKMismatch, got, want)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package envelope
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+const goldenDir = "../testdata/golden/range-16/"
+
+// fuzzCurves are the curves a fuzzed input is decoded on, picked by a byte.
+var fuzzCurves = []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761}
+
+// golden reads the golden envelope and verifying key.
+func golden(f *testing.F) (*Envelope, groth16.VerifyingKey) {
+	f.Helper()
+	data, err := os.ReadFile(goldenDir + "proof.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	env, err := Read(bytes.NewReader(data))
+	if err != nil {
+		f.Fatal(err)
+	}
+	raw, err := os.ReadFile(goldenDir + "vk.bin")
+	if err != nil {
+		f.Fatal(err)
+	}
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if _, err := vk.ReadFrom(bytes.NewReader(raw)); err != nil {
+		f.Fatal(err)
+	}
+	return env, vk
+}
+
+func FuzzDecodeProof(f *testing.F) {
+	env, _ := golden(f)
+	f.Add(uint8(0), env.Proof)
+	f.Add(uint8(1), env.Proof)
+	f.Add(uint8(0), env.Proof[:len(env.Proof)-1])
+	f.Add(uint8(0), []byte{})
+	f.Fuzz(func(t *testing.T, c uint8, data []byte) {
+		curve := fuzzCurves[int(c)%len(fuzzCurves)]
+		proof, err := decodeProof(curve, data)
+		if err == nil && proof == nil {
+			t.Fatal("decodeProof returned neither a proof nor an error")
+		}
+	})
+}
+
+func FuzzDecodePublicWitness(f *testing.F) {
+	env, _ := golden(f)
+	f.Add(uint8(0), env.PublicInputs)
+	f.Add(uint8(1), env.PublicInputs)
+	f.Add(uint8(0), []byte{0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1})
+	f.Add(uint8(0), []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, c uint8, data []byte) {
+		curve := fuzzCurves[int(c)%len(fuzzCurves)]
+		public, err := decodePublicWitness(curve, data)
+		if err != nil {
+			return
+		}
+		// Whatever decodes holds exactly the values its header announced.
+		values, err := public.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != len(data) {
+			t.Fatalf("decoded %d bytes into a witness of %d", len(data), len(values))
+		}
+	})
+}
+
+func FuzzEnvelopeRead(f *testing.F) {
+	env, vk := golden(f)
+	var buf bytes.Buffer
+	if _, err := env.WriteTo(&buf); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buf.Bytes())
+	f.Add([]byte(`{"version":1,"curve":"bn254","circuit":"range/16"}`))
+	f.Add([]byte(`{"proof":"AAAA","public_inputs":"////"}`))
+	f.Add([]byte(`[]`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		e, err := Read(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		// Verifying decodes the proof and public inputs of whatever parsed,
+		// which must return an error rather than panic or exhaust memory.
+		_ = e.Verify(env.Circuit, vk)
+	})
+}
@@ -0,0 +1,79 @@
+package envelope
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bw6-633"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761"
+)
+
+// Proofs and public witnesses arrive from untrusted parties. Their binary
+// encodings carry length prefixes which the gnark decoders allocate for
+// before reading any data, so a few forged bytes are enough to exhaust the
+// verifier's memory. The checks below reject any encoding whose prefixes do
+// not match its actual length, before it reaches a decoder.
+
+// pointSizes returns the compressed G1 and G2 point sizes on a curve.
+func pointSizes(curve ecc.ID) (g1, g2 int, err error) {
+	switch curve {
+	case ecc.BN254:
+		return bn254.SizeOfG1AffineCompressed, bn254.SizeOfG2AffineCompressed, nil
+	case ecc.BLS12_377:
+		return bls12377.SizeOfG1AffineCompressed, bls12377.SizeOfG2AffineCompressed, nil
+	case ecc.BLS12_381:
+		return bls12381.SizeOfG1AffineCompressed, bls12381.SizeOfG2AffineCompressed, nil
+	case ecc.BLS24_315:
+		return bls24315.SizeOfG1AffineCompressed, bls24315.SizeOfG2AffineCompressed, nil
+	case ecc.BLS24_317:
+		return bls24317.SizeOfG1AffineCompressed, bls24317.SizeOfG2AffineCompressed, nil
+	case ecc.BW6_633:
+		return bw6633.SizeOfG1AffineCompressed, bw6633.SizeOfG2AffineCompressed, nil
+	case ecc.BW6_761:
+		return bw6761.SizeOfG1AffineCompressed, bw6761.SizeOfG2AffineCompressed, nil
+	}
+	return 0, 0, fmt.Errorf("%w: no Groth16 proofs on %s", ErrCurveMismatch, curve)
+}
+
+// checkProofLayout validates a compressed Groth16 proof encoding:
+// Ar (G1) | Bs (G2) | Krs (G1) | n (uint32) | n commitments (G1) | PoK (G1).
+func checkProofLayout(curve ecc.ID, data []byte) error {
+	g1, g2, err := pointSizes(curve)
+	if err != nil {
+		return err
+	}
+	prefix := 2*g1 + g2
+	if len(data) < prefix+4 {
+		return fmt.Errorf("proof is %d bytes, too short for %s", len(data), curve)
+	}
+	n := uint64(binary.BigEndian.Uint32(data[prefix:]))
+	if want := uint64(prefix+4+g1) + n*uint64(g1); uint64(len(data)) != want {
+		return fmt.Errorf("proof is %d bytes, its header announces %d", len(data), want)
+	}
+	return nil
+}
+
+// checkWitnessLayout validates a public witness encoding:
+// nbPublic (uint32) | nbSecret (uint32) | n (uint32) | n field elements.
+func checkWitnessLayout(curve ecc.ID, data []byte) error {
+	if len(data) < 12 {
+		return fmt.Errorf("public inputs are %d bytes, too short", len(data))
+	}
+	nbPublic := uint64(binary.BigEndian.Uint32(data[0:]))
+	nbSecret := uint64(binary.BigEndian.Uint32(data[4:]))
+	n := uint64(binary.BigEndian.Uint32(data[8:]))
+	if nbSecret != 0 || n != nbPublic {
+		return fmt.Errorf("public inputs header announces %d public, %d secret and %d values", nbPublic, nbSecret, n)
+	}
+	elementSize := uint64(curve.ScalarField().BitLen()+7) / 8
+	if want := 12 + n*elementSize; uint64(len(data)) != want {
+		return fmt.Errorf("public inputs are %d bytes, their header announces %d", len(data), want)
+	}
+	return nil
+}
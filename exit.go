@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// Process exit codes, so scripts can tell a rejected proof from a broken run.
+const (
+	exitOK                 = 0
+	exitVerificationFailed = 1
+	exitUsage              = 2
+	exitInvalidInput       = 3
+	exitIO                 = 4
+	exitInternal           = 5
+)
+
+// exitCode maps an error returned by a command to its exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, zkp.ErrVerificationFailed):
+		return exitVerificationFailed
+	case errors.Is(err, zkp.ErrInvalidWitness):
+		return exitInvalidInput
+	case errors.Is(err, zkp.ErrIO):
+		return exitIO
+	default:
+		return exitInternal
+	}
+}
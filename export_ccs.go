@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/r1cs"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runExportCCS compiles a circuit and writes its constraint system as gnark
+// binary, iden3 .r1cs and a JSON description.
+func runExportCCS(args []string) error {
+	fs := flag.NewFlagSet("export-ccs", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	name := fs.String("circuit", "range", "circuit to export")
+	out := fs.String("out", "ccs-out", "directory to write circuit.ccs, circuit.r1cs and circuit.json to")
+	constraints := fs.Bool("constraints", false, "list every constraint in circuit.json")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	definition, ccs, err := compileCircuit(*name, *bits)
+	if err != nil {
+		return err
+	}
+	description, err := r1cs.Describe(definition.ID(), ccs, *constraints)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	if err := writeTo(filepath.Join(*out, "circuit.ccs"), ccs); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(*out, "circuit.json"), description); err != nil {
+		return err
+	}
+	files := []string{"circuit.ccs", "circuit.json"}
+
+	// Circuits with commitments have no .r1cs form; the other two files
+	// still describe them.
+	f, err := os.Create(filepath.Join(*out, "circuit.r1cs"))
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	err = r1cs.WriteIden3(f, ccs)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		report.printf("Skipped circuit.r1cs: %v\n", err)
+	} else {
+		files = append(files, "circuit.r1cs")
+	}
+
+	report.set("circuit", definition.ID())
+	report.set("constraints", description.Constraints)
+	report.set("wires", description.Wires)
+	report.set("out", *out)
+	report.set("files", files)
+	report.printf("Exported %s (%d constraints, %d wires) to %s: %v\n",
+		definition.ID(), description.Constraints, description.Wires.Total, *out, files)
+	return nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/interop"
+	"github.com/ananthanir/hello-zkp/snarkjs"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// interopFixture is fixture.json: the statement of the proof, and every
+// arkworks file in hex for verifiers that embed them in their own tests.
+// The proof verifies against Public and must not against PublicInvalid.
+type interopFixture struct {
+	Circuit       string                `json:"circuit"`
+	Curve         string                `json:"curve"`
+	PublicInputs  []circuit.PublicInput `json:"public_inputs"`
+	VerifyingKey  string                `json:"vk"`
+	Proof         string                `json:"proof"`
+	Public        string                `json:"public"`
+	PublicInvalid string                `json:"public_invalid"`
+}
+
+// Files written by export-interop, and read back by -check.
+const (
+	arkVKFile            = "vk.ark"
+	arkProofFile         = "proof.ark"
+	arkPublicFile        = "public.ark"
+	arkPublicInvalidFile = "public_invalid.ark"
+	interopFixtureFile   = "fixture.json"
+)
+
+// runExportInterop writes a verifying key and a proof as fixtures for
+// verifiers in other languages: arkworks' byte layout (see package interop),
+// snarkjs' JSON, and fixture.json. Each comes with public inputs that must
+// verify and ones, the first input plus one, that must not. The arkworks
+// files are then read back and checked, as -check does for an existing
+// directory.
+func runExportInterop(args []string) error {
+	fs := flag.NewFlagSet("export-interop", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	keys := fs.String("keys", "keys", "directory holding vk.bin")
+	in := fs.String("proof", "proof.json", "proof envelope to export")
+	name := fs.String("circuit", "range", "circuit the proof is for")
+	out := fs.String("out", "interop-out", "directory to write the fixtures to")
+	check := fs.Bool("check", false, "write nothing: read the arkworks fixtures in -out back and check them")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	if *check {
+		return checkInterop(*out)
+	}
+
+	definition, err := circuit.New(*name, *bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	vk, err := readVerifyingKey(*keys)
+	if err != nil {
+		return err
+	}
+	env, err := readEnvelope(*in)
+	if err != nil {
+		return err
+	}
+	proof, publicWitness, err := env.Open(definition.ID(), vk)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	inputs, err := circuit.ReadPublicInputs(definition, publicWitness)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	invalid, err := tamperPublic(publicWitness)
+	if err != nil {
+		return err
+	}
+
+	arkVK, err := interop.MarshalVerifyingKey(vk)
+	if err != nil {
+		return err
+	}
+	arkProof, err := interop.MarshalProof(proof)
+	if err != nil {
+		return err
+	}
+	arkPublic, err := interop.MarshalPublicInputs(publicWitness)
+	if err != nil {
+		return err
+	}
+	arkInvalid, err := interop.MarshalPublicInputs(invalid)
+	if err != nil {
+		return err
+	}
+	jsVK, err := snarkjs.ExportVerifyingKey(vk)
+	if err != nil {
+		return err
+	}
+	jsProof, err := snarkjs.ExportProof(proof)
+	if err != nil {
+		return err
+	}
+	jsPublic, err := snarkjs.ExportPublicInputs(publicWitness)
+	if err != nil {
+		return err
+	}
+	jsInvalid, err := snarkjs.ExportPublicInputs(invalid)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	binaries := []struct {
+		name string
+		data []byte
+	}{
+		{arkVKFile, arkVK},
+		{arkProofFile, arkProof},
+		{arkPublicFile, arkPublic},
+		{arkPublicInvalidFile, arkInvalid},
+	}
+	for _, f := range binaries {
+		if err := os.WriteFile(filepath.Join(*out, f.name), f.data, 0o644); err != nil {
+			return zkp.Wrap(zkp.ErrIO, err)
+		}
+	}
+	files := []struct {
+		name string
+		v    any
+	}{
+		{"verification_key.json", jsVK},
+		{"proof.json", jsProof},
+		{"public.json", jsPublic},
+		{"public_invalid.json", jsInvalid},
+		{interopFixtureFile, interopFixture{
+			Circuit:       definition.ID(),
+			Curve:         curve.String(),
+			PublicInputs:  inputs.Inputs,
+			VerifyingKey:  hex.EncodeToString(arkVK),
+			Proof:         hex.EncodeToString(arkProof),
+			Public:        hex.EncodeToString(arkPublic),
+			PublicInvalid: hex.EncodeToString(arkInvalid),
+		}},
+	}
+	for _, f := range files {
+		if err := writeJSON(filepath.Join(*out, f.name), f.v); err != nil {
+			return err
+		}
+	}
+	report.set("out", *out)
+	report.printf("Exported %s fixtures to %s\n", definition.ID(), *out)
+	return checkInterop(*out)
+}
+
+// checkInterop reads the arkworks fixtures in dir back, and checks that the
+// proof verifies against the public inputs, does not against the invalid
+// ones, and that fixture.json holds the same bytes.
+func checkInterop(dir string) error {
+	read := func(name string) ([]byte, error) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrIO, err)
+		}
+		return data, nil
+	}
+	var data [4][]byte
+	for i, name := range []string{arkVKFile, arkProofFile, arkPublicFile, arkPublicInvalidFile} {
+		var err error
+		if data[i], err = read(name); err != nil {
+			return err
+		}
+	}
+	vk, err := interop.UnmarshalVerifyingKey(data[0])
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	proof, err := interop.UnmarshalProof(data[1])
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	public, err := interop.UnmarshalPublicInputs(data[2])
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	invalid, err := interop.UnmarshalPublicInputs(data[3])
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+
+	raw, err := read(interopFixtureFile)
+	if err != nil {
+		return err
+	}
+	var fixture interopFixture
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, fmt.Errorf("%s: %w", interopFixtureFile, err))
+	}
+	for i, encoded := range []string{fixture.VerifyingKey, fixture.Proof, fixture.Public, fixture.PublicInvalid} {
+		if encoded != hex.EncodeToString(data[i]) {
+			return zkp.Wrap(zkp.ErrVerificationFailed, fmt.Errorf("%s does not match the .ark files", interopFixtureFile))
+		}
+	}
+
+	if err := zkp.Verify(proof, vk, public); err != nil {
+		report.println("Round trip: ❌ the decoded proof does not verify")
+		return err
+	}
+	if zkp.Verify(proof, vk, invalid) == nil {
+		report.println("Round trip: ❌ the decoded proof verifies against the invalid public inputs")
+		return zkp.Wrap(zkp.ErrVerificationFailed, errors.New("proof verifies against public_invalid"))
+	}
+	report.set("checked", dir)
+	report.printf("Round trip: ✅ %s decodes, verifies, and rejects the invalid public inputs\n", dir)
+	return nil
+}
+
+// tamperPublic returns a copy of a public witness with one added to its
+// first input, which no honest proof verifies against.
+func tamperPublic(public witness.Witness) (witness.Witness, error) {
+	values, ok := public.Vector().(fr.Vector)
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("%w: no %s public inputs to export", interop.ErrUnsupported, curve)
+	}
+	invalid, err := public.Public()
+	if err != nil {
+		return nil, err
+	}
+	tampered := invalid.Vector().(fr.Vector)
+	var one fr.Element
+	one.SetOne()
+	tampered[0].Add(&tampered[0], &one)
+	return invalid, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/ananthanir/hello-zkp/qr"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// qrLevels maps the -level flag to QR error correction levels.
+var qrLevels = map[string]qr.Level{"L": qr.L, "M": qr.M, "Q": qr.Q, "H": qr.H}
+
+// runExportQR renders a proof envelope as a QR code for offline
+// presentation. The code holds the compact text form of the envelope, which
+// verify accepts as scanned.
+func runExportQR(args []string) error {
+	fs := flag.NewFlagSet("export-qr", flag.ExitOnError)
+	in := fs.String("proof", "proof.json", "proof envelope to export")
+	out := fs.String("out", "proof.png", "PNG file to write the QR code to")
+	text := fs.String("text", "", "also write the encoded text, as a scanner reads it, to this file")
+	levelName := fs.String("level", "M", "error correction level: L, M, Q or H")
+	scale := fs.Int("scale", 8, "pixels per QR module")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	level, ok := qrLevels[strings.ToUpper(*levelName)]
+	if !ok {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("unknown error correction level %q", *levelName))
+	}
+	env, err := readEnvelope(*in)
+	if err != nil {
+		return err
+	}
+	payload, err := env.Compact()
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	code, err := qr.Encode(payload, level)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	if err := png.Encode(f, code.Image(*scale)); err != nil {
+		f.Close()
+		return zkp.Wrap(zkp.ErrIO, fmt.Errorf("write %s: %w", *out, err))
+	}
+	if err := f.Close(); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	if *text != "" {
+		if err := os.WriteFile(*text, []byte(payload+"\n"), 0o644); err != nil {
+			return zkp.Wrap(zkp.ErrIO, err)
+		}
+	}
+
+	report.set("out", *out)
+	report.set("version", code.Version)
+	report.set("payload", payload)
+	report.printf("QR code (version %d, %d×%d modules, %d characters) written to %s\n",
+		code.Version, code.Size(), code.Size(), len(payload), *out)
+	return nil
+}
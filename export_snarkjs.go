@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/snarkjs"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runExportSnarkjs converts a verifying key and a proof envelope into the
+// verification_key.json, proof.json and public.json files snarkjs reads.
+func runExportSnarkjs(args []string) error {
+	fs := flag.NewFlagSet("export-snarkjs", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	keys := fs.String("keys", "keys", "directory holding vk.bin")
+	in := fs.String("proof", "proof.json", "proof envelope to export")
+	name := fs.String("circuit", "range", "circuit the proof is for")
+	out := fs.String("out", "snarkjs-out", "directory to write the snarkjs files to")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	definition, err := circuit.New(*name, *bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	vk, err := readVerifyingKey(*keys)
+	if err != nil {
+		return err
+	}
+	env, err := readEnvelope(*in)
+	if err != nil {
+		return err
+	}
+	proof, publicWitness, err := env.Open(definition.ID(), vk)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+
+	exportedVK, err := snarkjs.ExportVerifyingKey(vk)
+	if err != nil {
+		return err
+	}
+	exportedProof, err := snarkjs.ExportProof(proof)
+	if err != nil {
+		return err
+	}
+	public, err := snarkjs.ExportPublicInputs(publicWitness)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	files := []struct {
+		name string
+		v    any
+	}{
+		{"verification_key.json", exportedVK},
+		{"proof.json", exportedProof},
+		{"public.json", public},
+	}
+	for _, f := range files {
+		if err := writeJSON(filepath.Join(*out, f.name), f.v); err != nil {
+			return err
+		}
+	}
+
+	report.set("out", *out)
+	report.printf("Exported to %s; check with: snarkjs groth16 verify %s %s %s\n", *out,
+		filepath.Join(*out, "verification_key.json"), filepath.Join(*out, "public.json"), filepath.Join(*out, "proof.json"))
+	return nil
+}
+
+// writeJSON writes v to path as indented JSON; failures wrap zkp.ErrIO.
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/snarkjs"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// solidityCalldata is the argument list of the exported contract's
+// verifyProof(uint256[8] proof, uint256[n] input).
+type solidityCalldata struct {
+	Proof []string `json:"proof"` // A, B, C in EIP-197 order, 0x-prefixed hex
+	Input []string `json:"input"` // public inputs, decimal
+}
+
+// runExportSolidity writes gnark's Solidity verifier for a verifying key and,
+// given a proof envelope, the calldata that verifies it on chain.
+func runExportSolidity(args []string) error {
+	fs := flag.NewFlagSet("export-solidity", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	keys := fs.String("keys", "keys", "directory holding vk.bin")
+	in := fs.String("proof", "proof.json", "proof envelope to write calldata for (empty: contract only)")
+	name := fs.String("circuit", "range", "circuit the proof is for")
+	out := fs.String("out", "solidity-out", "directory to write Verifier.sol and calldata.json to")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	definition, err := circuit.New(*name, *bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	vk, err := readVerifyingKey(*keys)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+
+	contract := filepath.Join(*out, "Verifier.sol")
+	f, err := os.Create(contract)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	defer f.Close()
+	if err := vk.ExportSolidity(f); err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("export Solidity verifier: %w", err))
+	}
+	if err := f.Close(); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	report.set("contract", contract)
+	report.printf("Export: ✅ wrote %s\n", contract)
+
+	if *in == "" {
+		return nil
+	}
+	env, err := readEnvelope(*in)
+	if err != nil {
+		return err
+	}
+	proof, publicWitness, err := env.Open(definition.ID(), vk)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	}
+	marshaler, ok := proof.(interface{ MarshalSolidity() []byte })
+	if !ok {
+		return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("Solidity calldata is only defined for BN254 proofs"))
+	}
+	raw := marshaler.MarshalSolidity()
+	if len(raw) != 8*32 {
+		return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("proofs with Pedersen commitments need a longer verifyProof signature"))
+	}
+	var calldata solidityCalldata
+	for i := 0; i < 8; i++ {
+		calldata.Proof = append(calldata.Proof, fmt.Sprintf("0x%x", raw[32*i:32*(i+1)]))
+	}
+	if calldata.Input, err = snarkjs.ExportPublicInputs(publicWitness); err != nil {
+		return err
+	}
+	path := filepath.Join(*out, "calldata.json")
+	if err := writeJSON(path, calldata); err != nil {
+		return err
+	}
+	report.set("calldata", path)
+	report.printf("Export: ✅ wrote %s for verifyProof(uint256[8], uint256[%d])\n", path, len(calldata.Input))
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/ananthanir/hello-zkp/vp"
+)
+
+// runExportVP wraps a proof envelope in a W3C Verifiable Presentation. verify
+// accepts the result in place of the envelope.
+func runExportVP(args []string) error {
+	fs := flag.NewFlagSet("export-vp", flag.ExitOnError)
+	in := fs.String("proof", "proof.json", "proof envelope to export")
+	out := fs.String("out", "presentation.json", "file to write the presentation to")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	env, err := readEnvelope(*in)
+	if err != nil {
+		return err
+	}
+	if err := writeTo(*out, vp.New(env, time.Now())); err != nil {
+		return err
+	}
+
+	report.set("out", *out)
+	report.printf("Presentation for circuit %s written to %s\n", env.Circuit, *out)
+	return nil
+}
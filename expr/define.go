@@ -0,0 +1,190 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/gadgets"
+)
+
+// ErrFalse is returned by Eval when the statement does not hold for the
+// given values, so no proof of it exists.
+var ErrFalse = errors.New("statement does not hold")
+
+// Eval evaluates the statement natively. Every variable must have a value
+// in [0, 2^bits). It returns ErrFalse if the statement does not hold.
+func (s *Statement) Eval(values map[string]*big.Int) error {
+	for _, name := range s.Vars() {
+		v, ok := values[name]
+		if !ok || v == nil {
+			return fmt.Errorf("%w: no value for %s", ErrType, name)
+		}
+		if v.Sign() < 0 || v.BitLen() > s.bits {
+			return fmt.Errorf("%w: %s = %s does not fit in %d bits", ErrType, name, v, s.bits)
+		}
+	}
+	if s.root.eval(values).Sign() == 0 {
+		return fmt.Errorf("%w: %s", ErrFalse, s.root)
+	}
+	return nil
+}
+
+// eval returns the value of an int node, or 1 or 0 for a condition.
+func (n *node) eval(values map[string]*big.Int) *big.Int {
+	b := func(ok bool) *big.Int {
+		if ok {
+			return big.NewInt(1)
+		}
+		return new(big.Int)
+	}
+	switch n.op {
+	case "num":
+		return n.value
+	case "var":
+		return values[n.name]
+	case "!":
+		return b(n.x.eval(values).Sign() == 0)
+	case "neg":
+		return new(big.Int).Neg(n.x.eval(values))
+	}
+	x, y := n.x.eval(values), n.y.eval(values)
+	switch n.op {
+	case "||":
+		return b(x.Sign() != 0 || y.Sign() != 0)
+	case "&&":
+		return b(x.Sign() != 0 && y.Sign() != 0)
+	case "==":
+		return b(x.Cmp(y) == 0)
+	case "!=":
+		return b(x.Cmp(y) != 0)
+	case "<":
+		return b(x.Cmp(y) < 0)
+	case "<=":
+		return b(x.Cmp(y) <= 0)
+	case ">":
+		return b(x.Cmp(y) > 0)
+	case ">=":
+		return b(x.Cmp(y) >= 0)
+	case "+":
+		return new(big.Int).Add(x, y)
+	case "-":
+		return new(big.Int).Sub(x, y)
+	case "*":
+		return new(big.Int).Mul(x, y)
+	case "/":
+		return new(big.Int).Quo(x, y)
+	default: // %
+		return new(big.Int).Rem(x, y)
+	}
+}
+
+// Define constrains the statement to hold for the given variables, which it
+// bounds to the statement's bit width first.
+func (s *Statement) Define(api frontend.API, vars map[string]frontend.Variable) error {
+	for _, name := range s.Vars() {
+		v, ok := vars[name]
+		if !ok {
+			return fmt.Errorf("%w: no variable %s", ErrType, name)
+		}
+		gadgets.AssertBitLen(api, v, s.bits)
+	}
+	holds, err := s.root.define(api, vars)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(holds, 1)
+	return nil
+}
+
+// define returns the wire of an int node, or a boolean wire for a
+// condition.
+func (n *node) define(api frontend.API, vars map[string]frontend.Variable) (frontend.Variable, error) {
+	switch n.op {
+	case "num":
+		return n.value, nil
+	case "var":
+		return vars[n.name], nil
+	}
+	x, err := n.x.define(api, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		return api.Sub(1, x), nil
+	case "neg":
+		return api.Neg(x), nil
+	}
+	y, err := n.y.define(api, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "||":
+		return api.Or(x, y), nil
+	case "&&":
+		return api.And(x, y), nil
+	case "==":
+		return api.IsZero(api.Sub(x, y)), nil
+	case "!=":
+		return api.Sub(1, api.IsZero(api.Sub(x, y))), nil
+	case "<=":
+		return lessOrEqual(api, x, n.x, y, n.y), nil
+	case ">=":
+		return lessOrEqual(api, y, n.y, x, n.x), nil
+	case "<":
+		return lessOrEqual(api, api.Add(x, 1), shift(n.x, 1), y, n.y), nil
+	case ">":
+		return lessOrEqual(api, api.Add(y, 1), shift(n.y, 1), x, n.x), nil
+	case "+":
+		return api.Add(x, y), nil
+	case "-":
+		return api.Sub(x, y), nil
+	case "*":
+		return api.Mul(x, y), nil
+	}
+	return divMod(api, n, x)
+}
+
+// lessOrEqual returns a boolean wire for x ≤ y, where the nodes give the
+// intervals x and y lie in. With 2^k above every |y - x| the intervals
+// allow, y - x + 2^k lies in (0, 2^(k+1)) and its top bit is set exactly
+// when y - x ≥ 0. The decomposition also proves the bound, since it fails
+// for any value outside the range.
+func lessOrEqual(api frontend.API, x frontend.Variable, nx *node, y frontend.Variable, ny *node) frontend.Variable {
+	lo := new(big.Int).Sub(ny.lo, nx.hi) // smallest y - x
+	hi := new(big.Int).Sub(ny.hi, nx.lo) // largest y - x
+	if lo.Sign() >= 0 {
+		return 1
+	}
+	if hi.Sign() < 0 {
+		return 0
+	}
+	k := max(new(big.Int).Neg(lo).BitLen(), hi.BitLen())
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(k))
+	bits := api.ToBinary(api.Add(api.Sub(y, x), offset), k+1)
+	return bits[k]
+}
+
+// shift returns a copy of an int node's interval moved by d, for x + 1.
+func shift(n *node, d int64) *node {
+	return &node{lo: new(big.Int).Add(n.lo, big.NewInt(d)), hi: new(big.Int).Add(n.hi, big.NewInt(d))}
+}
+
+// divMod constrains x = q·c + r with 0 ≤ r < c and q within the interval of
+// x / c, which makes q and r unique, and returns q for / or r for %. The
+// quotient and remainder are computed outside the circuit (see
+// gadgets.DivMod).
+func divMod(api frontend.API, n *node, x frontend.Variable) (frontend.Variable, error) {
+	q, r, err := gadgets.DivMod(api, x, n.y.value, new(big.Int).Quo(n.x.hi, n.y.value))
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "/" {
+		return q, nil
+	}
+	return r, nil
+}
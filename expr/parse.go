@@ -0,0 +1,382 @@
+// Package expr is a small statement language compiled into constraints at
+// runtime, so simple predicates need no new Go circuit type:
+//
+//	age >= min && age <= max && age % 2 == 0
+//
+// A statement is a boolean expression over non-negative integer variables
+// and literals, with Go's operators and precedence: || && ! == != < <= > >=
+// + - * / %. Division and remainder are by positive literals only. By
+// default age is the only private variable and every other one is public; a
+// "private a, b:" prefix names the private variables instead.
+//
+// Every variable is bounded to the circuit's bit width. Each subexpression
+// carries the interval of values it can take, computed when the statement is
+// parsed, which is what makes comparisons sound: they are done on values
+// known not to wrap around the scalar field.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// MaxValueBits bounds the magnitude of every intermediate value, far below
+// the scalar field order of every supported curve.
+const MaxValueBits = 200
+
+var (
+	// ErrSyntax is returned for statements that do not parse.
+	ErrSyntax = errors.New("syntax error")
+
+	// ErrType is returned for statements that parse but are ill-typed or
+	// out of bounds, such as age + (min < max) or a division by a variable.
+	ErrType = errors.New("invalid statement")
+)
+
+// kind is the type of an expression.
+type kind int
+
+const (
+	intKind kind = iota
+	boolKind
+)
+
+// node is a checked expression.
+type node struct {
+	op    string   // "num", "var", a unary ("!", "neg") or binary operator
+	value *big.Int // num
+	name  string   // var
+	x, y  *node
+
+	kind   kind
+	lo, hi *big.Int // interval of an int expression
+}
+
+// Statement is a parsed and checked statement.
+type Statement struct {
+	root    *node
+	private []string
+	public  []string
+	bits    int
+}
+
+// Parse parses a statement over variables of the given bit width.
+func Parse(src string, bits int) (*Statement, error) {
+	if bits < 1 || bits > MaxValueBits {
+		return nil, fmt.Errorf("%w: bit width %d", ErrType, bits)
+	}
+	declared := []string{"age"}
+	explicit := false
+	if rest, ok := strings.CutPrefix(strings.TrimSpace(src), "private "); ok {
+		decl, body, ok := strings.Cut(rest, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: private declaration without ':'", ErrSyntax)
+		}
+		declared, explicit = nil, true
+		for _, name := range strings.Split(decl, ",") {
+			name = strings.TrimSpace(name)
+			if !isIdent(name) {
+				return nil, fmt.Errorf("%w: %q is not a variable name", ErrSyntax, name)
+			}
+			declared = append(declared, name)
+		}
+		src = body
+	}
+
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, bits: bits, seen: map[string]bool{}}
+	root, err := p.expr(1)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, p.tokens[p.pos])
+	}
+	if root.kind != boolKind {
+		return nil, fmt.Errorf("%w: the statement is an int, not a condition", ErrType)
+	}
+
+	s := &Statement{root: root, bits: bits}
+	isPrivate := map[string]bool{}
+	for _, name := range declared {
+		if explicit && !p.seen[name] {
+			return nil, fmt.Errorf("%w: private variable %s is not used", ErrType, name)
+		}
+		if p.seen[name] && !isPrivate[name] {
+			isPrivate[name] = true
+			s.private = append(s.private, name)
+		}
+	}
+	for _, name := range p.order {
+		if !isPrivate[name] {
+			s.public = append(s.public, name)
+		}
+	}
+	if len(s.private) == 0 {
+		return nil, fmt.Errorf("%w: no private variable (age, or a private declaration)", ErrType)
+	}
+	return s, nil
+}
+
+// Private returns the private variables in order of first use.
+func (s *Statement) Private() []string {
+	return s.private
+}
+
+// Public returns the public variables in order of first use, which is the
+// order of the public inputs.
+func (s *Statement) Public() []string {
+	return s.public
+}
+
+// Vars returns every variable, the private ones first.
+func (s *Statement) Vars() []string {
+	return append(append([]string{}, s.private...), s.public...)
+}
+
+// Bits returns the bit width every variable is bounded to.
+func (s *Statement) Bits() int {
+	return s.bits
+}
+
+// String returns the statement in canonical form: fully parenthesized, with
+// its private declaration, so equal strings mean equal circuits.
+func (s *Statement) String() string {
+	return "private " + strings.Join(s.private, ", ") + ": " + s.root.String()
+}
+
+func (n *node) String() string {
+	switch n.op {
+	case "num":
+		return n.value.String()
+	case "var":
+		return n.name
+	case "!":
+		return "!" + n.x.String()
+	case "neg":
+		return "-" + n.x.String()
+	}
+	return "(" + n.x.String() + " " + n.op + " " + n.y.String() + ")"
+}
+
+// lex splits a statement into tokens.
+func lex(src string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(src) && unicode.IsDigit(rune(src[j])) {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		case c == '_' || unicode.IsLetter(c):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		default:
+			if i+1 < len(src) {
+				if two := src[i : i+2]; two == "&&" || two == "||" || two == "==" || two == "!=" || two == "<=" || two == ">=" {
+					tokens = append(tokens, two)
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("()!<>+-*/%", c) {
+				return nil, fmt.Errorf("%w: unexpected character %q", ErrSyntax, c)
+			}
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// isIdent reports whether s is a variable name.
+func isIdent(s string) bool {
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		return false
+	}
+	for _, c := range s {
+		if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return s != "private"
+}
+
+// precedence of the binary operators, as in Go.
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5, "%": 5,
+}
+
+// parser is a precedence-climbing parser that checks types and intervals
+// as it builds nodes.
+type parser struct {
+	tokens []string
+	pos    int
+	bits   int
+	seen   map[string]bool
+	order  []string
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// expr parses a binary expression whose operators bind at least as tightly
+// as minPrec.
+func (p *parser) expr(minPrec int) (*node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		prec, ok := precedence[op]
+		if !ok || prec < minPrec {
+			return x, nil
+		}
+		p.pos++
+		y, err := p.expr(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		if x, err = binary(op, x, y); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) unary() (*node, error) {
+	switch tok := p.peek(); {
+	case tok == "":
+		return nil, fmt.Errorf("%w: unexpected end of statement", ErrSyntax)
+	case tok == "!" || tok == "-":
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if tok == "!" {
+			if x.kind != boolKind {
+				return nil, fmt.Errorf("%w: ! applied to int %s", ErrType, x)
+			}
+			return &node{op: "!", x: x, kind: boolKind}, nil
+		}
+		if x.kind != intKind {
+			return nil, fmt.Errorf("%w: - applied to condition %s", ErrType, x)
+		}
+		return checked(&node{op: "neg", x: x, kind: intKind, lo: new(big.Int).Neg(x.hi), hi: new(big.Int).Neg(x.lo)})
+	case tok == "(":
+		p.pos++
+		x, err := p.expr(1)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("%w: missing )", ErrSyntax)
+		}
+		p.pos++
+		return x, nil
+	case unicode.IsDigit(rune(tok[0])):
+		p.pos++
+		v, _ := new(big.Int).SetString(tok, 10)
+		return checked(&node{op: "num", value: v, kind: intKind, lo: v, hi: v})
+	case isIdent(tok):
+		p.pos++
+		if !p.seen[tok] {
+			p.seen[tok] = true
+			p.order = append(p.order, tok)
+		}
+		max := new(big.Int).Lsh(big.NewInt(1), uint(p.bits))
+		return &node{op: "var", name: tok, kind: intKind, lo: new(big.Int), hi: max.Sub(max, big.NewInt(1))}, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, tok)
+	}
+}
+
+// binary checks and builds x op y.
+func binary(op string, x, y *node) (*node, error) {
+	n := &node{op: op, x: x, y: y}
+	switch op {
+	case "&&", "||":
+		if x.kind != boolKind || y.kind != boolKind {
+			return nil, fmt.Errorf("%w: %s needs two conditions in %s", ErrType, op, n)
+		}
+		n.kind = boolKind
+		return n, nil
+	case "==", "!=", "<", "<=", ">", ">=":
+		if x.kind != intKind || y.kind != intKind {
+			return nil, fmt.Errorf("%w: %s compares ints in %s", ErrType, op, n)
+		}
+		n.kind = boolKind
+		return n, nil
+	}
+
+	if x.kind != intKind || y.kind != intKind {
+		return nil, fmt.Errorf("%w: %s needs two ints in %s", ErrType, op, n)
+	}
+	n.kind = intKind
+	switch op {
+	case "+":
+		n.lo, n.hi = new(big.Int).Add(x.lo, y.lo), new(big.Int).Add(x.hi, y.hi)
+	case "-":
+		n.lo, n.hi = new(big.Int).Sub(x.lo, y.hi), new(big.Int).Sub(x.hi, y.lo)
+	case "*":
+		products := []*big.Int{
+			new(big.Int).Mul(x.lo, y.lo), new(big.Int).Mul(x.lo, y.hi),
+			new(big.Int).Mul(x.hi, y.lo), new(big.Int).Mul(x.hi, y.hi),
+		}
+		n.lo, n.hi = products[0], products[0]
+		for _, v := range products[1:] {
+			if v.Cmp(n.lo) < 0 {
+				n.lo = v
+			}
+			if v.Cmp(n.hi) > 0 {
+				n.hi = v
+			}
+		}
+	case "/", "%":
+		if y.op != "num" || y.value.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: %s is only defined by a positive literal in %s", ErrType, op, n)
+		}
+		if x.lo.Sign() < 0 {
+			return nil, fmt.Errorf("%w: %s of a value that may be negative in %s", ErrType, op, n)
+		}
+		if op == "/" {
+			n.lo, n.hi = new(big.Int).Quo(x.lo, y.value), new(big.Int).Quo(x.hi, y.value)
+		} else {
+			n.lo, n.hi = new(big.Int), new(big.Int).Sub(y.value, big.NewInt(1))
+		}
+	}
+	return checked(n)
+}
+
+// checked rejects int nodes whose values may grow past MaxValueBits.
+func checked(n *node) (*node, error) {
+	if n.lo.BitLen() > MaxValueBits || n.hi.BitLen() > MaxValueBits {
+		return nil, fmt.Errorf("%w: %s may exceed %d bits", ErrType, n, MaxValueBits)
+	}
+	return n, nil
+}
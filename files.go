@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/vp"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// curve is the pairing curve every command works on.
+var curve = ecc.BN254
+
+// File names used inside a -keys directory.
+const (
+	provingKeyFile   = "pk.bin"
+	verifyingKeyFile = "vk.bin"
+)
+
+// compileRange builds the range circuit for the given bit width and compiles
+// it to R1CS. Compilation is deterministic, so setup and prove can each do it.
+func compileRange(bits int) (*circuit.RangeCircuit, constraint.ConstraintSystem, error) {
+	definition, err := circuit.NewRangeCircuit(bits)
+	if err != nil {
+		return nil, nil, zkp.Wrap(zkp.ErrCompile, err)
+	}
+	ccs, err := prover.Compile(curve, definition)
+	if err != nil {
+		return nil, nil, err
+	}
+	return definition, ccs, nil
+}
+
+// compileCircuit is compileRange for any registered circuit, parameters
+// included (see circuit.ParseSpec).
+func compileCircuit(name string, bits int) (circuit.Definition, constraint.ConstraintSystem, error) {
+	spec, err := circuit.SpecFor(name, bits)
+	if err != nil {
+		return nil, nil, zkp.Wrap(zkp.ErrCompile, err)
+	}
+	return prover.CompileSpec(curve, spec)
+}
+
+// defaultCacheDir is where demo and prove-batch cache keys unless told
+// otherwise.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "hello-zkp", "keys")
+}
+
+// setupKeys runs setup, reusing keys cached in cacheDir for the same circuit
+// when cacheDir is not empty.
+func setupKeys(ccs constraint.ConstraintSystem, cacheDir string) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	if cacheDir == "" {
+		return prover.Setup(ccs)
+	}
+	pk, vk, hit, err := prover.CachedSetup(cacheDir, ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if hit {
+		report.printf("Setup: reused cached keys from %s\n", cacheDir)
+	}
+	return pk, vk, nil
+}
+
+// warnSeeded prints the warning every seeded command must show.
+func warnSeeded(cmd, risk string) {
+	fmt.Fprintf(os.Stderr, "WARNING: INSECURE, demo only: %s randomness derived from -seed; %s\n", cmd, risk)
+}
+
+// writeTo writes v to path; failures wrap zkp.ErrIO.
+func writeTo(path string, v io.WriterTo) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	defer f.Close()
+	if _, err := v.WriteTo(f); err != nil {
+		return zkp.Wrap(zkp.ErrIO, fmt.Errorf("write %s: %w", path, err))
+	}
+	return nil
+}
+
+// readFrom fills v from path; failures wrap zkp.ErrIO.
+func readFrom(path string, v io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	defer f.Close()
+	if _, err := v.ReadFrom(f); err != nil {
+		return zkp.Wrap(zkp.ErrIO, fmt.Errorf("read %s: %w", path, err))
+	}
+	return nil
+}
+
+// addCompressFlag registers -compress, picking the encoding keys are
+// written in.
+func addCompressFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("compress", true, "write keys with compressed points (false: raw, about twice the size but faster to read)")
+}
+
+// writeKeys writes pk.bin and vk.bin, each with its checksum file, in the
+// given encoding. Failures wrap zkp.ErrIO.
+func writeKeys(dir string, pk groth16.ProvingKey, vk groth16.VerifyingKey, enc keyfile.Encoding) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	if _, err := keyfile.Write(filepath.Join(dir, provingKeyFile), pk, enc); err != nil {
+		return err
+	}
+	_, err := keyfile.Write(filepath.Join(dir, verifyingKeyFile), vk, enc)
+	return err
+}
+
+// readProvingKey reads pk.bin, checking its checksum file if there is one.
+func readProvingKey(dir string) (groth16.ProvingKey, error) {
+	pk := groth16.NewProvingKey(curve)
+	if err := keyfile.Read(filepath.Join(dir, provingKeyFile), pk); err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+// readVerifyingKey reads vk.bin, checking its checksum file if there is one.
+func readVerifyingKey(dir string) (groth16.VerifyingKey, error) {
+	vk := groth16.NewVerifyingKey(curve)
+	if err := keyfile.Read(filepath.Join(dir, verifyingKeyFile), vk); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+func writeEnvelope(path string, env *envelope.Envelope) error {
+	return writeTo(path, env)
+}
+
+// readEnvelope reads an envelope file, a Verifiable Presentation wrapping
+// one, or the compact text scanned from a QR code. A file that cannot be
+// read is an I/O failure; one that cannot be parsed is a proof that fails
+// verification.
+func readEnvelope(path string) (*envelope.Envelope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	env, err := parseEnvelope(data)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrVerificationFailed, fmt.Errorf("%s: %w", path, err))
+	}
+	return env, nil
+}
+
+func parseEnvelope(data []byte) (*envelope.Envelope, error) {
+	if text := string(bytes.TrimSpace(data)); strings.HasPrefix(text, envelope.CompactPrefix) {
+		return envelope.ParseCompact(text)
+	}
+	if !vp.IsPresentation(data) {
+		return envelope.Read(bytes.NewReader(data))
+	}
+	p, err := vp.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return p.Envelope()
+}
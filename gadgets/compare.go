@@ -0,0 +1,44 @@
+// Package gadgets collects small, reusable constraint helpers shared by the
+// circuits in this repository.
+package gadgets
+
+import "github.com/consensys/gnark/frontend"
+
+// AssertBitLen constrains 0 ≤ v < 2^bits.
+//
+// api.ToBinary already asserts that every output is boolean and that the bits
+// recompose to v, so no extra constraints are needed on top of it. We use the
+// plain decomposition rather than the commitment-based rangecheck so that the
+// resulting Groth16 proofs carry no Pedersen commitment.
+func AssertBitLen(api frontend.API, v frontend.Variable, bits int) {
+	api.ToBinary(v, bits)
+}
+
+// AssertLessOrEqual constrains a ≤ b over the whole scalar field.
+//
+// This is the generic comparator: it works for any pair of field elements but
+// costs a full-width decomposition of both operands. Prefer
+// AssertLessOrEqualBounded when both values are known to be small.
+func AssertLessOrEqual(api frontend.API, a, b frontend.Variable) {
+	api.AssertIsLessOrEqual(a, b)
+}
+
+// AssertLessOrEqualBounded constrains a ≤ b, assuming both a and b are already
+// constrained to [0, 2^bits). Under that assumption b - a can only fit in
+// 'bits' bits if it did not wrap around the field, i.e. if a ≤ b.
+func AssertLessOrEqualBounded(api frontend.API, a, b frontend.Variable, bits int) {
+	AssertBitLen(api, api.Sub(b, a), bits)
+}
+
+// AssertInRange constrains min ≤ v ≤ max, with v, min and max all in
+// [0, 2^bits). Bounding the operands first is what makes the two difference
+// checks overflow-safe: without it a huge (or "negative") value could wrap
+// around the field and still produce a small difference.
+func AssertInRange(api frontend.API, v, min, max frontend.Variable, bits int) {
+	AssertBitLen(api, v, bits)
+	AssertBitLen(api, min, bits)
+	AssertBitLen(api, max, bits)
+
+	AssertLessOrEqualBounded(api, min, v, bits) // v - min ≥ 0  ⇒ v ≥ min
+	AssertLessOrEqualBounded(api, v, max, bits) // max - v ≥ 0  ⇒ v ≤ max
+}
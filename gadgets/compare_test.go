@@ -0,0 +1,121 @@
+package gadgets
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const testBits = 8
+
+// minusOne is -1 in the BN254 scalar field, the value an underflowing
+// subtraction leaves behind.
+var minusOne = new(big.Int).Sub(ecc.BN254.ScalarField(), big.NewInt(1))
+
+type bitLenCircuit struct {
+	V frontend.Variable
+}
+
+func (c *bitLenCircuit) Define(api frontend.API) error {
+	AssertBitLen(api, c.V, testBits)
+	return nil
+}
+
+type lessOrEqualBoundedCircuit struct {
+	A, B frontend.Variable
+}
+
+func (c *lessOrEqualBoundedCircuit) Define(api frontend.API) error {
+	AssertLessOrEqualBounded(api, c.A, c.B, testBits)
+	return nil
+}
+
+type inRangeCircuit struct {
+	V, Min, Max frontend.Variable
+}
+
+func (c *inRangeCircuit) Define(api frontend.API) error {
+	AssertInRange(api, c.V, c.Min, c.Max, testBits)
+	return nil
+}
+
+func solved(t *testing.T, circuit, assignment frontend.Circuit) bool {
+	t.Helper()
+	return test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()) == nil
+}
+
+func TestAssertBitLen(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		v    any
+		ok   bool
+	}{
+		{"zero", 0, true},
+		{"one", 1, true},
+		{"largest", 1<<testBits - 1, true},
+		{"2^bits", 1 << testBits, false},
+		{"2^bits + 1", 1<<testBits + 1, false},
+		{"minus one", minusOne, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := solved(t, &bitLenCircuit{}, &bitLenCircuit{V: tc.v}); got != tc.ok {
+				t.Errorf("AssertBitLen(%v, %d) satisfied = %t, want %t", tc.v, testBits, got, tc.ok)
+			}
+		})
+	}
+}
+
+func TestAssertLessOrEqualBounded(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		a, b any
+		ok   bool
+	}{
+		{"equal zero", 0, 0, true},
+		{"equal", 42, 42, true},
+		{"less", 17, 18, true},
+		{"full width", 0, 1<<testBits - 1, true},
+		{"greater by one", 19, 18, false},
+		{"greater, full width", 1<<testBits - 1, 0, false},
+		// The bound is a precondition: an operand already wrapped around
+		// the field passes, which is why AssertInRange bounds them first.
+		{"unbounded a", minusOne, 0, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := solved(t, &lessOrEqualBoundedCircuit{}, &lessOrEqualBoundedCircuit{A: tc.a, B: tc.b}); got != tc.ok {
+				t.Errorf("AssertLessOrEqualBounded(%v, %v) satisfied = %t, want %t", tc.a, tc.b, got, tc.ok)
+			}
+		})
+	}
+}
+
+func TestAssertInRange(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		v, min, max any
+		ok          bool
+	}{
+		{"inside", 25, 18, 30, true},
+		{"at min", 18, 18, 30, true},
+		{"at max", 30, 18, 30, true},
+		{"single value", 7, 7, 7, true},
+		{"whole width", 1<<testBits - 1, 0, 1<<testBits - 1, true},
+		{"below min", 17, 18, 30, false},
+		{"above max", 31, 18, 30, false},
+		{"negative v", minusOne, 0, 30, false},
+		{"v overflows bits", 1 << testBits, 0, 1<<testBits - 1, false},
+		{"negative min", 5, minusOne, 30, false},
+		{"max overflows bits", 5, 0, 1 << testBits, false},
+		{"min above max", 20, 30, 18, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := solved(t, &inRangeCircuit{}, &inRangeCircuit{V: tc.v, Min: tc.min, Max: tc.max})
+			if got != tc.ok {
+				t.Errorf("AssertInRange(%v, %v, %v) satisfied = %t, want %t", tc.v, tc.min, tc.max, got, tc.ok)
+			}
+		})
+	}
+}
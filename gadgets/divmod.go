@@ -0,0 +1,38 @@
+package gadgets
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/hints"
+)
+
+// DivMod returns the quotient q and remainder r of x by the constant c ≥ 1.
+// The solver computes them with hints.DivMod; the circuit only checks that
+//
+//	x = q·c + r,  0 ≤ q ≤ qmax,  0 ≤ r < c
+//
+// which makes them unique, and bounds x to [0, (qmax+1)·c): no other x
+// satisfies it. Both bounds matter: without them a prover could pick an r
+// past c, or a q that wraps around the field, and still satisfy the
+// equation.
+func DivMod(api frontend.API, x frontend.Variable, c, qmax *big.Int) (q, r frontend.Variable, err error) {
+	out, err := api.Compiler().NewHint(hints.DivMod, 2, x, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, r = out[0], out[1]
+	assertAtMost(api, q, qmax)
+	assertAtMost(api, r, new(big.Int).Sub(c, big.NewInt(1)))
+	api.AssertIsEqual(x, api.Add(api.Mul(q, c), r))
+	return q, r, nil
+}
+
+// assertAtMost constrains 0 ≤ v ≤ bound for a constant bound ≥ 0, with
+// one decomposition of v and one of bound - v, each as wide as bound.
+func assertAtMost(api frontend.API, v frontend.Variable, bound *big.Int) {
+	bits := max(bound.BitLen(), 1)
+	AssertBitLen(api, v, bits)
+	AssertLessOrEqualBounded(api, v, bound, bits)
+}
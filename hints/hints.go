@@ -0,0 +1,44 @@
+// Package hints holds the gnark hint functions used by the circuits in this
+// repository.
+//
+// A hint computes a value outside the circuit, from wires the solver already
+// knows, and hands it back as a new private wire; the circuit then checks
+// the value with constraints, which is far cheaper than computing it with
+// them. Integer division is the typical case: the quotient and remainder of
+// x by c take a handful of constraints to check (x = q·c + r, 0 ≤ r < c) but
+// many more to derive. A hint constrains nothing itself, so a circuit that
+// does not check its output proves nothing about it.
+//
+// The prover runs the hints a constraint system depends on, so they must be
+// registered in the proving process: importing this package registers All
+// with gnark's solver. Hints from elsewhere are registered with
+// solver.RegisterHint, or passed for one proof with prover.WithHints.
+// Verifying never runs a hint.
+package hints
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(All()...)
+}
+
+// All returns the hints of this package.
+func All() []solver.Hint {
+	return []solver.Hint{DivMod}
+}
+
+// DivMod computes the quotient and remainder of inputs[0] by inputs[1], into
+// outputs[0] and outputs[1]. The inputs are the field elements taken as
+// non-negative integers.
+func DivMod(_ *big.Int, inputs, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 2 || inputs[1].Sign() == 0 {
+		return errors.New("DivMod: expected a dividend and a non-zero divisor")
+	}
+	outputs[0].QuoRem(inputs[0], inputs[1], outputs[1])
+	return nil
+}
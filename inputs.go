@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ananthanir/hello-zkp/secret"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// envPrefix starts the environment variables inputs can be read from, e.g.
+// HELLO_ZKP_AGE.
+const envPrefix = "HELLO_ZKP_"
+
+// inputSource is somewhere the demo can read its inputs from.
+type inputSource interface {
+	// value returns the named input, with ok false if the source does not
+	// have it.
+	value(name string) (v int, ok bool, err error)
+}
+
+// privateInputs are the inputs never echoed: they can be read from a secret
+// reference with -<name>-from and are typed without echo at a prompt.
+var privateInputs = map[string]bool{"age": true}
+
+// inputFlags are the demo flags naming where inputs come from.
+type inputFlags struct {
+	fs      *flag.FlagSet
+	values  map[string]*int
+	from    map[string]*string
+	witness *string
+}
+
+func addInputFlags(fs *flag.FlagSet, names ...string) *inputFlags {
+	f := &inputFlags{fs: fs, values: map[string]*int{}, from: map[string]*string{}}
+	for _, name := range names {
+		f.values[name] = fs.Int(name, 0, fmt.Sprintf("%s input (default: from -witness, $%s%s or stdin)", name, envPrefix, strings.ToUpper(name)))
+		if privateInputs[name] {
+			f.from[name] = fs.String(name+"-from", "", fmt.Sprintf("read the private %s from %s", name, secret.RefHelp))
+		}
+	}
+	f.witness = fs.String("witness", "", "JSON file holding the inputs, mode 0600, e.g. {\"age\":25,\"min\":18,\"max\":30}")
+	return f
+}
+
+// sources returns the input sources in order of precedence: flags set on
+// the command line, secret references given with -<name>-from, the
+// -witness file, the environment, and finally stdin, which is prompted for
+// when it is a terminal.
+func (f *inputFlags) sources() ([]inputSource, error) {
+	set := flagSource{}
+	f.fs.Visit(func(fl *flag.Flag) {
+		if v, ok := f.values[fl.Name]; ok {
+			set[fl.Name] = *v
+		}
+	})
+	for name, ref := range f.from {
+		if *ref == "" {
+			continue
+		}
+		if _, ok := set[name]; ok {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("-%s and -%s-from are exclusive", name, name))
+		}
+		v, err := secret.ReadInt(*ref)
+		if err != nil {
+			return nil, err
+		}
+		set[name] = v
+	}
+	sources := []inputSource{set}
+	if *f.witness != "" {
+		file, err := readWitnessFile(*f.witness)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, file)
+	}
+	return append(sources, envSource{}, newStdinSource(os.Stdin)), nil
+}
+
+// resolveInputs reads each named input from the first source that has it.
+// Errors wrap zkp.ErrInvalidWitness, or zkp.ErrIO if stdin failed.
+func resolveInputs(sources []inputSource, names ...string) (map[string]int, error) {
+	values := make(map[string]int, len(names))
+	for _, name := range names {
+		found := false
+		for _, src := range sources {
+			v, ok, err := src.value(name)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				values[name], found = v, true
+				break
+			}
+		}
+		if !found {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("no value for %s", name))
+		}
+	}
+	return values, nil
+}
+
+// flagSource holds the inputs given as flags.
+type flagSource map[string]int
+
+func (s flagSource) value(name string) (int, bool, error) {
+	v, ok := s[name]
+	return v, ok, nil
+}
+
+// fileSource holds the inputs of a witness file.
+type fileSource map[string]int
+
+func readWitnessFile(path string) (fileSource, error) {
+	if err := secret.CheckFile(path); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	var s fileSource
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("parse %s: %w", path, err))
+	}
+	return s, nil
+}
+
+func (s fileSource) value(name string) (int, bool, error) {
+	v, ok := s[name]
+	return v, ok, nil
+}
+
+// envSource reads inputs from HELLO_ZKP_<NAME> variables.
+type envSource struct{}
+
+func (envSource) value(name string) (int, bool, error) {
+	key := envPrefix + strings.ToUpper(name)
+	s, ok := os.LookupEnv(key)
+	if !ok {
+		return 0, false, nil
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, false, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("$%s: %w", key, err))
+	}
+	return v, true, nil
+}
+
+// stdinSource reads whitespace-separated inputs from stdin, in the order
+// they are asked for, prompting when stdin is a terminal. Private inputs are
+// typed without echo.
+type stdinSource struct {
+	f      *os.File
+	r      *bufio.Reader
+	prompt bool
+}
+
+func newStdinSource(f *os.File) *stdinSource {
+	info, err := f.Stat()
+	terminal := err == nil && info.Mode()&os.ModeCharDevice != 0
+	return &stdinSource{f: f, r: bufio.NewReader(f), prompt: terminal}
+}
+
+// prompts are the questions asked for the known inputs on a terminal.
+var prompts = map[string]string{
+	"age": "Enter Age (private, not echoed): ",
+	"min": "Enter Min bound (public): ",
+	"max": "Enter Max bound (public): ",
+}
+
+func (s *stdinSource) value(name string) (int, bool, error) {
+	if s.prompt {
+		prompt, ok := prompts[name]
+		if !ok {
+			prompt = fmt.Sprintf("Enter %s: ", name)
+		}
+		report.printf("%s", prompt)
+	}
+	var v int
+	scan := func() error {
+		_, err := fmt.Fscan(s.r, &v)
+		return err
+	}
+	var err error
+	if s.prompt && privateInputs[name] {
+		err = readHidden(s.f, scan)
+	} else {
+		err = scan()
+	}
+	if err != nil {
+		return 0, false, zkp.Wrap(zkp.ErrIO, fmt.Errorf("read %s: %w", name, err))
+	}
+	return v, true, nil
+}
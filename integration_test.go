@@ -0,0 +1,267 @@
+//go:build integration
+
+// The integration suite runs the built hello-zkp binary end to end, as a
+// script would, and checks its exit status and -json output:
+//
+//	go test -tags integration .
+package main_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// bin is the binary under test, built once by TestMain.
+var bin string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "hello-zkp-integration-")
+	if err != nil {
+		panic(err)
+	}
+	bin = filepath.Join(dir, "hello-zkp")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		os.RemoveAll(dir)
+		panic(err)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// result is what a command run with -json reports.
+type result struct {
+	Exit     int
+	Command  string `json:"command"`
+	OK       bool   `json:"ok"`
+	Verified bool   `json:"verified"`
+	Error    string `json:"error"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// run runs the binary in dir with args and -json, and decodes its output.
+func run(t *testing.T, dir string, args ...string) result {
+	t.Helper()
+	cmd := exec.Command(bin, append(args, "-json")...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	var r result
+	var exit *exec.ExitError
+	switch {
+	case errors.As(err, &exit):
+		r.Exit = exit.ExitCode()
+	case err != nil:
+		t.Fatalf("%s: %v", strings.Join(args, " "), err)
+	}
+	if err := json.Unmarshal(out, &r); err != nil {
+		t.Fatalf("%s: output is not JSON: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	if r.Exit != r.ExitCode {
+		t.Errorf("%s: exited %d, JSON says %d", strings.Join(args, " "), r.Exit, r.ExitCode)
+	}
+	return r
+}
+
+// fixture is a directory with keys, a second unrelated setup and a proof of
+// 18 ≤ 25 ≤ 30, shared by the tests that only read it.
+func fixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"setup", "-keys", "keys"},
+		{"setup", "-keys", "other"},
+		{"prove", "-keys", "keys", "-age", "25", "-min", "18", "-max", "30", "-out", "proof.json"},
+	} {
+		if r := run(t, dir, args...); r.Exit != 0 || !r.OK {
+			t.Fatalf("%s: exit %d: %s", strings.Join(args, " "), r.Exit, r.Error)
+		}
+	}
+	return dir
+}
+
+// editEnvelope writes a copy of dir/proof.json, changed by f, to dir/name.
+func editEnvelope(t *testing.T, dir, name string, f func(env map[string]any)) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "proof.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env map[string]any
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatal(err)
+	}
+	f(env)
+	if data, err = json.Marshal(env); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// editBytes applies f to a base64 field of an envelope.
+func editBytes(t *testing.T, env map[string]any, field string, f func(b []byte)) {
+	t.Helper()
+	b, err := base64.StdEncoding.DecodeString(env[field].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f(b)
+	env[field] = base64.StdEncoding.EncodeToString(b)
+}
+
+func TestVerify(t *testing.T) {
+	dir := fixture(t)
+
+	var otherVK struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	out, err := exec.Command(bin, "vk", "fingerprint", "-keys", filepath.Join(dir, "other"), "-json").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(out, &otherVK); err != nil {
+		t.Fatal(err)
+	}
+
+	editEnvelope(t, dir, "tampered.json", func(env map[string]any) {
+		// The last byte of the proof, inside C.
+		editBytes(t, env, "proof", func(b []byte) { b[len(b)-1] ^= 1 })
+	})
+	editEnvelope(t, dir, "swapped.json", func(env map[string]any) {
+		// Min and Max, the first two elements after the 12-byte header.
+		editBytes(t, env, "public_inputs", func(b []byte) {
+			var min [32]byte
+			copy(min[:], b[12:44])
+			copy(b[12:44], b[44:76])
+			copy(b[44:76], min[:])
+		})
+	})
+	editEnvelope(t, dir, "relabelled.json", func(env map[string]any) {
+		// Claims the other key, so that only the pairing can tell.
+		env["vk_hash"] = otherVK.Fingerprint
+	})
+	editEnvelope(t, dir, "other-circuit.json", func(env map[string]any) {
+		env["circuit"] = "range/8"
+	})
+	if err := os.WriteFile(filepath.Join(dir, "malformed.json"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		exit     int
+		verified bool
+	}{
+		{"valid", []string{"-proof", "proof.json", "-min", "18", "-max", "30"}, 0, true},
+		{"valid, unpinned", []string{"-proof", "proof.json"}, 0, true},
+		{"tampered proof", []string{"-proof", "tampered.json", "-min", "18", "-max", "30"}, 1, false},
+		{"swapped public inputs", []string{"-proof", "swapped.json", "-min", "18", "-max", "30"}, 1, false},
+		{"swapped public inputs, unpinned", []string{"-proof", "swapped.json"}, 1, false},
+		{"other bounds pinned", []string{"-proof", "proof.json", "-min", "19", "-max", "30"}, 1, false},
+		{"wrong vk", []string{"-proof", "proof.json", "-keys", "other", "-min", "18", "-max", "30"}, 1, false},
+		{"wrong vk, relabelled envelope", []string{"-proof", "relabelled.json", "-keys", "other", "-min", "18", "-max", "30"}, 1, false},
+		{"wrong vk fingerprint", []string{"-proof", "proof.json", "-vk-fingerprint", otherVK.Fingerprint}, 1, false},
+		{"other circuit", []string{"-proof", "other-circuit.json", "-min", "18", "-max", "30"}, 1, false},
+		{"malformed envelope", []string{"-proof", "malformed.json"}, 1, false},
+		{"missing envelope", []string{"-proof", "missing.json"}, 4, false},
+		{"missing vk", []string{"-proof", "proof.json", "-keys", "missing"}, 4, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			args := append([]string{"verify"}, tc.args...)
+			if !strings.Contains(strings.Join(tc.args, " "), "-keys") {
+				args = append(args, "-keys", "keys")
+			}
+			r := run(t, dir, args...)
+			if r.Exit != tc.exit {
+				t.Errorf("exit %d, want %d (%s)", r.Exit, tc.exit, r.Error)
+			}
+			if r.Command != "verify" || r.Verified != tc.verified || r.OK != (tc.exit == 0) {
+				t.Errorf("JSON: command %q, verified %t, ok %t; want verify, %t, %t", r.Command, r.Verified, r.OK, tc.verified, tc.exit == 0)
+			}
+			if tc.exit != 0 && r.Error == "" {
+				t.Error("no error in the JSON output")
+			}
+		})
+	}
+}
+
+func TestProveRejects(t *testing.T) {
+	dir := fixture(t)
+	for _, tc := range []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{"age outside the range", []string{"-age", "40", "-min", "18", "-max", "30"}, 3},
+		{"negative age", []string{"-age", "-1", "-min", "0", "-max", "30"}, 3},
+		{"min above max", []string{"-age", "25", "-min", "30", "-max", "18"}, 3},
+		{"missing keys", []string{"-keys", "missing", "-age", "25", "-min", "18", "-max", "30"}, 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			args := append([]string{"prove", "-out", "refused.json"}, tc.args...)
+			if !strings.Contains(strings.Join(tc.args, " "), "-keys") {
+				args = append(args, "-keys", "keys")
+			}
+			r := run(t, dir, args...)
+			if r.Exit != tc.exit || r.OK || r.Error == "" {
+				t.Errorf("exit %d, ok %t, error %q; want exit %d with an error", r.Exit, r.OK, r.Error, tc.exit)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "refused.json")); err == nil {
+				t.Error("a refused proof was written")
+			}
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	cmd := exec.Command(bin, "verify", "-no-such-flag")
+	cmd.Dir = t.TempDir()
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+		t.Errorf("unknown flag: %v, want exit status 2", err)
+	}
+}
+
+// TestVerifyDomain checks -domain against a circuit without a Domain input:
+// even empty, it is a usage the verifier must be told about, not a crash.
+func TestVerifyDomain(t *testing.T) {
+	dir := fixture(t)
+	for _, args := range [][]string{
+		{"setup", "-circuit", "committed-range", "-keys", "committed"},
+		{"commit", "-age", "30"},
+		{"prove", "-circuit", "committed-range", "-keys", "committed", "-min", "18", "-max", "99", "-out", "committed.json"},
+	} {
+		if r := run(t, dir, args...); r.Exit != 0 {
+			t.Fatalf("%s: exit %d: %s", strings.Join(args, " "), r.Exit, r.Error)
+		}
+	}
+	for _, tc := range []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{"range, empty domain", []string{"-keys", "keys", "-proof", "proof.json", "-domain", ""}, 0},
+		{"range, other domain", []string{"-keys", "keys", "-proof", "proof.json", "-domain", "other-app"}, 1},
+		{"no Domain input, empty domain", []string{"-circuit", "committed-range", "-keys", "committed", "-proof", "committed.json", "-domain", ""}, 3},
+		{"no Domain input, domain", []string{"-circuit", "committed-range", "-keys", "committed", "-proof", "committed.json", "-domain", "other-app"}, 3},
+		{"no Domain input, unpinned", []string{"-circuit", "committed-range", "-keys", "committed", "-proof", "committed.json"}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := run(t, dir, append([]string{"verify"}, tc.args...)...)
+			if r.Exit != tc.exit {
+				t.Errorf("exit %d, want %d (%s)", r.Exit, tc.exit, r.Error)
+			}
+		})
+	}
+}
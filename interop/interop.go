@@ -0,0 +1,326 @@
+// Package interop encodes BN254 Groth16 verifying keys, proofs and public
+// inputs in the byte layout of arkworks, so that proofs made here can be
+// checked by ark-groth16, and decodes them back for round-trip checks.
+// JavaScript verifiers take snarkjs' JSON instead (package snarkjs).
+//
+// The layout is ark-serialize's uncompressed CanonicalSerialize, as read by
+// deserialize_uncompressed:
+//
+//	Fq, Fr   32 bytes, little-endian, canonical (not Montgomery) form
+//	G1       x ‖ y                                          64 bytes
+//	G2       x.c0 ‖ x.c1 ‖ y.c0 ‖ y.c1                     128 bytes
+//	Vec<T>   length as u64 little-endian ‖ the elements
+//
+// The two top bits of the last byte of a point carry flags: bit 7 is set
+// when y is the larger of y and -y (c1 compared first for G2), bit 6 marks
+// the point at infinity, which no key or proof here contains. gnark's
+// [A0, A1] are arkworks' (c0, c1).
+//
+//	proof    A (G1) ‖ B (G2) ‖ C (G1)                      256 bytes
+//	vk       α (G1) ‖ β (G2) ‖ γ (G2) ‖ δ (G2) ‖ Vec<G1> IC
+//	public   Vec<Fr>, in declaration order, without the constant 1
+//
+// IC[0] is the constant wire, as gnark's K[0]. Like snarkjs, arkworks has no
+// notion of gnark's Pedersen commitments, so circuits with commitments are
+// not exportable.
+package interop
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+const (
+	elementSize = 32
+	g1Size      = 2 * elementSize
+	g2Size      = 4 * elementSize
+
+	// ProofSize is the length of an encoded proof.
+	ProofSize = 2*g1Size + g2Size
+
+	flagNegative = 1 << 7
+	flagInfinity = 1 << 6
+	flagMask     = flagNegative | flagInfinity
+)
+
+var (
+	// ErrUnsupported is returned for artifacts the layout cannot represent.
+	ErrUnsupported = errors.New("not exportable to arkworks")
+
+	// ErrEncoding is returned for bytes that do not decode: wrong length,
+	// values outside the field or points off the curve.
+	ErrEncoding = errors.New("invalid arkworks encoding")
+)
+
+// MarshalVerifyingKey encodes a verifying key as ark-groth16's VerifyingKey.
+func MarshalVerifyingKey(vk groth16.VerifyingKey) ([]byte, error) {
+	k, ok := vk.(*groth16bn254.VerifyingKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: verifying key is for %s, only %s is supported", ErrUnsupported, vk.CurveID(), ecc.BN254)
+	}
+	if len(k.CommitmentKeys) > 0 {
+		return nil, fmt.Errorf("%w: circuit uses Pedersen commitments", ErrUnsupported)
+	}
+	b := appendG1(nil, &k.G1.Alpha)
+	b = appendG2(b, &k.G2.Beta)
+	b = appendG2(b, &k.G2.Gamma)
+	b = appendG2(b, &k.G2.Delta)
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(k.G1.K)))
+	for i := range k.G1.K {
+		b = appendG1(b, &k.G1.K[i])
+	}
+	return b, nil
+}
+
+// MarshalProof encodes a proof as ark-groth16's Proof.
+func MarshalProof(proof groth16.Proof) ([]byte, error) {
+	p, ok := proof.(*groth16bn254.Proof)
+	if !ok {
+		return nil, fmt.Errorf("%w: proof is for %s, only %s is supported", ErrUnsupported, proof.CurveID(), ecc.BN254)
+	}
+	if len(p.Commitments) > 0 {
+		return nil, fmt.Errorf("%w: proof carries Pedersen commitments", ErrUnsupported)
+	}
+	b := appendG1(nil, &p.Ar)
+	b = appendG2(b, &p.Bs)
+	return appendG1(b, &p.Krs), nil
+}
+
+// MarshalPublicInputs encodes a public witness as a Vec<Fr>.
+func MarshalPublicInputs(public witness.Witness) ([]byte, error) {
+	values, ok := public.Vector().(fr.Vector)
+	if !ok {
+		return nil, fmt.Errorf("%w: public inputs are not over the %s scalar field", ErrUnsupported, ecc.BN254)
+	}
+	b := binary.LittleEndian.AppendUint64(nil, uint64(len(values)))
+	for i := range values {
+		b = appendLE(b, values[i].Bytes())
+	}
+	return b, nil
+}
+
+// UnmarshalVerifyingKey decodes a verifying key written by
+// MarshalVerifyingKey, or by arkworks. Only the parts a verifier uses are
+// set, so the key verifies the same proofs but does not serialize to the
+// original vk.bin.
+func UnmarshalVerifyingKey(b []byte) (groth16.VerifyingKey, error) {
+	d := decoder{b: b}
+	k := new(groth16bn254.VerifyingKey)
+	d.g1(&k.G1.Alpha)
+	d.g2(&k.G2.Beta)
+	d.g2(&k.G2.Gamma)
+	d.g2(&k.G2.Delta)
+	n := d.length(g1Size)
+	if n > 0 {
+		k.G1.K = make([]bn254.G1Affine, n)
+	}
+	for i := range k.G1.K {
+		d.g1(&k.G1.K[i])
+	}
+	if err := d.finish(); err != nil {
+		return nil, fmt.Errorf("verifying key: %w", err)
+	}
+	if len(k.G1.K) == 0 {
+		return nil, fmt.Errorf("verifying key: %w: no IC points", ErrEncoding)
+	}
+	if err := k.Precompute(); err != nil {
+		return nil, fmt.Errorf("verifying key: %w", err)
+	}
+	return k, nil
+}
+
+// UnmarshalProof decodes a proof written by MarshalProof, or by arkworks.
+func UnmarshalProof(b []byte) (groth16.Proof, error) {
+	d := decoder{b: b}
+	p := new(groth16bn254.Proof)
+	d.g1(&p.Ar)
+	d.g2(&p.Bs)
+	d.g1(&p.Krs)
+	if err := d.finish(); err != nil {
+		return nil, fmt.Errorf("proof: %w", err)
+	}
+	return p, nil
+}
+
+// UnmarshalPublicInputs decodes public inputs written by
+// MarshalPublicInputs, or by arkworks, into a public witness.
+func UnmarshalPublicInputs(b []byte) (witness.Witness, error) {
+	d := decoder{b: b}
+	n := d.length(elementSize)
+	values := make([]fr.Element, n)
+	for i := range values {
+		d.fr(&values[i])
+	}
+	if err := d.finish(); err != nil {
+		return nil, fmt.Errorf("public inputs: %w", err)
+	}
+	public, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan any, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	if err := public.Fill(len(values), 0, ch); err != nil {
+		return nil, fmt.Errorf("public inputs: %w", err)
+	}
+	return public, nil
+}
+
+func appendG1(b []byte, p *bn254.G1Affine) []byte {
+	b = appendLE(b, p.X.Bytes())
+	return withFlags(appendLE(b, p.Y.Bytes()), p.Y.LexicographicallyLargest(), p.IsInfinity())
+}
+
+func appendG2(b []byte, p *bn254.G2Affine) []byte {
+	b = appendLE(b, p.X.A0.Bytes())
+	b = appendLE(b, p.X.A1.Bytes())
+	b = appendLE(b, p.Y.A0.Bytes())
+	b = appendLE(b, p.Y.A1.Bytes())
+	return withFlags(b, p.Y.LexicographicallyLargest(), p.IsInfinity())
+}
+
+// withFlags sets the point flags in the last byte of b.
+func withFlags(b []byte, negative, infinity bool) []byte {
+	if infinity {
+		b[len(b)-1] |= flagInfinity
+	} else if negative {
+		b[len(b)-1] |= flagNegative
+	}
+	return b
+}
+
+// appendLE appends a big-endian field element in little-endian order.
+func appendLE(b []byte, be [elementSize]byte) []byte {
+	for i := elementSize - 1; i >= 0; i-- {
+		b = append(b, be[i])
+	}
+	return b
+}
+
+// decoder reads the layout, keeping the first error.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: "+format, append([]any{ErrEncoding}, args...)...)
+	}
+}
+
+// next returns the next n bytes, or nil past the end.
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.b) < n {
+		d.fail("truncated")
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+// element returns the next element, big-endian for gnark-crypto, with the
+// flags of its last byte when it carries them.
+func (d *decoder) element(flagged bool) (be []byte, flags byte) {
+	le := d.next(elementSize)
+	if le == nil {
+		return nil, 0
+	}
+	be = make([]byte, elementSize)
+	for i := range le {
+		be[elementSize-1-i] = le[i]
+	}
+	if flagged {
+		flags = be[0] & flagMask
+		be[0] &^= flagMask
+	}
+	return be, flags
+}
+
+func (d *decoder) fp(e *fp.Element, flagged bool) byte {
+	be, flags := d.element(flagged)
+	if be != nil {
+		if err := e.SetBytesCanonical(be); err != nil {
+			d.fail("%v", err)
+		}
+	}
+	return flags
+}
+
+func (d *decoder) fr(e *fr.Element) {
+	be, _ := d.element(false)
+	if be != nil {
+		if err := e.SetBytesCanonical(be); err != nil {
+			d.fail("%v", err)
+		}
+	}
+}
+
+func (d *decoder) g1(p *bn254.G1Affine) {
+	d.fp(&p.X, false)
+	flags := d.fp(&p.Y, true)
+	if d.err != nil {
+		return
+	}
+	d.point(flags, p.IsOnCurve() && p.IsInSubGroup())
+}
+
+func (d *decoder) g2(p *bn254.G2Affine) {
+	d.fp(&p.X.A0, false)
+	d.fp(&p.X.A1, false)
+	d.fp(&p.Y.A0, false)
+	flags := d.fp(&p.Y.A1, true)
+	if d.err != nil {
+		return
+	}
+	d.point(flags, p.IsOnCurve() && p.IsInSubGroup())
+}
+
+// point checks a decoded point. Like arkworks, it ignores the y flag of an
+// uncompressed point, which y itself settles.
+func (d *decoder) point(flags byte, valid bool) {
+	switch {
+	case flags&flagInfinity != 0:
+		d.fail("point at infinity")
+	case !valid:
+		d.fail("point not on the curve or not in the subgroup")
+	}
+}
+
+// length reads a Vec length, bounded by the bytes left for its elements.
+func (d *decoder) length(size int) int {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	n := binary.LittleEndian.Uint64(b)
+	if n > uint64(len(d.b)/size) {
+		d.fail("length %d exceeds the %d bytes left", n, len(d.b))
+		return 0
+	}
+	return int(n)
+}
+
+// finish reports the first error, or trailing bytes.
+func (d *decoder) finish() error {
+	if d.err == nil && len(d.b) > 0 {
+		d.fail("%d trailing bytes", len(d.b))
+	}
+	return d.err
+}
@@ -0,0 +1,252 @@
+package interop_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/interop"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/snarkjs"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// The golden range/16 proof, and the fixtures export-interop made of it.
+const (
+	goldenDir   = "../testdata/golden/range-16"
+	fixturesDir = "../testdata/interop/range-16"
+)
+
+type golden struct {
+	vk     groth16.VerifyingKey
+	proof  groth16.Proof
+	public witness.Witness
+}
+
+func loadGolden(t *testing.T) golden {
+	t.Helper()
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if err := keyfile.Read(filepath.Join(goldenDir, "vk.bin"), vk); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(goldenDir, "proof.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := envelope.Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, public, err := env.Open(env.Circuit, vk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return golden{vk, proof, public}
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(fixturesDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// TestRoundTrip encodes the golden proof, decodes it back and verifies it.
+func TestRoundTrip(t *testing.T) {
+	g := loadGolden(t)
+	arkVK, err := interop.MarshalVerifyingKey(g.vk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arkProof, err := interop.MarshalProof(g.proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arkProof) != interop.ProofSize {
+		t.Errorf("proof is %d bytes, want %d", len(arkProof), interop.ProofSize)
+	}
+	arkPublic, err := interop.MarshalPublicInputs(g.public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vk, err := interop.UnmarshalVerifyingKey(arkVK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := interop.UnmarshalProof(arkProof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := interop.UnmarshalPublicInputs(arkPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := zkp.Verify(proof, vk, public); err != nil {
+		t.Errorf("decoded proof: %v", err)
+	}
+	if err := zkp.Verify(proof, g.vk, g.public); err != nil {
+		t.Errorf("decoded proof against the original key: %v", err)
+	}
+	reencoded, err := interop.MarshalProof(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reencoded, arkProof) {
+		t.Error("the decoded proof encodes to other bytes")
+	}
+}
+
+// TestFixtures checks that the committed fixtures are what the encoders
+// give today, so the reference verifiers in interop/stubs test this code.
+func TestFixtures(t *testing.T) {
+	g := loadGolden(t)
+	for _, tc := range []struct {
+		file    string
+		marshal func() ([]byte, error)
+	}{
+		{"vk.ark", func() ([]byte, error) { return interop.MarshalVerifyingKey(g.vk) }},
+		{"proof.ark", func() ([]byte, error) { return interop.MarshalProof(g.proof) }},
+		{"public.ark", func() ([]byte, error) { return interop.MarshalPublicInputs(g.public) }},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			got, err := tc.marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, readFixture(t, tc.file)) {
+				t.Errorf("%s does not match the encoding of the golden proof", tc.file)
+			}
+		})
+	}
+
+	jsVK, err := snarkjs.ExportVerifyingKey(g.vk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsProof, err := snarkjs.ExportProof(g.proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsPublic, err := snarkjs.ExportPublicInputs(g.public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		file string
+		want any
+		got  any
+	}{
+		{"verification_key.json", jsVK, new(snarkjs.VerifyingKey)},
+		{"proof.json", jsProof, new(snarkjs.Proof)},
+		{"public.json", &jsPublic, new([]string)},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			if err := json.Unmarshal(readFixture(t, tc.file), tc.got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Errorf("%s does not match the snarkjs export of the golden proof", tc.file)
+			}
+		})
+	}
+}
+
+// TestInvalidPublicInputs checks that the proof does not verify against the
+// public_invalid fixture.
+func TestInvalidPublicInputs(t *testing.T) {
+	g := loadGolden(t)
+	invalid, err := interop.UnmarshalPublicInputs(readFixture(t, "public_invalid.ark"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zkp.Verify(g.proof, g.vk, invalid) == nil {
+		t.Error("the proof verifies against public_invalid.ark")
+	}
+}
+
+func TestUnmarshalRejects(t *testing.T) {
+	proof := readFixture(t, "proof.ark")
+	vk := readFixture(t, "vk.ark")
+	public := readFixture(t, "public.ark")
+	edit := func(b []byte, f func([]byte)) []byte {
+		b = bytes.Clone(b)
+		f(b)
+		return b
+	}
+	// Byte 63 is the top byte of A.y, where the flags are.
+	const flagByte = 63
+	for _, tc := range []struct {
+		name      string
+		data      []byte
+		unmarshal func([]byte) error
+	}{
+		{"truncated proof", proof[:len(proof)-1], unmarshalProof},
+		{"trailing byte", append(bytes.Clone(proof), 0), unmarshalProof},
+		{"point at infinity", edit(proof, func(b []byte) { b[flagByte] |= 1 << 6 }), unmarshalProof},
+		{"point off the curve", edit(proof, func(b []byte) { b[0] ^= 1 }), unmarshalProof},
+		{"coordinate above the modulus", edit(proof, func(b []byte) {
+			for i := 0; i < 32; i++ {
+				b[i] = 0xff
+			}
+			b[31] = 0x3f
+		}), unmarshalProof},
+		{"empty proof", nil, unmarshalProof},
+		{"IC length past the end", edit(vk, func(b []byte) {
+			binary.LittleEndian.PutUint64(b[64+3*128:], 1<<40)
+		}), unmarshalVK},
+		{"no IC points", edit(vk[:64+3*128+8], func(b []byte) {
+			binary.LittleEndian.PutUint64(b[64+3*128:], 0)
+		}), unmarshalVK},
+		{"public length past the end", edit(public, func(b []byte) {
+			binary.LittleEndian.PutUint64(b, 1<<62)
+		}), unmarshalPublic},
+		{"public input above the modulus", edit(public, func(b []byte) {
+			for i := 8; i < 40; i++ {
+				b[i] = 0xff
+			}
+		}), unmarshalPublic},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.unmarshal(tc.data); !errors.Is(err, interop.ErrEncoding) {
+				t.Errorf("unmarshal = %v, want ErrEncoding", err)
+			}
+		})
+	}
+}
+
+func unmarshalProof(b []byte) error {
+	_, err := interop.UnmarshalProof(b)
+	return err
+}
+
+func unmarshalVK(b []byte) error {
+	_, err := interop.UnmarshalVerifyingKey(b)
+	return err
+}
+
+func unmarshalPublic(b []byte) error {
+	_, err := interop.UnmarshalPublicInputs(b)
+	return err
+}
+
+func TestMarshalUnsupportedCurve(t *testing.T) {
+	if _, err := interop.MarshalVerifyingKey(groth16.NewVerifyingKey(ecc.BLS12_381)); !errors.Is(err, interop.ErrUnsupported) {
+		t.Errorf("MarshalVerifyingKey(BLS12-381) = %v, want ErrUnsupported", err)
+	}
+	if _, err := interop.MarshalProof(groth16.NewProof(ecc.BLS12_381)); !errors.Is(err, interop.ErrUnsupported) {
+		t.Errorf("MarshalProof(BLS12-381) = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,41 @@
+//! Verifies the arkworks fixtures written by `hello-zkp export-interop`:
+//!
+//!     cargo run --release -- ../../../testdata/interop/range-16
+//!
+//! It exits 0 if the proof verifies against public.ark and not against
+//! public_invalid.ark, and 1 otherwise.
+
+use std::path::{Path, PathBuf};
+use std::process::ExitCode;
+
+use ark_bn254::{Bn254, Fr};
+use ark_groth16::{Groth16, Proof, VerifyingKey};
+use ark_serialize::CanonicalDeserialize;
+use ark_snark::SNARK;
+
+fn read<T: CanonicalDeserialize>(dir: &Path, name: &str) -> T {
+    let bytes = std::fs::read(dir.join(name)).unwrap_or_else(|e| panic!("{name}: {e}"));
+    T::deserialize_uncompressed(&bytes[..]).unwrap_or_else(|e| panic!("{name}: {e}"))
+}
+
+fn main() -> ExitCode {
+    let dir = std::env::args()
+        .nth(1)
+        .map(PathBuf::from)
+        .unwrap_or_else(|| PathBuf::from("../../../testdata/interop/range-16"));
+
+    let vk: VerifyingKey<Bn254> = read(&dir, "vk.ark");
+    let proof: Proof<Bn254> = read(&dir, "proof.ark");
+    let public: Vec<Fr> = read(&dir, "public.ark");
+    let invalid: Vec<Fr> = read(&dir, "public_invalid.ark");
+
+    let ok = Groth16::<Bn254>::verify(&vk, &public, &proof).unwrap_or(false);
+    let rejected = !Groth16::<Bn254>::verify(&vk, &invalid, &proof).unwrap_or(false);
+    println!("public.ark: {}", if ok { "verified" } else { "FAILED" });
+    println!("public_invalid.ark: {}", if rejected { "rejected" } else { "ACCEPTED" });
+    if ok && rejected {
+        ExitCode::SUCCESS
+    } else {
+        ExitCode::FAILURE
+    }
+}
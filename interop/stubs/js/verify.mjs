@@ -0,0 +1,24 @@
+// Verifies the snarkjs fixtures written by `hello-zkp export-interop`:
+//
+//   npm install && node verify.mjs ../../../testdata/interop/range-16
+//
+// It exits 0 if the proof verifies against public.json and not against
+// public_invalid.json, and 1 otherwise.
+
+import { readFile } from "node:fs/promises";
+import { join } from "node:path";
+import { fileURLToPath } from "node:url";
+import * as snarkjs from "snarkjs";
+
+const dir = process.argv[2] ?? fileURLToPath(new URL("../../../testdata/interop/range-16", import.meta.url));
+const load = async (name) => JSON.parse(await readFile(join(dir, name), "utf8"));
+
+const vk = await load("verification_key.json");
+const proof = await load("proof.json");
+const ok = await snarkjs.groth16.verify(vk, await load("public.json"), proof);
+const rejected = !(await snarkjs.groth16.verify(vk, await load("public_invalid.json"), proof));
+
+console.log(`public.json: ${ok ? "verified" : "FAILED"}`);
+console.log(`public_invalid.json: ${rejected ? "rejected" : "ACCEPTED"}`);
+// snarkjs keeps worker threads alive; exit explicitly.
+process.exit(ok && rejected ? 0 : 1);
@@ -0,0 +1,297 @@
+// Package issuer implements the third party of the issuer/holder/verifier
+// model: an authority that checks a subject's age once, signs a credential
+// for it and publishes what verifiers need to trust its credentials.
+//
+// A credential is a commitment.Opening, the age, a salt and their Poseidon2
+// commitment, issued to a numeric subject identifier, together with the
+// issuer's EdDSA signature over the commitment and the subject. The holder
+// keeps the whole credential and proves statements about the age with the
+// committed-range circuit, reading the credential as its opening. Only the
+// Claim, without age or salt, is shown to verifiers, who check its
+// signature against the issuer's Publication.
+//
+// Revocation is a blocklist of subject identifiers (see
+// commitment.Blocklist) that the issuer publishes with its root. The claim
+// shows its subject, so a verifier checks that subject against the list
+// directly; the non-membership circuit does not tie its identifier to a
+// credential and proves nothing about a claim on its own.
+//
+// Keys are EdDSA on the twisted Edwards curve embedded in BN254, and
+// messages are hashed with MiMC, so signatures could also be checked inside
+// a BN254 circuit.
+package issuer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+
+	"github.com/ananthanir/hello-zkp/commitment"
+)
+
+// Curve is the curve credentials are committed and signed on.
+const Curve = ecc.BN254
+
+var (
+	// ErrSignature is returned when a claim's signature does not check out
+	// against the issuer's public key.
+	ErrSignature = errors.New("invalid issuer signature")
+
+	// ErrIssuer is returned when a claim names another issuer than the one
+	// it is checked against.
+	ErrIssuer = errors.New("claim is from another issuer")
+
+	// ErrRevoked is returned when a claim's subject is in the issuer's
+	// revocation list.
+	ErrRevoked = errors.New("subject is revoked")
+)
+
+// Key is an issuer's private signing key.
+type Key struct {
+	sk *eddsa.PrivateKey
+}
+
+// GenerateKey returns a fresh random issuer key.
+func GenerateKey() (*Key, error) {
+	sk, err := eddsa.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate issuer key: %w", err)
+	}
+	return &Key{sk: sk}, nil
+}
+
+// Public returns the hex public key the issuer publishes.
+func (k *Key) Public() string {
+	return hex.EncodeToString(k.sk.PublicKey.Bytes())
+}
+
+// Save writes the key to path in hex, readable by the owner only.
+func (k *Key) Save(path string) error {
+	return os.WriteFile(path, []byte(hex.EncodeToString(k.sk.Bytes())+"\n"), 0o600)
+}
+
+// LoadKey reads a key written by Save.
+func LoadKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	var sk eddsa.PrivateKey
+	if _, err := sk.SetBytes(raw); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &Key{sk: &sk}, nil
+}
+
+// Claim is the public part of a credential: what a holder shows a verifier
+// next to its proofs.
+type Claim struct {
+	Subject    int      `json:"subject"`
+	Commitment *big.Int `json:"commitment"`
+	Issuer     string   `json:"issuer"`    // hex public key
+	Signature  string   `json:"signature"` // hex EdDSA signature
+}
+
+// Credential is what the holder keeps: the opening of the committed age and
+// the signed claim about it. Its JSON form is a superset of the opening's,
+// so it can be passed to prove -opening as it is.
+type Credential struct {
+	commitment.Opening
+	Subject   int    `json:"subject"`
+	Issuer    string `json:"issuer"`
+	Signature string `json:"signature"`
+}
+
+// Issue commits to age with a fresh salt and signs the commitment for
+// subject.
+func (k *Key) Issue(age, subject int) (*Credential, error) {
+	if subject < 0 {
+		return nil, fmt.Errorf("subject %d is negative", subject)
+	}
+	opening, err := commitment.New(Curve, age)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := k.sk.Sign(message(opening.Commitment, subject), mimc.NewMiMC())
+	if err != nil {
+		return nil, fmt.Errorf("sign credential: %w", err)
+	}
+	return &Credential{
+		Opening:   *opening,
+		Subject:   subject,
+		Issuer:    k.Public(),
+		Signature: hex.EncodeToString(sig),
+	}, nil
+}
+
+// Claim returns the public part of the credential.
+func (c *Credential) Claim() *Claim {
+	return &Claim{Subject: c.Subject, Commitment: c.Commitment, Issuer: c.Issuer, Signature: c.Signature}
+}
+
+// Verify checks the opening and the issuer's signature.
+func (c *Credential) Verify() error {
+	if err := c.Opening.Verify(); err != nil {
+		return err
+	}
+	return c.Claim().Verify(c.Issuer)
+}
+
+// Save writes the credential to path, readable by the owner only, as for
+// openings.
+func (c *Credential) Save(path string) error {
+	return saveJSON(path, c, 0o600)
+}
+
+// LoadCredential reads a credential written by Save and checks it.
+func LoadCredential(path string) (*Credential, error) {
+	var c Credential
+	if err := loadJSON(path, &c); err != nil {
+		return nil, err
+	}
+	if err := c.Verify(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Verify checks that the claim was signed by the issuer with the given hex
+// public key.
+func (c *Claim) Verify(issuer string) error {
+	if c.Issuer != issuer {
+		return fmt.Errorf("%w: %s", ErrIssuer, c.Issuer)
+	}
+	raw, err := hex.DecodeString(issuer)
+	if err != nil {
+		return fmt.Errorf("%w: malformed public key: %v", ErrSignature, err)
+	}
+	var pk eddsa.PublicKey
+	if _, err := pk.SetBytes(raw); err != nil {
+		return fmt.Errorf("%w: malformed public key: %v", ErrSignature, err)
+	}
+	sig, err := hex.DecodeString(c.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSignature, err)
+	}
+	if c.Commitment == nil || c.Commitment.Sign() < 0 || c.Commitment.Cmp(fr.Modulus()) >= 0 || c.Subject < 0 {
+		return fmt.Errorf("%w: claim out of range", ErrSignature)
+	}
+	ok, err := pk.Verify(sig, message(c.Commitment, c.Subject), mimc.NewMiMC())
+	if err != nil || !ok {
+		return ErrSignature
+	}
+	return nil
+}
+
+// LoadClaim reads a claim, or the claim of a credential file.
+func LoadClaim(path string) (*Claim, error) {
+	var c Claim
+	if err := loadJSON(path, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Save writes the claim to path. It is public.
+func (c *Claim) Save(path string) error {
+	return saveJSON(path, c, 0o644)
+}
+
+// Publication is what an issuer publishes for verifiers: its public key, its
+// revocation list and the list's root.
+type Publication struct {
+	Curve          string                `json:"curve"`
+	PublicKey      string                `json:"public_key"`
+	RevocationRoot string                `json:"revocation_root"`
+	Revoked        int                   `json:"revoked"`
+	Revocations    *commitment.Blocklist `json:"revocations"`
+}
+
+// Publish returns the publication for the key and revocation list.
+func (k *Key) Publish(revoked *commitment.Blocklist) *Publication {
+	return &Publication{
+		Curve:          Curve.String(),
+		PublicKey:      k.Public(),
+		RevocationRoot: commitment.Format(revoked.Root()),
+		Revoked:        len(revoked.IDs),
+		Revocations:    revoked,
+	}
+}
+
+// CheckRevocation returns ErrRevoked if subject is in the published
+// revocation list.
+func (p *Publication) CheckRevocation(subject int) error {
+	if p.Revocations.Contains(subject) {
+		return fmt.Errorf("%w: %d", ErrRevoked, subject)
+	}
+	return nil
+}
+
+// Save writes the publication to path.
+func (p *Publication) Save(path string) error {
+	return saveJSON(path, p, 0o644)
+}
+
+// LoadPublication reads a publication written by Save.
+func LoadPublication(path string) (*Publication, error) {
+	var p Publication
+	if err := loadJSON(path, &p); err != nil {
+		return nil, err
+	}
+	if p.Curve != Curve.String() {
+		return nil, fmt.Errorf("%s: issuer on unsupported curve %s", path, p.Curve)
+	}
+	if p.Revocations == nil {
+		return nil, fmt.Errorf("%s: publication has no revocation list", path)
+	}
+	if err := p.Revocations.Verify(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if root := commitment.Format(p.Revocations.Root()); root != p.RevocationRoot || len(p.Revocations.IDs) != p.Revoked {
+		return nil, fmt.Errorf("%s: revocation list does not match its root %s", path, p.RevocationRoot)
+	}
+	return &p, nil
+}
+
+// message is the signed message: the commitment and the subject, each as
+// one BN254 scalar, which is what MiMC absorbs.
+func message(c *big.Int, subject int) []byte {
+	var e [2]fr.Element
+	e[0].SetBigInt(c)
+	e[1].SetInt64(int64(subject))
+	b0, b1 := e[0].Bytes(), e[1].Bytes()
+	return append(b0[:], b1[:]...)
+}
+
+func saveJSON(path string, v any, perm os.FileMode) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), perm)
+}
+
+func loadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
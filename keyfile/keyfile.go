@@ -0,0 +1,154 @@
+// Package keyfile reads and writes Groth16 keys and other large artifacts.
+//
+// Proving keys grow with the circuit, to hundreds of megabytes for the bigger
+// ones, so files are streamed through large buffers rather than held in
+// memory, written through a temporary file so a reader never sees half a
+// key, and accompanied by a SHA-256 checksum that Read checks as it goes.
+//
+// Artifacts can be written compressed, the gnark default with points in
+// compressed form, or raw, with uncompressed points: about twice the size,
+// but decoding skips the square root each compressed point costs, which
+// dominates reading a large proving key. Readers detect the encoding.
+package keyfile
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// ChecksumSuffix is appended to a file name to name its checksum file, which
+// holds one line in sha256sum format.
+const ChecksumSuffix = ".sha256"
+
+// bufferSize is the read and write buffer size.
+const bufferSize = 1 << 20
+
+// ErrChecksum is returned when a file does not match its checksum file.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// Encoding is how points of an artifact are written.
+type Encoding int
+
+const (
+	Compressed Encoding = iota
+	Raw
+)
+
+// EncodingOf returns Compressed or Raw.
+func EncodingOf(compress bool) Encoding {
+	if compress {
+		return Compressed
+	}
+	return Raw
+}
+
+func (e Encoding) String() string {
+	if e == Raw {
+		return "raw"
+	}
+	return "compressed"
+}
+
+// Artifact is anything gnark can write in both encodings: keys, proofs and
+// SRS.
+type Artifact interface {
+	io.WriterTo
+	WriteRawTo(w io.Writer) (int64, error)
+}
+
+// Write writes v to path in the given encoding, then its checksum file. It
+// returns the hex SHA-256 of the file. Failures wrap zkp.ErrIO.
+func Write(path string, v Artifact, enc Encoding) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return "", zkp.Wrap(zkp.ErrIO, err)
+	}
+	defer os.Remove(f.Name())
+
+	h := sha256.New()
+	w := bufio.NewWriterSize(io.MultiWriter(f, h), bufferSize)
+	if enc == Raw {
+		_, err = v.WriteRawTo(w)
+	} else {
+		_, err = v.WriteTo(w)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		f.Close()
+		return "", zkp.Wrap(zkp.ErrIO, fmt.Errorf("write %s: %w", path, err))
+	}
+	if err := f.Close(); err != nil {
+		return "", zkp.Wrap(zkp.ErrIO, err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", zkp.Wrap(zkp.ErrIO, err)
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(path))
+	if err := os.WriteFile(path+ChecksumSuffix, []byte(line), 0o644); err != nil {
+		return "", zkp.Wrap(zkp.ErrIO, err)
+	}
+	return sum, nil
+}
+
+// Read fills v from path, in either encoding. If a checksum file sits next
+// to it, the whole file is hashed while it is decoded and must match;
+// files without one, such as keys written before checksums existed, are
+// read unchecked. Failures wrap zkp.ErrIO.
+func Read(path string, v io.ReaderFrom) error {
+	want, err := readChecksum(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	r := io.TeeReader(bufio.NewReaderSize(f, bufferSize), h)
+	if _, err := v.ReadFrom(r); err != nil {
+		return zkp.Wrap(zkp.ErrIO, fmt.Errorf("read %s: %w", path, err))
+	}
+	if want == "" {
+		return nil
+	}
+	// The decoder may stop before trailing bytes the checksum covers.
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return zkp.Wrap(zkp.ErrIO, fmt.Errorf("read %s: %w", path, err))
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return zkp.Wrap(zkp.ErrIO, fmt.Errorf("%w: %s has SHA-256 %s, %s%s says %s", ErrChecksum, path, got, filepath.Base(path), ChecksumSuffix, want))
+	}
+	return nil
+}
+
+// readChecksum returns the checksum recorded for path, or "" if there is
+// no checksum file.
+func readChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path + ChecksumSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", zkp.Wrap(zkp.ErrIO, err)
+	}
+	sum, _, _ := strings.Cut(strings.TrimSpace(string(data)), " ")
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != 2*sha256.Size {
+		return "", zkp.Wrap(zkp.ErrIO, fmt.Errorf("%w: %s%s is not a SHA-256 checksum", ErrChecksum, path, ChecksumSuffix))
+	}
+	return strings.ToLower(sum), nil
+}
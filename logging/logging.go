@@ -0,0 +1,123 @@
+// Package logging configures the log output of the hello-zkp commands:
+// gnark's own logs and the structured events emitted when a proving step
+// finishes (compile_done, setup_done, prove_done, verify_done), each with
+// its duration. Logs go to stderr, which -json leaves free of results, and
+// are disabled unless a level is set.
+//
+//	hello-zkp prove -log-level info -log-format json ...
+//	{"level":"info","event":"prove_done","duration":6.9,"curve":"bn254","time":"…"}
+package logging
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/logger"
+	"github.com/rs/zerolog"
+)
+
+// Log formats.
+const (
+	Console = "console" // human-readable lines, the default
+	JSON    = "json"    // one JSON object per line
+)
+
+// ErrInvalid is returned for an unknown level or format.
+var ErrInvalid = errors.New("invalid logging option")
+
+var (
+	mu  sync.RWMutex
+	log = zerolog.Nop()
+)
+
+// ParseLevel parses a zerolog level name; the empty string disables logs.
+func ParseLevel(s string) (zerolog.Level, error) {
+	if s == "" {
+		return zerolog.Disabled, nil
+	}
+	level, err := zerolog.ParseLevel(strings.ToLower(s))
+	if err != nil {
+		return zerolog.Disabled, fmt.Errorf("%w: unknown log level %q", ErrInvalid, s)
+	}
+	return level, nil
+}
+
+// ParseFormat checks a format name; the empty string means Console.
+func ParseFormat(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "", Console:
+		return Console, nil
+	case JSON:
+		return JSON, nil
+	}
+	return "", fmt.Errorf("%w: unknown log format %q (console or json)", ErrInvalid, s)
+}
+
+// Setup sets the level and format of every log line, gnark's included, and
+// sends them to stderr.
+func Setup(level, format string) error {
+	return SetupWriter(os.Stderr, level, format)
+}
+
+// SetupWriter is Setup writing to w.
+func SetupWriter(w io.Writer, level, format string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	f, err := ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	if f == Console {
+		w = zerolog.ConsoleWriter{Out: w, TimeFormat: "15:04:05"}
+	}
+	l := zerolog.New(w).Level(lvl).With().Timestamp().Logger()
+
+	mu.Lock()
+	defer mu.Unlock()
+	log = l
+	// gnark checks the global level too
+	zerolog.SetGlobalLevel(lvl)
+	logger.Set(l.With().Str("component", "gnark").Logger())
+	return nil
+}
+
+// Flags are the -log-level and -log-format flags of a command.
+type Flags struct {
+	Level  *string
+	Format *string
+}
+
+// AddFlags registers -log-level and -log-format on fs.
+func AddFlags(fs *flag.FlagSet) *Flags {
+	return &Flags{
+		Level:  fs.String("log-level", "", "log level on stderr: debug, info, warn, error (empty: no logs)"),
+		Format: fs.String("log-format", Console, "log format: console or json"),
+	}
+}
+
+// Setup applies the parsed flags.
+func (f *Flags) Setup() error {
+	return Setup(*f.Level, *f.Format)
+}
+
+// Logger returns the configured logger.
+func Logger() zerolog.Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+	return log
+}
+
+// Done logs at info level that the step named by event finished, with the
+// time since start in milliseconds and fields given as key, value pairs.
+func Done(event string, start time.Time, fields ...any) {
+	l := Logger()
+	l.Info().Str("event", event).Dur("duration", time.Since(start)).Fields(fields).Send()
+}
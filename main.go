@@ -2,137 +2,109 @@ package main
 
 import (
 	"fmt"
-	"log"
-
-	"github.com/consensys/gnark-crypto/ecc"
-	"github.com/consensys/gnark/backend/groth16"
-	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/frontend/cs/r1cs"
-
-	"github.com/rs/zerolog"
+	"os"
+	"strings"
 )
 
-// Circuit: Prove that Min ≤ Age ≤ Max
-type Circuit struct {
-	// Private input: the user's age
-	Age frontend.Variable `gnark:"age"`
-
-	// Public inputs: range bounds
-	Min frontend.Variable `gnark:",public"`
-	Max frontend.Variable `gnark:",public"`
-}
-
-// rangeNonNeg constrains v >= 0 by forcing v to be representable
-// as a small non-negative integer using 'bits' bits.
-func rangeNonNeg(api frontend.API, v frontend.Variable, bits int) {
-	bin := api.ToBinary(v, bits) // constrain 0 ≤ v < 2^bits
-	for _, b := range bin {
-		api.AssertIsBoolean(b)
-	}
-	// Reconstruct v from bits and assert equality
-	reconstructed := frontend.Variable(0)
-	for i, b := range bin {
-		reconstructed = api.Add(reconstructed, api.Mul(b, 1<<i))
-	}
-	api.AssertIsEqual(v, reconstructed)
-}
-
-// Define: enforce Min ≤ Age ≤ Max
-func (c *Circuit) Define(api frontend.API) error {
-	const bits = 16 // plenty for realistic ages
-
-	lower := api.Sub(c.Age, c.Min) // Age - Min ≥ 0  ⇒ Age ≥ Min
-	rangeNonNeg(api, lower, bits)
-
-	upper := api.Sub(c.Max, c.Age) // Max - Age ≥ 0  ⇒ Age ≤ Max
-	rangeNonNeg(api, upper, bits)
-
-	return nil
+const usage = `usage: hello-zkp [command] [flags]
+
+commands:
+  demo            prompt for inputs and run compile, setup, prove and verify (default)
+  setup           compile the circuit and write the proving and verifying keys
+  ceremony        run a multi-party setup ceremony step by step (see 'ceremony' alone)
+  prove           prove Min ≤ Age ≤ Max and write a proof envelope
+  verify          check a proof envelope against a verifying key
+  vk              fingerprint, publish or fetch a verifying key (see 'vk' alone)
+  audit           list the proofs in an audit log, or show one (see 'audit' alone)
+  commit          commit to an age and write the private opening (registrar)
+  policy          commit to private Min/Max bounds and write the opening (verifier)
+  blocklist       create or update a blocklist and print its root (issuer)
+  challenge       print a fresh random challenge for a prover to bind a proof to
+  prove-batch     set up once and prove every row of a JSONL/CSV file concurrently
+  verify-batch    verify every proof envelope in a directory in parallel
+  recursive       prove an age proof on BLS12-377, then prove holding it on BW6-761
+  aggregate       fold the proofs of every row of a JSONL/CSV file into one proof
+  sudoku          prove knowledge of a solution to a public sudoku puzzle
+  prove-all-curves prove one statement on BN254, BLS12-381 and BLS12-377 at once and compare
+  bench           report constraint count, timings and artifact sizes per curve
+  export-snarkjs  write the verifying key and a proof in snarkjs' JSON formats
+  export-interop  write arkworks and snarkjs fixtures of a proof, and check them
+  export-ccs      write the compiled constraint system as gnark binary, .r1cs and JSON
+  export-solidity write the Solidity verifier contract and a proof's calldata
+  export-vp       wrap a proof envelope in a W3C Verifiable Presentation
+  export-qr       render a proof envelope as a QR code for offline presentation
+
+Run 'hello-zkp <command> -h' for the flags of a command. Every command
+also takes -log-level (debug, info, …) and -log-format (console, json) for
+logs on stderr, and -otlp-endpoint to send OpenTelemetry spans to.
+
+Flag defaults (curve, bits, keys, cache, log level) can be set in
+hello-zkp.json, or the file named by $HELLO_ZKP_CONFIG.
+
+exit status:
+  0  success
+  1  the proof did not verify
+  2  usage error
+  3  invalid input or witness
+  4  i/o failure
+  5  compile or setup failure
+`
+
+// commands maps each command name to its implementation.
+var commands = map[string]func(args []string) error{
+	"demo":             runDemo,
+	"setup":            runSetup,
+	"ceremony":         runCeremony,
+	"prove":            runProve,
+	"verify":           runVerify,
+	"vk":               runVK,
+	"audit":            runAudit,
+	"commit":           runCommit,
+	"blocklist":        runBlocklist,
+	"policy":           runPolicy,
+	"challenge":        runChallenge,
+	"prove-batch":      runProveBatch,
+	"verify-batch":     runVerifyBatch,
+	"bench":            runBench,
+	"prove-all-curves": runProveAllCurves,
+	"export-snarkjs":   runExportSnarkjs,
+	"export-interop":   runExportInterop,
+	"export-ccs":       runExportCCS,
+	"export-solidity":  runExportSolidity,
+	"export-vp":        runExportVP,
+	"export-qr":        runExportQR,
+	"recursive":        runRecursive,
+	"aggregate":        runAggregate,
+	"sudoku":           runSudoku,
 }
 
 func main() {
-	// Disable gnark debug logs
-	zerolog.SetGlobalLevel(zerolog.Disabled)
-
-	// -----------------------------
-	// Ask user for inputs
-	// -----------------------------
-	var age, min, max int
-	fmt.Print("Enter Age (private): ")
-	_, err := fmt.Scan(&age)
-	if err != nil {
-		log.Fatalf("failed to read Age: %v", err)
+	// Logs stay disabled unless the config file or -log-level sets a level
+	if err := loadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "hello-zkp: %v\n", err)
+		os.Exit(exitUsage)
 	}
 
-	fmt.Print("Enter Min bound (public): ")
-	_, err = fmt.Scan(&min)
-	if err != nil {
-		log.Fatalf("failed to read Min: %v", err)
-	}
-
-	fmt.Print("Enter Max bound (public): ")
-	_, err = fmt.Scan(&max)
-	if err != nil {
-		log.Fatalf("failed to read Max: %v", err)
+	// With no command (or only flags) keep the original interactive demo
+	cmd, args := "demo", os.Args[1:]
+	if len(os.Args) >= 2 && !strings.HasPrefix(os.Args[1], "-") {
+		cmd, args = os.Args[1], os.Args[2:]
 	}
 
-	// -----------------------------
-	// 1) Compile circuit
-	// -----------------------------
-	var circuit Circuit
-	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
-	if err != nil {
-		log.Fatalf("compile error: %v", err)
-	}
-
-	// -----------------------------
-	// 2) Trusted setup (Groth16)
-	// -----------------------------
-	pk, vk, err := groth16.Setup(ccs)
-	if err != nil {
-		log.Fatalf("setup error: %v", err)
-	}
-
-	// -----------------------------
-	// 3) Assign inputs (witness)
-	// -----------------------------
-	assignment := Circuit{
-		Age: age, // private
-		Min: min, // public
-		Max: max, // public
-	}
-
-	fmt.Println("\n=== Inputs ===")
-	fmt.Printf("Private:  Age = %v\n", age)
-	fmt.Printf("Public:   Min = %v\n", min)
-	fmt.Printf("Public:   Max = %v\n", max)
-	fmt.Println("Proving statement: Min ≤ Age ≤ Max ?")
-
-	witness, err := frontend.NewWitness(&assignment, ecc.BN254.ScalarField())
-	if err != nil {
-		log.Fatalf("witness error: %v", err)
+	if cmd == "help" {
+		fmt.Print(usage)
+		return
 	}
-	publicWitness, err := witness.Public()
-	if err != nil {
-		log.Fatalf("public witness error: %v", err)
+	run, ok := commands[cmd]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(exitUsage)
 	}
-
-	// -----------------------------
-	// 4) Prove
-	// -----------------------------
-	proof, err := groth16.Prove(ccs, pk, witness)
+	err := run(args)
+	finishTrace(err)
+	report.flush(cmd, err)
 	if err != nil {
-		fmt.Println("Prove: ❌ FAILED (witness does not satisfy constraints)")
-		log.Fatalf("Reason: %v\n", err)
+		fmt.Fprintf(os.Stderr, "hello-zkp %s: %v\n", cmd, err)
+		os.Exit(exitCode(err))
 	}
-
-	// -----------------------------
-	// 5) Verify
-	// -----------------------------
-	if err := groth16.Verify(proof, vk, publicWitness); err != nil {
-		fmt.Println("Verification: ❌ FAILED")
-		fmt.Printf("Reason: %v\n", err)
-		return
-	}
-	fmt.Println("Verification: ✅ SUCCESS (Min ≤ Age ≤ Max proven zero-knowledge)")
-}
\ No newline at end of file
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// output is where commands report. By default progress and results are text
+// on stdout. With -json the text moves to stderr, and the results set with
+// set are printed on stdout as one JSON object when the command returns, so
+// scripts can drive the tool without scraping text.
+type output struct {
+	json   bool
+	fields map[string]any
+}
+
+// report is the output of the running command.
+var report = &output{fields: map[string]any{}}
+
+// addJSONFlag registers -json on a command's flag set.
+func addJSONFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&report.json, "json", false, "print the result as JSON on stdout (progress goes to stderr)")
+}
+
+// text returns where human-readable output goes.
+func (o *output) text() io.Writer {
+	if o.json {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+func (o *output) printf(format string, args ...any) {
+	fmt.Fprintf(o.text(), format, args...)
+}
+
+func (o *output) println(args ...any) {
+	fmt.Fprintln(o.text(), args...)
+}
+
+// set records a result field for -json.
+func (o *output) set(key string, value any) {
+	o.fields[key] = value
+}
+
+// since records the time elapsed since start, in nanoseconds, as key.
+func (o *output) since(key string, start time.Time) {
+	o.set(key, time.Since(start).Nanoseconds())
+}
+
+// flush prints the JSON result of a command that returned err. It does
+// nothing without -json.
+func (o *output) flush(cmd string, err error) {
+	if !o.json {
+		return
+	}
+	o.set("command", cmd)
+	o.set("ok", err == nil)
+	if err != nil {
+		o.set("error", err.Error())
+		o.set("exit_code", exitCode(err))
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(o.fields); err != nil {
+		fmt.Fprintf(os.Stderr, "hello-zkp %s: write JSON: %v\n", cmd, err)
+	}
+}
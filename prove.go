@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	gnarkbackend "github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/audit"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runProve proves a statement with keys from a previous setup and writes
+// the proof envelope. With -audit-log the attempt is recorded, whether or
+// not it succeeds.
+func runProve(args []string) (err error) {
+	fs := flag.NewFlagSet("prove", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	keys := fs.String("keys", "keys", "directory holding pk.bin and vk.bin")
+	name := fs.String("circuit", "range", "circuit to prove")
+	statement := addStatementFlags(fs, true)
+	out := fs.String("out", "proof.json", "file to write the proof envelope to")
+	seed := fs.String("seed", "", "derive the proof randomness from this seed (INSECURE, demo only)")
+	accelerator := fs.String("accelerator", string(prover.CPU), "prove on cpu or gpu (gpu: ICICLE, needs -tags icicle and CUDA; falls back to cpu)")
+	lowMemory := fs.Bool("low-memory", false, "lower the peak memory at the cost of proving time (see README)")
+	auditLog := addAuditFlag(fs)
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	log, err := openAudit(*auditLog)
+	if err != nil {
+		return err
+	}
+	var (
+		definition    circuit.Definition
+		env           *envelope.Envelope
+		publicWitness witness.Witness
+	)
+	defer func() {
+		err = recordAudit(log, audit.Proved, definition, env, publicWitness, false, err)
+	}()
+
+	acc, err := prover.ParseAccelerator(*accelerator)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	if acc == prover.GPU && *seed != "" {
+		return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("-seed proves on the cpu only"))
+	}
+
+	var opts []gnarkbackend.ProverOption
+	if *lowMemory {
+		opts = prover.LowMemory()
+	}
+
+	definition, ccs, err := compileCircuit(*name, *bits)
+	if err != nil {
+		return err
+	}
+	report.set("circuit", definition.ID())
+
+	assignment, err := statement.assignment(definition)
+	if err != nil {
+		return err
+	}
+	full, publicWitness, err := prover.NewWitness(curve, assignment)
+	if err != nil {
+		return err
+	}
+
+	// Compiling leaves garbage behind that would otherwise still take room
+	// while the proving key is read.
+	if *lowMemory {
+		prover.Release()
+	}
+	pk, err := readProvingKey(*keys)
+	if err != nil {
+		return err
+	}
+	vk, err := readVerifyingKey(*keys)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	var proof groth16.Proof
+	if *seed != "" {
+		warnSeeded("prove", "anyone who knows the seed can recover the private inputs")
+		proof, err = prover.SeededProve(ccs, pk, full, *seed, opts...)
+	} else {
+		var used prover.Accelerator
+		var fallback error
+		proof, used, fallback, err = prover.ProveOn(acc, ccs, pk, full, opts...)
+		if fallback != nil {
+			fmt.Fprintf(os.Stderr, "prove: gpu unavailable, proving on the cpu: %v\n", fallback)
+		}
+		report.set("accelerator", used)
+	}
+	if err != nil {
+		report.println("Prove: ❌ FAILED (witness does not satisfy constraints)")
+		return err
+	}
+	report.since("prove_ns", start)
+	if rss, ok := peakRSS(); ok {
+		report.set("peak_rss_bytes", rss)
+	}
+
+	env, err = envelope.New(definition.ID(), vk, proof, publicWitness)
+	if err != nil {
+		return err
+	}
+	if err := writeEnvelope(*out, env); err != nil {
+		return err
+	}
+
+	report.set("statement", statement.describe(definition))
+	report.set("envelope", env)
+	report.set("out", *out)
+	report.printf("Prove: ✅ wrote proof of %s to %s\n", statement.describe(definition), *out)
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runProveAllCurves compiles, sets up, proves and verifies one statement on
+// several curves at once and compares the runs. A curve that fails is
+// reported in the table; the command then fails with the first error.
+func runProveAllCurves(args []string) error {
+	fs := flag.NewFlagSet("prove-all-curves", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	name := fs.String("circuit", "range", "circuit to prove")
+	statement := addStatementFlags(fs, true)
+	curves := fs.String("curves", "bn254,bls12_381,bls12_377", "comma-separated curves to prove on")
+	out := fs.String("out", "", "directory to write <curve>/vk.bin and <curve>/proof.json to (empty: none)")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	definition, err := circuit.New(*name, *bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	assignment, err := statement.assignment(definition)
+	if err != nil {
+		return err
+	}
+	var ids []ecc.ID
+	for _, name := range strings.Split(*curves, ",") {
+		id, err := ecc.IDFromString(strings.TrimSpace(name))
+		if err != nil {
+			return zkp.Wrap(zkp.ErrCompile, fmt.Errorf("%q: %w", name, err))
+		}
+		ids = append(ids, id)
+	}
+
+	report.printf("Proving %s (%s) on %d curves concurrently...\n", statement.describe(definition), definition.ID(), len(ids))
+	start := time.Now()
+	results := make([]benchResult, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i].Curve = id.String()
+			// Compiling writes to the circuit it is given, so each run gets
+			// its own definition; the assignment is only read.
+			definition, err := circuit.New(*name, *bits)
+			if err != nil {
+				errs[i] = zkp.Wrap(zkp.ErrCompile, err)
+				return
+			}
+			results[i], errs[i] = benchGroth16(id, prover.CPU, definition, assignment)
+			results[i].Curve = id.String()
+		}()
+	}
+	wg.Wait()
+	report.since("total_ns", start)
+
+	var failed error
+	for i, r := range results {
+		if errs[i] != nil {
+			results[i].Error = errs[i].Error()
+			if failed == nil {
+				failed = fmt.Errorf("%s: %w", r.Curve, errs[i])
+			}
+			continue
+		}
+		if *out != "" {
+			if err := writeCurveRun(filepath.Join(*out, r.Curve), r); err != nil {
+				return err
+			}
+		}
+	}
+	report.set("circuit", definition.ID())
+	report.set("statement", statement.describe(definition))
+	report.set("results", results)
+
+	report.printf("\n%-10s %11s %9s %9s %9s %9s %7s %7s %9s  %s\n",
+		"curve", "constraints", "compile", "setup", "prove", "verify", "proof", "vk", "pk", "verified")
+	for _, r := range results {
+		if r.Error != "" {
+			report.printf("%-10s ❌ %s\n", r.Curve, r.Error)
+			continue
+		}
+		report.printf("%-10s %11d %9v %9v %9v %9v %6dB %6dB %8dB  ✅\n",
+			r.Curve, r.Constraints,
+			r.Compile.Round(time.Microsecond*100), r.Setup.Round(time.Microsecond*100),
+			r.Prove.Round(time.Microsecond*100), r.Verify.Round(time.Microsecond*100),
+			r.ProofSize, r.VKSize, r.PKSize)
+	}
+	report.printf("Timings overlap: the curves ran at the same time. Use bench for one at a time.\n")
+	if *out != "" && failed == nil {
+		report.printf("Wrote each curve's vk.bin and proof.json under %s\n", *out)
+	}
+	return failed
+}
+
+// writeCurveRun writes the verifying key and proof envelope of one run to
+// dir, in the layout verify reads with -keys dir -proof dir/proof.json.
+func writeCurveRun(dir string, r benchResult) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	if _, err := keyfile.Write(filepath.Join(dir, verifyingKeyFile), r.vk, keyfile.Compressed); err != nil {
+		return err
+	}
+	return writeEnvelope(filepath.Join(dir, "proof.json"), r.env)
+}
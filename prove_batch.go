@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// batchResult is the outcome of proving a single row.
+type batchResult struct {
+	index int
+	path  string
+	took  time.Duration
+	err   error
+}
+
+// batchOutcome is how a row or proof is reported with -json.
+type batchOutcome struct {
+	Row     int    `json:"row,omitempty"`
+	Path    string `json:"path"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	ProveNs int64  `json:"prove_ns,omitempty"`
+}
+
+func newBatchOutcome(row int, path string, took time.Duration, err error) batchOutcome {
+	o := batchOutcome{Row: row, Path: path, OK: err == nil, ProveNs: took.Nanoseconds()}
+	if err != nil {
+		o.Error = err.Error()
+	}
+	return o
+}
+
+// runProveBatch compiles and sets up once, then proves every row of the input
+// file on a pool of workers, writing one envelope per row.
+func runProveBatch(args []string) error {
+	fs := flag.NewFlagSet("prove-batch", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	input := fs.String("input", "witnesses.jsonl", "JSONL or CSV file of age,min,max rows")
+	out := fs.String("out", "proofs", "directory to write proof envelopes to")
+	keys := fs.String("keys", "", "directory holding pk.bin and vk.bin (default: run setup and write them to -out)")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of proofs generated concurrently")
+	cache := fs.String("cache", defaultCacheDir(), "directory caching keys per circuit when -keys is not set (empty: always run setup)")
+	compress := addCompressFlag(fs)
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	rows, err := readBatchRows(*input)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+
+	// -----------------------------
+	// Compile and set up once
+	// -----------------------------
+	start := time.Now()
+	definition, ccs, err := compileRange(*bits)
+	if err != nil {
+		return err
+	}
+	var pk groth16.ProvingKey
+	var vk groth16.VerifyingKey
+	if *keys != "" {
+		if pk, err = readProvingKey(*keys); err != nil {
+			return err
+		}
+		if vk, err = readVerifyingKey(*keys); err != nil {
+			return err
+		}
+	} else {
+		if pk, vk, err = setupKeys(ccs, *cache); err != nil {
+			return err
+		}
+		if err := writeKeys(*out, pk, vk, keyfile.EncodingOf(*compress)); err != nil {
+			return err
+		}
+	}
+	report.set("circuit", definition.ID())
+	report.since("prepare_ns", start)
+	report.printf("Prepared circuit %s in %v\n", definition.ID(), time.Since(start).Round(time.Millisecond))
+
+	// -----------------------------
+	// Prove rows on a worker pool
+	// -----------------------------
+	if *workers < 1 {
+		*workers = 1
+	}
+	jobs := make(chan int)
+	results := make(chan batchResult)
+	var wg sync.WaitGroup
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				path := filepath.Join(*out, fmt.Sprintf("proof-%04d.json", i+1))
+				t := time.Now()
+				err := proveRow(definition, ccs, pk, vk, rows[i], path)
+				results <- batchResult{index: i, path: path, took: time.Since(t), err: err}
+			}
+		}()
+	}
+	go func() {
+		for i := range rows {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	start = time.Now()
+	var failed int
+	var total, fastest, slowest time.Duration
+	outcomes := make([]batchOutcome, len(rows))
+	for r := range results {
+		row := rows[r.index]
+		outcomes[r.index] = newBatchOutcome(r.index+1, r.path, r.took, r.err)
+		if r.err != nil {
+			failed++
+			report.printf("row %d (%d ≤ %d ≤ %d): ❌ %v\n", r.index+1, row.Min, row.Age, row.Max, r.err)
+			continue
+		}
+		total += r.took
+		if fastest == 0 || r.took < fastest {
+			fastest = r.took
+		}
+		if r.took > slowest {
+			slowest = r.took
+		}
+		report.printf("row %d: ✅ %v -> %s\n", r.index+1, r.took.Round(time.Millisecond), r.path)
+	}
+	wall := time.Since(start)
+
+	// -----------------------------
+	// Summary
+	// -----------------------------
+	proved := len(rows) - failed
+	report.set("rows", outcomes)
+	report.set("proved", proved)
+	report.set("failed", failed)
+	report.set("workers", *workers)
+	report.set("wall_ns", wall.Nanoseconds())
+	report.println("\n=== Summary ===")
+	report.printf("Rows:     %d (%d proved, %d failed)\n", len(rows), proved, failed)
+	report.printf("Workers:  %d\n", *workers)
+	report.printf("Wall:     %v\n", wall.Round(time.Millisecond))
+	if proved > 0 {
+		report.printf("Prove:    avg %v, min %v, max %v\n",
+			(total / time.Duration(proved)).Round(time.Millisecond),
+			fastest.Round(time.Millisecond), slowest.Round(time.Millisecond))
+		report.printf("Rate:     %.2f proofs/s\n", float64(proved)/wall.Seconds())
+	}
+	if failed > 0 {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("%d of %d rows could not be proven", failed, len(rows)))
+	}
+	return nil
+}
+
+// proveRow proves a single row and writes its envelope to path.
+func proveRow(definition *circuit.RangeCircuit, ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey, row circuit.AgeWitness, path string) error {
+	assignment, err := row.Build(definition, nil)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	witness, publicWitness, err := prover.NewWitness(curve, assignment)
+	if err != nil {
+		return err
+	}
+	proof, err := prover.Prove(ccs, pk, witness)
+	if err != nil {
+		return err
+	}
+	env, err := envelope.New(definition.ID(), vk, proof, publicWitness)
+	if err != nil {
+		return err
+	}
+	return writeEnvelope(path, env)
+}
+
+// readBatchRows reads rows from a CSV file (by extension) or from JSONL.
+// Unreadable files wrap zkp.ErrIO, unparsable rows zkp.ErrInvalidWitness.
+func readBatchRows(path string) ([]circuit.AgeWitness, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	defer f.Close()
+	var rows []circuit.AgeWitness
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		rows, err = readCSVRows(f)
+	} else {
+		rows, err = readJSONLRows(f)
+	}
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("%s: %w", path, err))
+	}
+	return rows, nil
+}
+
+func readJSONLRows(r io.Reader) ([]circuit.AgeWitness, error) {
+	var rows []circuit.AgeWitness
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var row circuit.AgeWitness
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+// readCSVRows expects age,min,max columns; a header row is skipped.
+func readCSVRows(r io.Reader) ([]circuit.AgeWitness, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var rows []circuit.AgeWitness
+	for i, rec := range records {
+		if len(rec) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 columns (age,min,max), got %d", i+1, len(rec))
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(rec[0]), "age") {
+			continue
+		}
+		var vals [3]int
+		for j, field := range rec {
+			vals[j], err = strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+		rows = append(rows, circuit.AgeWitness{Age: vals[0], Min: vals[1], Max: vals[2]})
+	}
+	return rows, nil
+}
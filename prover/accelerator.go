@@ -0,0 +1,66 @@
+package prover
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	gnarkbackend "github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	icicle_bn254 "github.com/consensys/gnark/backend/groth16/bn254/icicle"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// Accelerator is the hardware a proof is computed on.
+type Accelerator string
+
+const (
+	CPU Accelerator = "cpu"
+	// GPU proves with ICICLE on a CUDA device. It needs a binary built with
+	// `-tags icicle` and BN254; keys must be set up or read by that binary.
+	GPU Accelerator = "gpu"
+)
+
+// GPUBuilt reports whether this binary was built with the icicle tag.
+const GPUBuilt = icicle_bn254.HasIcicle
+
+// ParseAccelerator parses "cpu" or "gpu".
+func ParseAccelerator(s string) (Accelerator, error) {
+	switch a := Accelerator(s); a {
+	case CPU, GPU:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown accelerator %q (want %s or %s)", s, CPU, GPU)
+}
+
+// ProveOn is Prove on the requested accelerator. A GPU request falls back to
+// the CPU when the binary or the circuit cannot use it, or when the GPU
+// prover fails, e.g. for lack of a CUDA device; used reports where the proof
+// was computed and why not, if the GPU was requested and not used.
+func ProveOn(acc Accelerator, ccs constraint.ConstraintSystem, pk groth16.ProvingKey, full witness.Witness, opts ...gnarkbackend.ProverOption) (proof groth16.Proof, used Accelerator, fallback error, err error) {
+	if acc == GPU {
+		switch {
+		case !GPUBuilt:
+			fallback = fmt.Errorf("built without the icicle tag")
+		case curveOf(ccs) != ecc.BN254:
+			fallback = fmt.Errorf("ICICLE proving supports %s only, not %s", ecc.BN254, curveOf(ccs))
+		default:
+			if proof, fallback = proveGPU(ccs, pk, full, opts...); fallback == nil {
+				return proof, GPU, nil, nil
+			}
+		}
+	}
+	proof, err = Prove(ccs, pk, full, opts...)
+	return proof, CPU, fallback, err
+}
+
+// proveGPU proves with ICICLE, which panics rather than fail when no CUDA
+// backend can be loaded.
+func proveGPU(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, full witness.Witness, opts ...gnarkbackend.ProverOption) (proof groth16.Proof, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("icicle: %v", r)
+		}
+	}()
+	return groth16.Prove(ccs, pk, full, append(opts, gnarkbackend.WithIcicleAcceleration())...)
+}
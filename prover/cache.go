@@ -0,0 +1,93 @@
+package prover
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/logging"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// backend is mixed into fingerprints so keys for another proof system can
+// never be mistaken for Groth16 ones.
+const backend = "groth16"
+
+// Fingerprint identifies a compiled circuit: the hex SHA-256 of the backend,
+// the curve and the serialized constraint system. Any change to the circuit
+// definition changes it.
+func Fingerprint(ccs constraint.ConstraintSystem) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", backend, curveOf(ccs))
+	if _, err := ccs.WriteTo(h); err != nil {
+		return "", zkp.Wrap(zkp.ErrCompile, fmt.Errorf("fingerprint constraint system: %w", err))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CachedSetup is Setup backed by a cache directory. Keys are stored under
+// dir/<fingerprint>/ and reused by later calls for the same circuit; a
+// changed circuit simply has another fingerprint. hit reports whether the
+// keys came from the cache.
+//
+// The cache holds proving keys, whose setup randomness must stay secret in
+// any real deployment; it is meant for demos and development.
+func CachedSetup(dir string, ccs constraint.ConstraintSystem) (pk groth16.ProvingKey, vk groth16.VerifyingKey, hit bool, err error) {
+	start := time.Now()
+	fingerprint, err := Fingerprint(ccs)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	entry := filepath.Join(dir, fingerprint)
+	curve := curveOf(ccs)
+
+	pk, vk = groth16.NewProvingKey(curve), groth16.NewVerifyingKey(curve)
+	if readCached(filepath.Join(entry, "pk.bin"), pk) == nil && readCached(filepath.Join(entry, "vk.bin"), vk) == nil {
+		logging.Done("setup_done", start, "curve", curve.String(), "cached", true)
+		return pk, vk, true, nil
+	}
+
+	if pk, vk, err = Setup(ccs); err != nil {
+		return nil, nil, false, err
+	}
+	if err := os.MkdirAll(entry, 0o700); err != nil {
+		return nil, nil, false, zkp.Wrap(zkp.ErrIO, err)
+	}
+	if err := writeCached(filepath.Join(entry, "pk.bin"), pk); err != nil {
+		return nil, nil, false, err
+	}
+	if err := writeCached(filepath.Join(entry, "vk.bin"), vk); err != nil {
+		return nil, nil, false, err
+	}
+	return pk, vk, false, nil
+}
+
+// readCached fills v from path. Any failure, a missing, truncated or
+// corrupted file included, is a cache miss.
+func readCached(path string, v io.ReaderFrom) error {
+	return keyfile.Read(path, v)
+}
+
+// writeCached writes v to path raw: the cache trades disk space for reading
+// keys without decompressing their points. keyfile writes through a
+// temporary file, so concurrent users of the cache never read a partial key.
+func writeCached(path string, v keyfile.Artifact) error {
+	_, err := keyfile.Write(path, v, keyfile.Raw)
+	return err
+}
+
+func curveOf(ccs constraint.ConstraintSystem) ecc.ID {
+	if c, ok := ccs.(interface{ CurveID() ecc.ID }); ok {
+		return c.CurveID()
+	}
+	return ecc.UNKNOWN
+}
@@ -0,0 +1,143 @@
+package prover_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// The golden vectors under testdata/golden/range-16, made with
+// setup -seed golden-range-16 and prove -seed golden-range-16 -age 25
+// -min 18 -max 30 (see the README).
+const (
+	goldenDir  = "../testdata/golden/range-16"
+	goldenSeed = "golden-range-16"
+)
+
+func readGolden(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(goldenDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func goldenEnvelope(t *testing.T) *envelope.Envelope {
+	t.Helper()
+	env, err := envelope.Read(bytes.NewReader(readGolden(t, "proof.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func encode(t *testing.T, v io.WriterTo) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	if _, err := v.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// TestGoldenVerifies checks that the stored proof still verifies against
+// the stored key and its statement, and against no other.
+func TestGoldenVerifies(t *testing.T) {
+	definition, err := circuit.NewRangeCircuit(circuit.DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if err := keyfile.Read(filepath.Join(goldenDir, "vk.bin"), vk); err != nil {
+		t.Fatal(err)
+	}
+	env := goldenEnvelope(t)
+	for _, tc := range []struct {
+		name     string
+		min, max int
+		ok       bool
+	}{
+		{"statement", 18, 30, true},
+		{"other min", 19, 30, false},
+		{"other max", 18, 31, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assignment, err := definition.PublicAssignment(tc.min, tc.max, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := prover.NewPublicWitness(ecc.BN254, assignment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = env.VerifyStatement(definition.ID(), vk, expected)
+			if tc.ok && err != nil {
+				t.Errorf("%d ≤ Age ≤ %d: %v", tc.min, tc.max, err)
+			}
+			if !tc.ok && !errors.Is(err, zkp.ErrVerificationFailed) {
+				t.Errorf("%d ≤ Age ≤ %d = %v, want a failed verification", tc.min, tc.max, err)
+			}
+		})
+	}
+}
+
+// TestGoldenRegenerates checks that compiling, the seeded setup and the
+// seeded proof still give the stored bytes: a change in gnark or in the
+// circuit that alters them shows up here first.
+func TestGoldenRegenerates(t *testing.T) {
+	definition, err := circuit.NewRangeCircuit(circuit.DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ccs, err := prover.Compile(ecc.BN254, definition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encode(t, ccs), readGolden(t, "circuit.ccs")) {
+		t.Fatal("circuit.ccs: the compiled constraint system changed")
+	}
+	pk, vk, err := prover.SeededSetup(ccs, goldenSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encode(t, vk), readGolden(t, "vk.bin")) {
+		t.Error("vk.bin: the seeded setup gives another verifying key")
+	}
+	if !bytes.Equal(encode(t, pk), readGolden(t, "pk.bin")) {
+		t.Error("pk.bin: the seeded setup gives another proving key")
+	}
+
+	assignment, err := definition.Assign(25, 18, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, public, err := prover.NewWitness(ecc.BN254, assignment.WithChallenge(big.NewInt(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := prover.SeededProve(ccs, pk, full, goldenSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := envelope.New(definition.ID(), vk, proof, public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encode(t, env), readGolden(t, "proof.json")) {
+		t.Error("proof.json: the seeded proof changed")
+	}
+}
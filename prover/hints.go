@@ -0,0 +1,44 @@
+package prover
+
+import (
+	"fmt"
+	"regexp"
+
+	gnarkbackend "github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint/solver"
+
+	// The circuits' own hints, registered for every proof.
+	_ "github.com/ananthanir/hello-zkp/hints"
+)
+
+// WithHints returns a prover option making hint functions available to the
+// solver for one proof, on top of those registered with solver.RegisterHint
+// (which include package hints). A constraint system compiled from a circuit
+// that calls a hint can only be proven where that hint is available.
+func WithHints(fns ...solver.Hint) gnarkbackend.ProverOption {
+	return withSolverOptions(solver.WithHints(fns...))
+}
+
+// withSolverOptions returns a prover option adding solver options to those
+// already set. gnarkbackend.WithSolverOptions replaces them instead, so that
+// LowMemory and WithHints would not combine.
+func withSolverOptions(opts ...solver.Option) gnarkbackend.ProverOption {
+	return func(c *gnarkbackend.ProverConfig) error {
+		c.SolverOpts = append(c.SolverOpts, opts...)
+		return nil
+	}
+}
+
+// missingHints matches the solver error for a constraint system calling
+// hints that are not registered.
+var missingHints = regexp.MustCompile(`solver missing hint\(s\): \[.*\]`)
+
+// missingHint returns the error for a proof that failed because hints were
+// missing, or nil for any other failure. That is a fault of the prover's
+// build, not of the witness.
+func missingHint(err error) error {
+	if m := missingHints.FindString(err.Error()); m != "" {
+		return fmt.Errorf("%s (register them with solver.RegisterHint or pass prover.WithHints)", m)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package prover
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	gnarkbackend "github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// lowMemoryGCPercent is the heap growth that triggers a collection in
+// low-memory mode, against Go's default of 100%.
+const lowMemoryGCPercent = 20
+
+// LowMemory trades proving time for a lower peak memory, for small VMs and
+// Raspberry Pi-class devices. It makes the garbage collector run when the
+// heap has grown by a fifth rather than doubled, which bounds the garbage
+// piled up between collections, and returns prover options that solve the
+// witness on one goroutine instead of one per CPU. GOMEMLIMIT still applies
+// on top, for a hard target.
+//
+// The multi-scalar multiplications, which hold the proving key and the
+// largest buffers, are left as gnark runs them: the proving key has to be
+// resident either way, and gnark sizes their tasks by CPU count.
+func LowMemory() []gnarkbackend.ProverOption {
+	debug.SetGCPercent(lowMemoryGCPercent)
+	return []gnarkbackend.ProverOption{withSolverOptions(solver.WithNbTasks(1))}
+}
+
+// Release collects everything unreachable and returns the memory to the
+// operating system, so that the peak of a step that has finished, such as
+// compiling, does not add to the next one.
+func Release() {
+	runtime.GC()
+	debug.FreeOSMemory()
+}
@@ -0,0 +1,201 @@
+// Package prover runs the proving side of the flow: compile, setup, witness
+// construction and proving. Errors wrap the zkp sentinel errors.
+package prover
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	gnarkbackend "github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/logging"
+	"github.com/ananthanir/hello-zkp/tracing"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// Compile compiles a circuit definition to R1CS over the curve's scalar field.
+func Compile(curve ecc.ID, definition frontend.Circuit) (constraint.ConstraintSystem, error) {
+	return compile(context.Background(), curve, definition)
+}
+
+// CompileContext is Compile, returning early if ctx is done first.
+func CompileContext(ctx context.Context, curve ecc.ID, definition frontend.Circuit) (constraint.ConstraintSystem, error) {
+	return zkp.Run(ctx, func() (constraint.ConstraintSystem, error) {
+		return compile(ctx, curve, definition)
+	})
+}
+
+func compile(ctx context.Context, curve ecc.ID, definition frontend.Circuit) (constraint.ConstraintSystem, error) {
+	start := time.Now()
+	fields := []any{"curve", curve.String()}
+	if c, ok := definition.(interface{ ID() string }); ok {
+		fields = append(fields, "circuit", c.ID())
+	}
+	_, span := tracing.Start(ctx, "compile", fields...)
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, definition)
+	if err != nil {
+		span.End(err)
+		return nil, zkp.Wrap(zkp.ErrCompile, err)
+	}
+	fields = append(fields, "constraints", ccs.GetNbConstraints())
+	span.SetAttributes("constraints", ccs.GetNbConstraints())
+	span.End(nil)
+	logging.Done("compile_done", start, fields...)
+	return ccs, nil
+}
+
+// Setup runs the single-party Groth16 trusted setup.
+func Setup(ccs constraint.ConstraintSystem) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	return setup(context.Background(), ccs)
+}
+
+// SetupContext is Setup, returning early if ctx is done first.
+func SetupContext(ctx context.Context, ccs constraint.ConstraintSystem) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	type keys struct {
+		pk groth16.ProvingKey
+		vk groth16.VerifyingKey
+	}
+	k, err := zkp.Run(ctx, func() (keys, error) {
+		pk, vk, err := setup(ctx, ccs)
+		return keys{pk, vk}, err
+	})
+	return k.pk, k.vk, err
+}
+
+func setup(ctx context.Context, ccs constraint.ConstraintSystem) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	start := time.Now()
+	_, span := tracing.Start(ctx, "setup", "curve", curveOf(ccs).String(), "constraints", ccs.GetNbConstraints())
+	pk, vk, err := groth16.Setup(ccs)
+	span.End(err)
+	if err != nil {
+		return nil, nil, zkp.Wrap(zkp.ErrSetup, err)
+	}
+	logging.Done("setup_done", start, "curve", curveOf(ccs).String(), "cached", false)
+	return pk, vk, nil
+}
+
+// NewWitness builds the full witness for an assignment and its public part.
+// Every input must already lie in the curve's scalar field (see
+// circuit.ToField); none is reduced silently.
+func NewWitness(curve ecc.ID, assignment frontend.Circuit) (full, public witness.Witness, err error) {
+	return NewWitnessContext(context.Background(), curve, assignment)
+}
+
+// NewWitnessContext is NewWitness, recording its span as a child of the one
+// in ctx. Building a witness takes no time worth cancelling.
+func NewWitnessContext(ctx context.Context, curve ecc.ID, assignment frontend.Circuit) (full, public witness.Witness, err error) {
+	_, span := tracing.Start(ctx, "witness", "curve", curve.String())
+	defer func() { span.End(err) }()
+	if err := circuit.CheckAssignment(assignment, curve.ScalarField(), false); err != nil {
+		return nil, nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	full, err = frontend.NewWitness(assignment, curve.ScalarField())
+	if err != nil {
+		return nil, nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	public, err = full.Public()
+	if err != nil {
+		return nil, nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	return full, public, nil
+}
+
+// NewPublicWitness builds the public witness for an assignment of the public
+// inputs only, as a verifier does from the statement it expects.
+func NewPublicWitness(curve ecc.ID, assignment frontend.Circuit) (witness.Witness, error) {
+	if err := circuit.CheckAssignment(assignment, curve.ScalarField(), true); err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	public, err := frontend.NewWitness(assignment, curve.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	return public, nil
+}
+
+// Prove generates a proof. The only way proving fails on a well-formed key is
+// a witness that does not satisfy the constraints, hence ErrInvalidWitness,
+// or a circuit calling a hint this build does not register (see WithHints),
+// hence ErrCompile.
+// The error names the failing constraint but not the values on its wires,
+// which are derived from the private inputs.
+func Prove(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, full witness.Witness, opts ...gnarkbackend.ProverOption) (groth16.Proof, error) {
+	return prove(context.Background(), ccs, pk, full, opts...)
+}
+
+func prove(ctx context.Context, ccs constraint.ConstraintSystem, pk groth16.ProvingKey, full witness.Witness, opts ...gnarkbackend.ProverOption) (groth16.Proof, error) {
+	start := time.Now()
+	_, span := tracing.Start(ctx, "prove", "curve", curveOf(ccs).String(), "constraints", ccs.GetNbConstraints())
+	proof, err := groth16.Prove(ccs, pk, full, opts...)
+	if err != nil {
+		if missing := missingHint(err); missing != nil {
+			span.End(missing)
+			return nil, zkp.Wrap(zkp.ErrCompile, missing)
+		}
+		err = unsatisfied(err)
+		span.End(err)
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	span.End(nil)
+	logging.Done("prove_done", start, "curve", curveOf(ccs).String())
+	return proof, nil
+}
+
+// unsatisfiedConstraint matches the part of a solver error naming the
+// constraint, without the wire values gnark appends.
+var unsatisfiedConstraint = regexp.MustCompile(`constraint #\d+ is not satisfied`)
+
+func unsatisfied(err error) error {
+	if m := unsatisfiedConstraint.FindString(err.Error()); m != "" {
+		return errors.New(m)
+	}
+	return errors.New("witness does not satisfy the constraints")
+}
+
+// ProveContext is Prove, returning early if ctx is done first.
+func ProveContext(ctx context.Context, ccs constraint.ConstraintSystem, pk groth16.ProvingKey, full witness.Witness, opts ...gnarkbackend.ProverOption) (groth16.Proof, error) {
+	return zkp.Run(ctx, func() (groth16.Proof, error) {
+		return prove(ctx, ccs, pk, full, opts...)
+	})
+}
+
+// BuildPublicWitness builds the public witness of a statement from named
+// public inputs, putting them in the order the circuit declares them (see
+// circuit.PublicInputs). Inputs for another circuit, or with a missing,
+// unknown or duplicate name, fail with circuit.ErrPublicInputs, and values
+// outside the scalar field with circuit.ErrOutOfRange, all wrapped in
+// zkp.ErrInvalidWitness.
+func BuildPublicWitness(curve ecc.ID, definition circuit.Definition, inputs circuit.PublicInputs) (witness.Witness, error) {
+	canonical, err := inputs.Canonical(definition)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	values := canonical.Values()
+	for i, v := range values {
+		if _, err := circuit.ToField(canonical.Inputs[i].Name, v, curve.ScalarField()); err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+	}
+	public, err := witness.New(curve.ScalarField())
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	ch := make(chan any, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	if err := public.Fill(len(values), 0, ch); err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	return public, nil
+}
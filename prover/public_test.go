@@ -0,0 +1,120 @@
+package prover_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+func named(values ...string) circuit.PublicInputs {
+	p := circuit.PublicInputs{Circuit: "range/16"}
+	for i := 0; i < len(values); i += 2 {
+		p.Inputs = append(p.Inputs, circuit.PublicInput{Name: values[i], Value: values[i+1]})
+	}
+	return p
+}
+
+// TestBuildPublicWitness checks named inputs against the golden proof of
+// 18 ≤ Age ≤ 30: in any order they give the witness it verifies against,
+// and swapping two values gives one it does not.
+func TestBuildPublicWitness(t *testing.T) {
+	definition, err := circuit.NewRangeCircuit(circuit.DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if err := keyfile.Read(filepath.Join(goldenDir, "vk.bin"), vk); err != nil {
+		t.Fatal(err)
+	}
+	env := goldenEnvelope(t)
+	for _, tc := range []struct {
+		name   string
+		inputs circuit.PublicInputs
+		ok     bool
+	}{
+		{"declaration order", named("Min", "18", "Max", "30", "Challenge", "0", "Domain", "0"), true},
+		{"reordered", named("Max", "30", "Domain", "0", "Min", "18", "Challenge", "0"), true},
+		{"Min and Max swapped", named("Min", "30", "Max", "18", "Challenge", "0", "Domain", "0"), false},
+		{"Max and Challenge swapped", named("Min", "18", "Max", "0", "Challenge", "30", "Domain", "0"), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expected, err := prover.BuildPublicWitness(ecc.BN254, definition, tc.inputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = env.VerifyStatement(definition.ID(), vk, expected)
+			if tc.ok && err != nil {
+				t.Errorf("VerifyStatement: %v", err)
+			}
+			if !tc.ok && !errors.Is(err, zkp.ErrVerificationFailed) {
+				t.Errorf("VerifyStatement = %v, want a failed verification", err)
+			}
+		})
+	}
+}
+
+// TestBuildPublicWitnessMatchesAssignment checks that named inputs give the
+// same witness as the statement built from the circuit's assignment.
+func TestBuildPublicWitnessMatchesAssignment(t *testing.T) {
+	definition, err := circuit.NewRangeCircuit(circuit.DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := definition.PublicAssignment(18, 30, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := prover.NewPublicWitness(ecc.BN254, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := prover.BuildPublicWitness(ecc.BN254, definition, named("Domain", "0", "Challenge", "0", "Max", "30", "Min", "18"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBytes, err := got.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantBytes, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gotBytes, wantBytes) {
+		t.Error("named inputs and the assignment give different public witnesses")
+	}
+}
+
+func TestBuildPublicWitnessRejects(t *testing.T) {
+	definition, err := circuit.NewRangeCircuit(circuit.DefaultBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := ecc.BN254.ScalarField().String()
+	for _, tc := range []struct {
+		name   string
+		inputs circuit.PublicInputs
+		want   error
+	}{
+		{"missing", named("Min", "18", "Max", "30", "Challenge", "0"), circuit.ErrPublicInputs},
+		{"unknown", named("Min", "18", "Max", "30", "Challenge", "0", "Domain", "0", "age", "25"), circuit.ErrPublicInputs},
+		{"negative", named("Min", "-1", "Max", "30", "Challenge", "0", "Domain", "0"), circuit.ErrOutOfRange},
+		{"field order", named("Min", "18", "Max", "30", "Challenge", p, "Domain", "0"), circuit.ErrOutOfRange},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := prover.BuildPublicWitness(ecc.BN254, definition, tc.inputs)
+			if !errors.Is(err, tc.want) || !errors.Is(err, zkp.ErrInvalidWitness) {
+				t.Errorf("BuildPublicWitness = %v, want %v as an invalid witness", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package prover
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	mathrand "math/rand/v2"
+	"sync"
+
+	gnarkbackend "github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// The seeded variants below make keys and proofs reproducible across runs and
+// machines, for demos and golden test vectors.
+//
+// INSECURE, demo only. Anyone who knows the seed of a setup knows its toxic
+// waste and can forge proofs for any statement; anyone who knows the seed of
+// a proof can strip its zero-knowledge and learn the private inputs.
+
+// seedMu serializes seeded calls: gnark draws its randomness from
+// crypto/rand.Reader, which is swapped out for the duration of each call.
+var seedMu sync.Mutex
+
+// SeededSetup is Setup with the toxic waste derived from seed.
+func SeededSetup(ccs constraint.ConstraintSystem, seed string) (pk groth16.ProvingKey, vk groth16.VerifyingKey, err error) {
+	withSeed(seed, func() { pk, vk, err = Setup(ccs) })
+	return pk, vk, err
+}
+
+// SeededProve is Prove with the proof randomness derived from seed.
+func SeededProve(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, full witness.Witness, seed string, opts ...gnarkbackend.ProverOption) (proof groth16.Proof, err error) {
+	withSeed(seed, func() { proof, err = Prove(ccs, pk, full, opts...) })
+	return proof, err
+}
+
+// withSeed runs f with crypto/rand.Reader replaced by a ChaCha8 stream keyed
+// by SHA-256(seed). Nothing else in the process should draw randomness while
+// f runs.
+func withSeed(seed string, f func()) {
+	seedMu.Lock()
+	defer seedMu.Unlock()
+
+	reader := rand.Reader
+	rand.Reader = mathrand.NewChaCha8(sha256.Sum256([]byte(seed)))
+	defer func() { rand.Reader = reader }()
+
+	f()
+}
@@ -0,0 +1,56 @@
+package prover
+
+import (
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// compiled is one entry of the compiled-spec cache. once makes concurrent
+// callers asking for the same spec wait for a single compilation.
+type compiled struct {
+	once       sync.Once
+	definition circuit.Definition
+	ccs        constraint.ConstraintSystem
+	err        error
+}
+
+var (
+	compiledMu sync.Mutex
+	compiledBy = map[string]*compiled{}
+)
+
+// CompileSpec builds the circuit a spec describes and compiles it on curve.
+// Results, failures included, are kept for the life of the process, keyed
+// by curve and spec.Key, so a long-running caller varying parameters at
+// runtime compiles each shape once. The returned values are shared: callers
+// must not modify them.
+func CompileSpec(curve ecc.ID, spec circuit.StatementSpec) (circuit.Definition, constraint.ConstraintSystem, error) {
+	spec, err := spec.Normalize()
+	if err != nil {
+		return nil, nil, zkp.Wrap(zkp.ErrCompile, err)
+	}
+	key := curve.String() + "/" + spec.Key()
+
+	compiledMu.Lock()
+	entry, ok := compiledBy[key]
+	if !ok {
+		entry = &compiled{}
+		compiledBy[key] = entry
+	}
+	compiledMu.Unlock()
+
+	entry.once.Do(func() {
+		entry.definition, entry.err = spec.Build()
+		if entry.err != nil {
+			entry.err = zkp.Wrap(zkp.ErrCompile, entry.err)
+			return
+		}
+		entry.ccs, entry.err = Compile(curve, entry.definition)
+	})
+	return entry.definition, entry.ccs, entry.err
+}
@@ -0,0 +1,613 @@
+// Package qr renders text as a QR code (ISO/IEC 18004), so a proof can be
+// presented offline, e.g. shown on a phone at a point of sale.
+//
+// Text made only of the alphanumeric characters 0-9, A-Z and " $%*+-./:",
+// which is all envelope.Compact emits, is stored in the dense alphanumeric
+// mode; any other text is stored byte for byte in byte mode. The smallest
+// version holding the text at the requested error correction level is used,
+// with the mask chosen by the standard's penalty rules.
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// Level is the error correction level: the share of the code that can be
+// damaged and still read back.
+type Level int
+
+// Error correction levels, from about 7% (L) to about 30% (H) recoverable.
+const (
+	L Level = iota
+	M
+	Q
+	H
+)
+
+// alphanumeric is the QR alphanumeric character set, in code order.
+const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// QuietZone is the blank border, in modules, that Image draws around the
+// code, as scanners require.
+const QuietZone = 4
+
+// ErrTooLong is returned when the text does not fit in version 40.
+var ErrTooLong = errors.New("qr: text too long")
+
+// mode is a data encoding mode, as its 4-bit mode indicator.
+type mode int
+
+const (
+	alphanumericMode mode = 0x2
+	byteMode         mode = 0x4
+)
+
+// Code is an encoded QR symbol.
+type Code struct {
+	// Version is the symbol version, 1 to 40; the code is 17+4·Version
+	// modules wide.
+	Version int
+
+	size       int
+	modules    [][]bool // true is dark; indexed [y][x]
+	isFunction [][]bool
+}
+
+// Encode builds the QR code for text at the given error correction level.
+func Encode(text string, level Level) (*Code, error) {
+	m := alphanumericMode
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte(alphanumeric, text[i]) < 0 {
+			m = byteMode
+			break
+		}
+	}
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if segmentBits(m, len(text), v) <= numDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%w: %d characters", ErrTooLong, len(text))
+	}
+
+	data := encodeData(text, m, version, level)
+	c := newCode(version)
+	c.drawFunctionPatterns()
+	c.drawCodewords(addECCAndInterleave(data, version, level))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(level, mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask) // masks are involutions: this undoes it
+	}
+	c.applyMask(best)
+	c.drawFormatBits(level, best)
+	return c, nil
+}
+
+// Size returns the width of the code in modules, without the quiet zone.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark reports whether the module at (x, y) is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Image renders the code with scale pixels per module and a QuietZone
+// border.
+func (c *Code) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	width := (c.size + 2*QuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, width, width))
+	for py := 0; py < width; py++ {
+		for px := 0; px < width; px++ {
+			x, y := px/scale-QuietZone, py/scale-QuietZone
+			dark := x >= 0 && y >= 0 && x < c.size && y < c.size && c.modules[y][x]
+			if dark {
+				img.SetGray(px, py, color.Gray{Y: 0})
+			} else {
+				img.SetGray(px, py, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	return img
+}
+
+// String renders the code as text, two characters per module, for
+// terminals.
+func (c *Code) String() string {
+	var sb strings.Builder
+	for y := -QuietZone; y < c.size+QuietZone; y++ {
+		for x := -QuietZone; x < c.size+QuietZone; x++ {
+			if x >= 0 && y >= 0 && x < c.size && y < c.size && c.modules[y][x] {
+				sb.WriteString("██")
+			} else {
+				sb.WriteString("  ")
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func newCode(version int) *Code {
+	size := version*4 + 17
+	c := &Code{Version: version, size: size, modules: make([][]bool, size), isFunction: make([][]bool, size)}
+	for y := range size {
+		c.modules[y] = make([]bool, size)
+		c.isFunction[y] = make([]bool, size)
+	}
+	return c
+}
+
+// ---------------------------------------------------------------------------
+// Capacity
+// ---------------------------------------------------------------------------
+
+// eccCodewordsPerBlock and numErrorCorrectionBlocks are the standard's table
+// of error correction blocks, indexed [level][version]; index 0 is unused.
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// formatLevelBits is the two-bit level code written in the format bits.
+var formatLevelBits = [4]int{L: 1, M: 0, Q: 3, H: 2}
+
+// numRawDataModules returns how many modules of a version carry data or
+// error correction, i.e. are not function patterns.
+func numRawDataModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numErrorCorrectionBlocks[level][version]
+}
+
+// countBits is the width of the character count in mode m.
+func countBits(m mode, version int) int {
+	if m == byteMode {
+		if version <= 9 {
+			return 8
+		}
+		return 16
+	}
+	switch {
+	case version <= 9:
+		return 9
+	case version <= 26:
+		return 11
+	default:
+		return 13
+	}
+}
+
+// segmentBits is the length of a segment of n characters in mode m.
+func segmentBits(m mode, n, version int) int {
+	if m == byteMode {
+		return 4 + countBits(m, version) + n*8
+	}
+	return 4 + countBits(m, version) + n/2*11 + n%2*6
+}
+
+// ---------------------------------------------------------------------------
+// Data and error correction codewords
+// ---------------------------------------------------------------------------
+
+// bitBuffer accumulates bits most significant first.
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+// encodeData returns the data codewords: mode, count, characters,
+// terminator and padding.
+func encodeData(text string, m mode, version int, level Level) []byte {
+	var bits bitBuffer
+	bits.append(int(m), 4)
+	bits.append(len(text), countBits(m, version))
+	switch m {
+	case byteMode:
+		for i := 0; i < len(text); i++ {
+			bits.append(int(text[i]), 8)
+		}
+	case alphanumericMode:
+		for i := 0; i+1 < len(text); i += 2 {
+			bits.append(strings.IndexByte(alphanumeric, text[i])*45+strings.IndexByte(alphanumeric, text[i+1]), 11)
+		}
+		if len(text)%2 == 1 {
+			bits.append(strings.IndexByte(alphanumeric, text[len(text)-1]), 6)
+		}
+	}
+
+	capacity := numDataCodewords(version, level) * 8
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	data := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			data[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return data
+}
+
+// addECCAndInterleave splits the data into blocks, appends each block's
+// Reed-Solomon codewords and interleaves the blocks as the standard lays
+// them out.
+func addECCAndInterleave(data []byte, version int, level Level) []byte {
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	eccLen := eccCodewordsPerBlock[level][version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0) // placeholder, skipped when interleaving
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor returns the generator polynomial of the given degree,
+// highest coefficient first, without its leading 1.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// ---------------------------------------------------------------------------
+// Module placement
+// ---------------------------------------------------------------------------
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := range c.size {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.size-4, 3)
+	c.drawFinder(3, c.size-4)
+
+	positions := alignmentPositions(c.Version)
+	n := len(positions)
+	for i := range n {
+		for j := range n {
+			// The three corners taken by finder patterns
+			if i == 0 && j == 0 || i == 0 && j == n-1 || i == n-1 && j == 0 {
+				continue
+			}
+			c.drawAlignment(positions[i], positions[j])
+		}
+	}
+
+	c.drawFormatBits(L, 0) // reserve the area; overwritten once masked
+	c.drawVersion()
+}
+
+// drawFinder draws a finder pattern and its separator centred on (x, y).
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.size || yy >= c.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPositions returns the centre coordinates of the alignment
+// patterns, in increasing order.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	positions := make([]int, n)
+	positions[0] = 6
+	for i, pos := n-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// drawFormatBits writes both copies of the level and mask, protected by a
+// BCH(15,5) code, plus the dark module.
+func (c *Code) drawFormatBits(level Level, mask int) {
+	data := formatLevelBits[level]<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.size-15+i, bit(i))
+	}
+	c.setFunction(8, c.size-8, true)
+}
+
+// drawVersion writes both copies of the version, protected by a BCH(18,6)
+// code, from version 7 on.
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := c.Version<<12 | rem
+	for i := range 18 {
+		dark := bits>>i&1 == 1
+		a, b := c.size-11+i%3, i/3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords fills the non-function modules in the standard's zigzag,
+// two columns at a time from the bottom right.
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		for vert := range c.size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.size - 1 - vert // upward column
+				}
+				if !c.isFunction[y][x] && i < len(codewords)*8 {
+					c.modules[y][x] = codewords[i>>3]>>(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with one of the eight mask patterns.
+func (c *Code) applyMask(mask int) {
+	for y := range c.size {
+		for x := range c.size {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.isFunction[y][x] {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Mask penalty
+// ---------------------------------------------------------------------------
+
+// penalty scores the current modules by the standard's four rules; lower
+// scans more reliably.
+func (c *Code) penalty() int {
+	p := 0
+	for i := range c.size {
+		p += linePenalty(c.size, func(j int) bool { return c.modules[i][j] })
+		p += linePenalty(c.size, func(j int) bool { return c.modules[j][i] })
+	}
+
+	// 2×2 blocks of one colour
+	for y := 0; y < c.size-1; y++ {
+		for x := 0; x < c.size-1; x++ {
+			d := c.modules[y][x]
+			if d == c.modules[y][x+1] && d == c.modules[y+1][x] && d == c.modules[y+1][x+1] {
+				p += 3
+			}
+		}
+	}
+
+	// Balance of dark and light modules
+	dark := 0
+	for _, row := range c.modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	total := c.size * c.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return p + k*10
+}
+
+// linePenalty scores one row or column: runs of five or more modules of one
+// colour, and patterns that look like a finder (1:1:3:1:1 with four light
+// modules on either side).
+func linePenalty(size int, at func(int) bool) int {
+	p := 0
+	run := 1
+	for j := 1; j <= size; j++ {
+		if j < size && at(j) == at(j-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			p += 3 + run - 5
+		}
+		run = 1
+	}
+
+	// The quiet zone around the code counts as light.
+	dark := func(j int) bool { return j >= 0 && j < size && at(j) }
+	finder := []bool{true, false, true, true, true, false, true}
+	for j := -4; j < size+4-7+1; j++ {
+		match := true
+		for k, want := range finder {
+			if dark(j+k) != want {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		before, after := true, true
+		for k := 1; k <= 4; k++ {
+			before = before && !dark(j-k)
+			after = after && !dark(j+6+k)
+		}
+		if before || after {
+			p += 40
+		}
+	}
+	return p
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,236 @@
+// Package r1cs exports compiled constraint systems for inspection and for
+// external tooling: a JSON description (Describe) and the binary .r1cs format
+// of iden3/Circom (WriteIden3), which snarkjs reads.
+//
+// Wires are numbered as gnark numbers them, which is also the .r1cs order:
+// wire 0 is the constant 1, followed by the public inputs, the secret inputs
+// and the internal wires.
+package r1cs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+)
+
+// Format names the layout of Description.
+const Format = "hello-zkp-r1cs/1"
+
+var (
+	// ErrNotR1CS is returned for constraint systems of another arithmetization.
+	ErrNotR1CS = errors.New("not a rank-1 constraint system")
+
+	// ErrUnsupported is returned for constraint systems .r1cs cannot
+	// represent.
+	ErrUnsupported = errors.New("not exportable to .r1cs")
+)
+
+// Description is the JSON description of a constraint system.
+type Description struct {
+	Format       string   `json:"format"`
+	Circuit      string   `json:"circuit"`
+	Curve        string   `json:"curve"`
+	Field        string   `json:"field"` // scalar field modulus, decimal
+	Constraints  int      `json:"constraints"`
+	Coefficients int      `json:"coefficients"` // distinct coefficient values
+	Commitments  int      `json:"commitments"`  // Pedersen commitments (BSB22)
+	Wires        Wires    `json:"wires"`
+	Public       []string `json:"public"` // names of wires 0 to Wires.Public-1
+	Secret       []string `json:"secret"` // names of the following Wires.Secret wires
+	// R1CS lists the constraints A·B = C, if asked for.
+	R1CS []Constraint `json:"r1cs,omitempty"`
+}
+
+// Wires counts the wires by kind. Public includes the constant wire.
+type Wires struct {
+	Total    int `json:"total"`
+	Public   int `json:"public"`
+	Secret   int `json:"secret"`
+	Internal int `json:"internal"`
+}
+
+// Constraint is one rank-1 constraint ⟨A,w⟩·⟨B,w⟩ = ⟨C,w⟩ over the wires w.
+type Constraint struct {
+	A []Term `json:"a"`
+	B []Term `json:"b"`
+	C []Term `json:"c"`
+}
+
+// Term is a coefficient, in canonical form and decimal, times a wire.
+type Term struct {
+	Wire  int    `json:"wire"`
+	Coeff string `json:"coeff"`
+}
+
+// Describe returns the description of ccs, compiled from the circuit with
+// the given ID, listing every constraint if withConstraints is set.
+func Describe(id string, ccs constraint.ConstraintSystem, withConstraints bool) (*Description, error) {
+	r, ok := ccs.(constraint.R1CS)
+	if !ok {
+		return nil, ErrNotR1CS
+	}
+	internal, secret, public := ccs.GetNbVariables()
+	d := &Description{
+		Format:       Format,
+		Circuit:      id,
+		Curve:        curveOf(ccs),
+		Field:        ccs.Field().String(),
+		Constraints:  ccs.GetNbConstraints(),
+		Coefficients: ccs.GetNbCoefficients(),
+		Commitments:  len(ccs.GetCommitments().CommitmentIndexes()),
+		Wires:        Wires{Total: internal + secret + public, Public: public, Secret: secret, Internal: internal},
+		Public:       make([]string, public),
+		Secret:       make([]string, secret),
+	}
+	for i := range d.Public {
+		d.Public[i] = ccs.VariableToString(i)
+	}
+	for i := range d.Secret {
+		d.Secret[i] = ccs.VariableToString(public + i)
+	}
+	if !withConstraints {
+		return d, nil
+	}
+	d.R1CS = make([]Constraint, 0, d.Constraints)
+	for _, c := range r.GetR1Cs() {
+		d.R1CS = append(d.R1CS, Constraint{
+			A: describeTerms(ccs, c.L),
+			B: describeTerms(ccs, c.R),
+			C: describeTerms(ccs, c.O),
+		})
+	}
+	return d, nil
+}
+
+func describeTerms(ccs constraint.ConstraintSystem, l constraint.LinearExpression) []Term {
+	terms := make([]Term, len(l))
+	for i, t := range l {
+		terms[i] = Term{Wire: wire(t), Coeff: coefficient(ccs, t).String()}
+	}
+	return terms
+}
+
+// WriteIden3 writes ccs in iden3's .r1cs format, version 1: a header, the
+// constraints and the identity wire-to-label map. gnark has no output
+// signals, so every public wire is a public input. Circuits with Pedersen
+// commitments are refused: their commitment wires are computed by a hint the
+// format cannot express.
+func WriteIden3(w io.Writer, ccs constraint.ConstraintSystem) error {
+	r, ok := ccs.(constraint.R1CS)
+	if !ok {
+		return ErrNotR1CS
+	}
+	if len(ccs.GetCommitments().CommitmentIndexes()) > 0 {
+		return fmt.Errorf("%w: circuit uses Pedersen commitments", ErrUnsupported)
+	}
+	internal, secret, public := ccs.GetNbVariables()
+	nbWires := internal + secret + public
+	constraints := r.GetR1Cs()
+	if nbWires > math.MaxUint32 || len(constraints) > math.MaxUint32 {
+		return fmt.Errorf("%w: too many wires or constraints", ErrUnsupported)
+	}
+	n8 := (ccs.Field().BitLen() + 63) / 64 * 8
+
+	// Section 1: header
+	var header []byte
+	header = binary.LittleEndian.AppendUint32(header, uint32(n8))
+	header = append(header, le(ccs.Field(), n8)...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(nbWires))
+	header = binary.LittleEndian.AppendUint32(header, 0)                // public outputs
+	header = binary.LittleEndian.AppendUint32(header, uint32(public-1)) // public inputs, without the constant
+	header = binary.LittleEndian.AppendUint32(header, uint32(secret))
+	header = binary.LittleEndian.AppendUint64(header, uint64(nbWires)) // labels
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(constraints)))
+
+	// Section 2: constraints
+	var body []byte
+	for _, c := range constraints {
+		for _, l := range []constraint.LinearExpression{c.L, c.R, c.O} {
+			body = appendIden3Terms(body, ccs, l, n8)
+		}
+	}
+
+	// Section 3: wire i has label i
+	labels := make([]byte, 0, 8*nbWires)
+	for i := 0; i < nbWires; i++ {
+		labels = binary.LittleEndian.AppendUint64(labels, uint64(i))
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("r1cs")
+	binary.Write(bw, binary.LittleEndian, uint32(1)) // version
+	binary.Write(bw, binary.LittleEndian, uint32(3)) // sections
+	for i, section := range [][]byte{header, body, labels} {
+		binary.Write(bw, binary.LittleEndian, uint32(i+1))
+		binary.Write(bw, binary.LittleEndian, uint64(len(section)))
+		bw.Write(section)
+	}
+	return bw.Flush()
+}
+
+// appendIden3Terms appends a linear combination, with one term per wire and
+// the wires in increasing order as .r1cs readers expect.
+func appendIden3Terms(b []byte, ccs constraint.ConstraintSystem, l constraint.LinearExpression, n8 int) []byte {
+	sums := map[int]*big.Int{}
+	var wires []int
+	for _, t := range l {
+		v := wire(t)
+		if sums[v] == nil {
+			sums[v] = new(big.Int)
+			wires = append(wires, v)
+		}
+		sums[v].Add(sums[v], coefficient(ccs, t))
+	}
+	slices.Sort(wires)
+	kept := wires[:0]
+	for _, v := range wires {
+		if sums[v].Mod(sums[v], ccs.Field()).Sign() != 0 {
+			kept = append(kept, v)
+		}
+	}
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(kept)))
+	for _, v := range kept {
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+		b = append(b, le(sums[v], n8)...)
+	}
+	return b
+}
+
+// wire returns the wire of a term. gnark marks constants with the largest
+// wire ID; they multiply the constant wire 0.
+func wire(t constraint.Term) int {
+	if t.VID == math.MaxUint32 {
+		return 0
+	}
+	return int(t.VID)
+}
+
+// coefficient returns the coefficient of a term in canonical form.
+func coefficient(ccs constraint.ConstraintSystem, t constraint.Term) *big.Int {
+	return ccs.ToBigInt(ccs.GetCoefficient(int(t.CID)))
+}
+
+// le returns v as n little-endian bytes.
+func le(v *big.Int, n int) []byte {
+	b := make([]byte, n)
+	v.FillBytes(b)
+	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+func curveOf(ccs constraint.ConstraintSystem) string {
+	if c, ok := ccs.(interface{ CurveID() ecc.ID }); ok {
+		return c.CurveID().String()
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+
+	"github.com/ananthanir/hello-zkp/challenge"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runRecursive is the proof composition demo: it proves Min ≤ Age ≤ Max on
+// the inner curve, then proves "I hold a valid age proof" on the outer
+// curve, and verifies only the outer proof.
+func runRecursive(args []string) error {
+	fs := flag.NewFlagSet("recursive", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	cache := fs.String("cache", defaultCacheDir(), "directory caching keys per circuit (empty: always run setup)")
+	inputs := addInputFlags(fs, "age", "min", "max")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	sources, err := inputs.sources()
+	if err != nil {
+		return err
+	}
+	values, err := resolveInputs(sources, "age", "min", "max")
+	if err != nil {
+		return err
+	}
+	age, min, max := values["age"], values["min"], values["max"]
+
+	// -----------------------------
+	// 1) Inner proof on BLS12-377
+	// -----------------------------
+	inner, err := circuit.NewRangeCircuit(*bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	assignment, err := inner.Assign(age, min, max)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	nonce, err := challenge.New()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	innerCcs, err := prover.Compile(circuit.InnerCurve, inner)
+	if err != nil {
+		return err
+	}
+	innerPk, innerVk, err := setupKeys(innerCcs, *cache)
+	if err != nil {
+		return err
+	}
+	innerWitness, innerPublic, err := prover.NewWitness(circuit.InnerCurve, assignment.WithChallenge(nonce))
+	if err != nil {
+		return err
+	}
+	// The inner proof has to be built with the hash the outer verifier
+	// gadget uses in-circuit.
+	innerProof, err := prover.Prove(innerCcs, innerPk, innerWitness,
+		stdgroth16.GetNativeProverOptions(circuit.OuterCurve.ScalarField(), circuit.InnerCurve.ScalarField()))
+	if err != nil {
+		report.println("Inner proof: ❌ FAILED (witness does not satisfy constraints)")
+		return err
+	}
+	report.since("inner_ns", start)
+	report.printf("Inner proof: %s on %s, %d constraints\n", inner.ID(), circuit.InnerCurve, innerCcs.GetNbConstraints())
+	report.set("inner", map[string]any{
+		"circuit":     inner.ID(),
+		"curve":       circuit.InnerCurve.String(),
+		"constraints": innerCcs.GetNbConstraints(),
+	})
+
+	// -----------------------------
+	// 2) Outer circuit on BW6-761
+	// -----------------------------
+	outer, err := circuit.NewRecursiveRangeCircuit(inner, innerCcs, innerVk)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	start = time.Now()
+	outerCcs, err := prover.Compile(circuit.OuterCurve, outer)
+	if err != nil {
+		return err
+	}
+	report.since("compile_ns", start)
+	report.printf("Outer circuit: %s on %s, %d constraints\n", outer.ID(), circuit.OuterCurve, outerCcs.GetNbConstraints())
+	report.set("circuit", outer.ID())
+	report.set("curve", circuit.OuterCurve.String())
+	report.set("constraints", outerCcs.GetNbConstraints())
+
+	start = time.Now()
+	outerPk, outerVk, err := setupKeys(outerCcs, *cache)
+	if err != nil {
+		return err
+	}
+	report.since("setup_ns", start)
+
+	// -----------------------------
+	// 3) Outer proof: the inner proof is its private input
+	// -----------------------------
+	outerAssignment, err := outer.Assign(innerProof, innerPublic)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	outerWitness, _, err := prover.NewWitness(circuit.OuterCurve, outerAssignment)
+	if err != nil {
+		return err
+	}
+	start = time.Now()
+	outerProof, err := prover.Prove(outerCcs, outerPk, outerWitness)
+	if err != nil {
+		report.println("Outer proof: ❌ FAILED (inner proof does not verify)")
+		return err
+	}
+	report.since("prove_ns", start)
+
+	// -----------------------------
+	// 4) Verify the outer proof from the public statement alone
+	// -----------------------------
+	expected, err := inner.PublicAssignment(min, max, nonce)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	innerStatement, err := prover.NewPublicWitness(circuit.InnerCurve, expected)
+	if err != nil {
+		return err
+	}
+	outerStatement, err := outer.PublicAssignment(innerStatement)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	publicWitness, err := prover.NewPublicWitness(circuit.OuterCurve, outerStatement)
+	if err != nil {
+		return err
+	}
+	start = time.Now()
+	err = zkp.Verify(outerProof, outerVk, publicWitness)
+	report.since("verify_ns", start)
+	return reportVerification(err)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+// peakRSS is only known on Unix.
+func peakRSS() (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package main
+
+import (
+	"runtime"
+	"syscall"
+)
+
+// peakRSS returns the largest resident set size of the process so far, in
+// bytes.
+func peakRSS() (int64, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, false
+	}
+	// Linux and the BSDs count kilobytes, macOS bytes.
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		return int64(usage.Maxrss), true
+	}
+	return int64(usage.Maxrss) * 1024, true
+}
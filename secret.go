@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// readHidden runs scan with echo turned off on the terminal f, for private
+// inputs typed at a prompt. Where stty is missing (Windows) the input is
+// echoed.
+func readHidden(f *os.File, scan func() error) error {
+	off := exec.Command("stty", "-echo")
+	off.Stdin = f
+	if off.Run() == nil {
+		defer func() {
+			on := exec.Command("stty", "echo")
+			on.Stdin = f
+			on.Run()
+			report.println()
+		}()
+	}
+	return scan()
+}
@@ -0,0 +1,118 @@
+// Package secret reads private inputs from secret references, which name
+// where a value is kept so that it never appears on the command line, in
+// shell history or on the terminal:
+//
+//	file:age.txt          a file readable by its owner only (chmod 600)
+//	env:AGE               an environment variable, unset once read
+//	keyring:hello-zkp/age an OS keyring entry (service/account): the macOS
+//	                      Keychain, or the Secret Service via secret-tool
+//
+// Errors never include the secret itself.
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// RefHelp describes the reference forms, for flag usage and errors.
+const RefHelp = "file:<path> (mode 0600), env:<name> or keyring:<service>/<account>"
+
+// Read returns the value a secret reference points to, trimmed.
+// Errors wrap zkp.ErrInvalidWitness, or zkp.ErrIO if the source cannot be
+// read.
+func Read(ref string) (string, error) {
+	kind, where, ok := strings.Cut(ref, ":")
+	if !ok || where == "" {
+		return "", zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("secret %q: expected %s", ref, RefHelp))
+	}
+	var value []byte
+	switch kind {
+	case "file":
+		if err := CheckFile(where); err != nil {
+			return "", err
+		}
+		data, err := os.ReadFile(where)
+		if err != nil {
+			return "", zkp.Wrap(zkp.ErrIO, err)
+		}
+		value = data
+	case "env":
+		v, ok := os.LookupEnv(where)
+		if !ok {
+			return "", zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("secret %s: $%s is not set", ref, where))
+		}
+		// Child processes, such as the keyring tools, must not inherit it.
+		os.Unsetenv(where)
+		value = []byte(v)
+	case "keyring":
+		data, err := readKeyring(where)
+		if err != nil {
+			return "", err
+		}
+		value = data
+	default:
+		return "", zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("secret %q: unknown source %q, expected %s", ref, kind, RefHelp))
+	}
+	return string(bytes.TrimSpace(value)), nil
+}
+
+// ReadInt is Read for an integer input.
+func ReadInt(ref string) (int, error) {
+	s, err := Read(ref)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("secret %s is not an integer", ref))
+	}
+	return v, nil
+}
+
+// CheckFile rejects a file holding private inputs that its group or
+// others can read. Windows has no such permission bits to check.
+func CheckFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("%s holds private inputs but has mode %04o; run chmod 600 %s", path, info.Mode().Perm(), path))
+	}
+	return nil
+}
+
+// readKeyring looks up a service/account entry with the OS keyring tool.
+func readKeyring(entry string) ([]byte, error) {
+	service, account, ok := strings.Cut(entry, "/")
+	if !ok || service == "" || account == "" {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("keyring entry %q: expected service/account", entry))
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("no OS keyring support on %s", runtime.GOOS))
+	}
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	switch {
+	case errors.As(err, &exit):
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("keyring entry %s not found (%s exited with %d)", entry, cmd.Args[0], exit.ExitCode()))
+	case err != nil:
+		return nil, zkp.Wrap(zkp.ErrIO, fmt.Errorf("keyring entry %s: %w", entry, err))
+	}
+	return out, nil
+}
@@ -0,0 +1,108 @@
+// Package session is the wire protocol between a verifier and a prover
+// running as separate processes, as they would in a real deployment.
+//
+// The verifier connects, sends a Request naming the statement it wants proven
+// and a fresh challenge, and reads back a Response carrying a proof envelope.
+// The holder's age never leaves the prover process; the verifier only ever
+// sees the proof and the public inputs it chose itself. Each connection
+// carries a single exchange, as one JSON message in each direction.
+//
+// Addresses are host:port for TCP, or unix:<path> for a Unix socket.
+// Errors wrap zkp.ErrIO.
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// MaxMessageSize bounds the bytes read for a single message, so a peer
+// cannot make the other side buffer without limit.
+const MaxMessageSize = 1 << 20
+
+// unixPrefix marks a Unix socket address.
+const unixPrefix = "unix:"
+
+// ErrRefused is returned to the verifier when the prover declined to prove
+// the requested statement.
+var ErrRefused = errors.New("prover refused")
+
+// Request is what the verifier asks the prover to prove: Min ≤ Age ≤ Max for
+// the given circuit, bound to a challenge in challenge.Format form and to the
+// verifier's domain, an application context string (empty for none).
+type Request struct {
+	Circuit   string `json:"circuit"`
+	Min       int    `json:"min"`
+	Max       int    `json:"max"`
+	Challenge string `json:"challenge"`
+	Domain    string `json:"domain,omitempty"`
+}
+
+// Response is the prover's answer: an envelope, or the reason it has none.
+type Response struct {
+	Envelope *envelope.Envelope `json:"envelope,omitempty"`
+	Error    string             `json:"error,omitempty"`
+}
+
+// Err returns the prover's refusal as an error, or nil if it sent a proof.
+func (r *Response) Err() error {
+	if r.Envelope != nil {
+		return nil
+	}
+	if r.Error == "" {
+		return fmt.Errorf("%w: empty response", ErrRefused)
+	}
+	return fmt.Errorf("%w: %s", ErrRefused, r.Error)
+}
+
+// Listen opens the socket the prover serves requests on.
+func Listen(addr string) (net.Listener, error) {
+	network, address := split(addr)
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	return l, nil
+}
+
+// Dial connects the verifier to a prover.
+func Dial(ctx context.Context, addr string) (net.Conn, error) {
+	network, address := split(addr)
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	return conn, nil
+}
+
+// Send writes one message.
+func Send(w io.Writer, msg any) error {
+	if err := json.NewEncoder(w).Encode(msg); err != nil {
+		return zkp.Wrap(zkp.ErrIO, fmt.Errorf("send: %w", err))
+	}
+	return nil
+}
+
+// Receive reads one message into msg.
+func Receive(r io.Reader, msg any) error {
+	if err := json.NewDecoder(io.LimitReader(r, MaxMessageSize)).Decode(msg); err != nil {
+		return zkp.Wrap(zkp.ErrIO, fmt.Errorf("receive: %w", err))
+	}
+	return nil
+}
+
+func split(addr string) (network, address string) {
+	if path, ok := strings.CutPrefix(addr, unixPrefix); ok {
+		return "unix", path
+	}
+	return "tcp", addr
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/prover"
+)
+
+// runSetup compiles the circuit and writes a fresh proving/verifying key pair.
+func runSetup(args []string) error {
+	fs := flag.NewFlagSet("setup", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	name := fs.String("circuit", "range", "circuit to set up")
+	keys := fs.String("keys", "keys", "directory to write pk.bin and vk.bin to")
+	seed := fs.String("seed", "", "derive the setup randomness from this seed (INSECURE, demo only)")
+	compress := addCompressFlag(fs)
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	start := time.Now()
+	definition, ccs, err := compileCircuit(*name, *bits)
+	if err != nil {
+		return err
+	}
+	report.since("compile_ns", start)
+	report.set("circuit", definition.ID())
+	report.set("constraints", ccs.GetNbConstraints())
+
+	start = time.Now()
+	var pk groth16.ProvingKey
+	var vk groth16.VerifyingKey
+	if *seed != "" {
+		warnSeeded("setup", "anyone who knows the seed can forge proofs")
+		pk, vk, err = prover.SeededSetup(ccs, *seed)
+	} else {
+		pk, vk, err = prover.Setup(ccs)
+	}
+	if err != nil {
+		return err
+	}
+	report.since("setup_ns", start)
+	if err := writeKeys(*keys, pk, vk, keyfile.EncodingOf(*compress)); err != nil {
+		return err
+	}
+	report.set("keys", *keys)
+
+	report.printf("Setup: ✅ wrote keys for circuit %s to %s\n", definition.ID(), *keys)
+	return nil
+}
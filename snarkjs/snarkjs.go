@@ -0,0 +1,122 @@
+// Package snarkjs converts Groth16 artifacts into the JSON formats used by
+// snarkjs and the wider Circom tooling, so proofs generated here can be
+// checked with `snarkjs groth16 verify verification_key.json public.json
+// proof.json`.
+//
+// Only BN254 (snarkjs' "bn128") is supported, and only circuits without
+// Pedersen commitments, which snarkjs has no notion of.
+package snarkjs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+const (
+	protocol = "groth16"
+	curve    = "bn128"
+)
+
+// ErrUnsupported is returned for artifacts snarkjs cannot represent.
+var ErrUnsupported = errors.New("not exportable to snarkjs")
+
+// VerifyingKey is the layout of snarkjs' verification_key.json.
+type VerifyingKey struct {
+	Protocol string       `json:"protocol"`
+	Curve    string       `json:"curve"`
+	NPublic  int          `json:"nPublic"`
+	Alpha1   [3]string    `json:"vk_alpha_1"`
+	Beta2    [3][2]string `json:"vk_beta_2"`
+	Gamma2   [3][2]string `json:"vk_gamma_2"`
+	Delta2   [3][2]string `json:"vk_delta_2"`
+	IC       [][3]string  `json:"IC"`
+}
+
+// Proof is the layout of snarkjs' proof.json.
+type Proof struct {
+	A        [3]string    `json:"pi_a"`
+	B        [3][2]string `json:"pi_b"`
+	C        [3]string    `json:"pi_c"`
+	Protocol string       `json:"protocol"`
+	Curve    string       `json:"curve"`
+}
+
+// ExportVerifyingKey converts a verifying key to snarkjs' format.
+func ExportVerifyingKey(vk groth16.VerifyingKey) (*VerifyingKey, error) {
+	k, ok := vk.(*groth16bn254.VerifyingKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: verifying key is for %s, only %s is supported", ErrUnsupported, vk.CurveID(), ecc.BN254)
+	}
+	if len(k.CommitmentKeys) > 0 {
+		return nil, fmt.Errorf("%w: circuit uses Pedersen commitments", ErrUnsupported)
+	}
+	// K[0] is the constant wire, as is IC[0] in snarkjs.
+	ic := make([][3]string, len(k.G1.K))
+	for i := range k.G1.K {
+		ic[i] = g1(&k.G1.K[i])
+	}
+	return &VerifyingKey{
+		Protocol: protocol,
+		Curve:    curve,
+		NPublic:  len(k.G1.K) - 1,
+		Alpha1:   g1(&k.G1.Alpha),
+		Beta2:    g2(&k.G2.Beta),
+		Gamma2:   g2(&k.G2.Gamma),
+		Delta2:   g2(&k.G2.Delta),
+		IC:       ic,
+	}, nil
+}
+
+// ExportProof converts a proof to snarkjs' format.
+func ExportProof(proof groth16.Proof) (*Proof, error) {
+	p, ok := proof.(*groth16bn254.Proof)
+	if !ok {
+		return nil, fmt.Errorf("%w: proof is for %s, only %s is supported", ErrUnsupported, proof.CurveID(), ecc.BN254)
+	}
+	if len(p.Commitments) > 0 {
+		return nil, fmt.Errorf("%w: proof carries Pedersen commitments", ErrUnsupported)
+	}
+	return &Proof{
+		A:        g1(&p.Ar),
+		B:        g2(&p.Bs),
+		C:        g1(&p.Krs),
+		Protocol: protocol,
+		Curve:    curve,
+	}, nil
+}
+
+// ExportPublicInputs converts a public witness to snarkjs' public.json: the
+// public inputs as decimal strings, in declaration order.
+func ExportPublicInputs(publicWitness witness.Witness) ([]string, error) {
+	values, ok := publicWitness.Vector().(fr.Vector)
+	if !ok {
+		return nil, fmt.Errorf("%w: public inputs are not over the %s scalar field", ErrUnsupported, ecc.BN254)
+	}
+	public := make([]string, len(values))
+	for i := range values {
+		public[i] = values[i].String()
+	}
+	return public, nil
+}
+
+// g1 writes an affine point in projective form with Z = 1.
+func g1(p *bn254.G1Affine) [3]string {
+	return [3]string{p.X.String(), p.Y.String(), "1"}
+}
+
+// g2 writes an affine point in projective form with Z = 1; each coordinate
+// is an [A0, A1] pair.
+func g2(p *bn254.G2Affine) [3][2]string {
+	return [3][2]string{
+		{p.X.A0.String(), p.X.A1.String()},
+		{p.Y.A0.String(), p.Y.A1.String()},
+		{"1", "0"},
+	}
+}
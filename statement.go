@@ -0,0 +1,492 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/challenge"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/commitment"
+	"github.com/ananthanir/hello-zkp/domain"
+	"github.com/ananthanir/hello-zkp/secret"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// statementFlags are the statement inputs shared by prove and verify. Which
+// of them are used depends on the circuit.
+type statementFlags struct {
+	age        *int
+	ageFrom    *string
+	ageMonths  *int
+	min        *int
+	max        *int
+	ranges     *string
+	brackets   *string
+	challenge  *string
+	opening    *string
+	commitment *string
+	policy     *string
+	id         *int
+	blocklist  *string
+	ttl        *time.Duration
+	issuedAt   *int64
+	country    *string
+	tier       *string
+	countries  *string
+	minTier    *string
+	domain     *string
+	vars       *string
+
+	// window is the validity window a verifier read from the envelope
+	// (expiring-range); it is checked against the clock, not pinned.
+	window *circuit.Window
+
+	// bracket is the bracket a verifier read from the envelope
+	// (age-bracket): the proof's output, which it cannot pin.
+	bracket *int
+}
+
+func addStatementFlags(fs *flag.FlagSet, prover bool) *statementFlags {
+	f := &statementFlags{
+		min:       fs.Int("min", 0, "public Min bound"),
+		max:       fs.Int("max", 0, "public Max bound"),
+		ranges:    fs.String("ranges", "", "comma-separated public min-max ranges, e.g. 0-17,65-150 (any-range)"),
+		brackets:  fs.String("brackets", "", "comma-separated bracket edges, e.g. 18,26,41,65 for <18, 18-25, 26-40, 41-64, 65+ (age-bracket; default: those)"),
+		challenge: fs.String("challenge", "", "hex challenge issued by the verifier"),
+		countries: fs.String("countries", "", "comma-separated allowed country codes to disclose membership of (credential)"),
+		minTier:   fs.String("min-tier", "", "minimum membership tier to disclose (credential)"),
+		domain:    fs.String("domain", "", "application context the proof is bound to, e.g. bar-entry-check-v1 (range)"),
+		vars:      fs.String("vars", "", "comma-separated name=value inputs, e.g. age=25,min=18 (expr:; verifiers give the public ones)"),
+	}
+	if prover {
+		f.age = fs.Int("age", 0, "private Age (range)")
+		f.ageFrom = fs.String("age-from", "", "read the private Age from "+secret.RefHelp+" instead of -age")
+		f.ageMonths = fs.Int("age-months", 0, "private age in whole months (age-months; -min and -max are in years)")
+		f.opening = fs.String("opening", "opening.json", "commitment opening (committed-range)")
+		f.policy = fs.String("policy", "policy.json", "policy opening shared by the verifier (policy-range)")
+		f.id = fs.Int("id", 0, "private identifier (non-membership)")
+		f.blocklist = fs.String("blocklist", "blocklist.json", "blocklist published by the issuer (non-membership)")
+		f.ttl = fs.Duration("ttl", 24*time.Hour, "how long the proof stays valid (expiring-range)")
+		f.issuedAt = fs.Int64("issued-at", 0, "Unix time the proof is valid from (expiring-range; default: now)")
+		f.country = fs.String("country", "", "private two-letter country code (credential)")
+		f.tier = fs.String("tier", "basic", "private membership tier: basic, silver, gold or platinum (credential)")
+	} else {
+		f.commitment = fs.String("commitment", "", "hex commitment the age was registered with (committed-range)")
+		f.policy = fs.String("policy", "", "hex policy commitment published by the verifier (policy-range)")
+		f.blocklist = fs.String("blocklist", "", "hex blocklist root published by the issuer (non-membership)")
+	}
+	return f
+}
+
+// assignment returns the full witness assignment for the given circuit.
+// Errors wrap zkp.ErrInvalidWitness.
+func (f *statementFlags) assignment(definition circuit.Definition) (frontend.Circuit, error) {
+	ch, err := f.parseChallenge()
+	if err != nil {
+		return nil, err
+	}
+	if *f.ageFrom != "" {
+		if *f.age, err = secret.ReadInt(*f.ageFrom); err != nil {
+			return nil, err
+		}
+	}
+	if err := f.checkDomain(definition); err != nil {
+		return nil, err
+	}
+	switch c := definition.(type) {
+	case *circuit.RangeCircuit:
+		assignment, err := c.Assign(*f.age, *f.min, *f.max)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch).WithDomain(domain.Hash(*f.domain)), nil
+	case *circuit.HashedRangeCircuit:
+		if err := checkHashedCurve(); err != nil {
+			return nil, err
+		}
+		assignment, err := c.Assign(*f.age, *f.min, *f.max)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch).WithDomain(domain.Hash(*f.domain)), nil
+	case *circuit.ExpiringRangeCircuit:
+		now := time.Now()
+		issuedAt := now
+		if *f.issuedAt != 0 {
+			issuedAt = time.Unix(*f.issuedAt, 0)
+		}
+		window := circuit.NewWindow(now, issuedAt, *f.ttl)
+		assignment, err := c.Assign(*f.age, *f.min, *f.max, window)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	case *circuit.CommittedRangeCircuit:
+		opening, err := commitment.Load(*f.opening)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("load opening %s: %w", *f.opening, err))
+		}
+		assignment, err := c.Assign(opening, *f.min, *f.max)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	case *circuit.PolicyRangeCircuit:
+		policy, err := commitment.LoadPolicy(*f.policy)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("load policy %s: %w", *f.policy, err))
+		}
+		assignment, err := c.Assign(*f.age, policy)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	case *circuit.AnyRangeCircuit:
+		ranges, err := f.parseRanges()
+		if err != nil {
+			return nil, err
+		}
+		assignment, err := c.Assign(*f.age, ranges)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	case *circuit.MonthsCircuit:
+		assignment, err := c.Assign(*f.ageMonths, *f.min, *f.max)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	case *circuit.BracketCircuit:
+		brackets, err := f.parseBrackets()
+		if err != nil {
+			return nil, err
+		}
+		assignment, err := c.Assign(*f.age, brackets)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	case *circuit.CredentialCircuit:
+		d, err := f.disclosure(c)
+		if err != nil {
+			return nil, err
+		}
+		tier, err := circuit.ParseTier(*f.tier)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		assignment, err := c.Assign(circuit.Credential{Age: *f.age, Country: *f.country, Tier: tier}, d)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	case *circuit.NonMembershipCircuit:
+		list, err := commitment.LoadBlocklist(*f.blocklist)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("load blocklist %s: %w", *f.blocklist, err))
+		}
+		assignment, err := c.Assign(list, *f.id)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	case *circuit.ExprCircuit:
+		values, err := parseVars(*f.vars)
+		if err != nil {
+			return nil, err
+		}
+		assignment, err := c.Assign(values)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithChallenge(ch), nil
+	}
+	return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("circuit %s does not take statement flags", definition.ID()))
+}
+
+// publicAssignment returns the public inputs a verifier expects.
+// Errors wrap zkp.ErrInvalidWitness.
+func (f *statementFlags) publicAssignment(definition circuit.Definition) (frontend.Circuit, error) {
+	ch, err := f.parseChallenge()
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkDomain(definition); err != nil {
+		return nil, err
+	}
+	switch c := definition.(type) {
+	case *circuit.RangeCircuit:
+		assignment, err := c.PublicAssignment(*f.min, *f.max, ch)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithDomain(domain.Hash(*f.domain)), nil
+	case *circuit.HashedRangeCircuit:
+		if err := checkHashedCurve(); err != nil {
+			return nil, err
+		}
+		assignment, err := c.PublicAssignment(*f.min, *f.max, ch)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment.WithDomain(domain.Hash(*f.domain)), nil
+	case *circuit.ExpiringRangeCircuit:
+		if f.window == nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, errors.New("no validity window to check"))
+		}
+		assignment, err := c.PublicAssignment(*f.min, *f.max, ch, *f.window)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment, nil
+	case *circuit.CommittedRangeCircuit:
+		committed, err := commitment.Parse(*f.commitment)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		assignment, err := c.PublicAssignment(*f.min, *f.max, ch, committed)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment, nil
+	case *circuit.PolicyRangeCircuit:
+		policy, err := commitment.Parse(*f.policy)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return c.PublicAssignment(ch, policy), nil
+	case *circuit.AnyRangeCircuit:
+		ranges, err := f.parseRanges()
+		if err != nil {
+			return nil, err
+		}
+		assignment, err := c.PublicAssignment(ranges, ch)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment, nil
+	case *circuit.MonthsCircuit:
+		assignment, err := c.PublicAssignment(*f.min, *f.max, ch)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment, nil
+	case *circuit.BracketCircuit:
+		if f.bracket == nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, errors.New("no bracket to check"))
+		}
+		brackets, err := f.parseBrackets()
+		if err != nil {
+			return nil, err
+		}
+		assignment, err := c.PublicAssignment(brackets, *f.bracket, ch)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment, nil
+	case *circuit.CredentialCircuit:
+		d, err := f.disclosure(c)
+		if err != nil {
+			return nil, err
+		}
+		assignment, err := c.PublicAssignment(d, ch)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment, nil
+	case *circuit.NonMembershipCircuit:
+		root, err := commitment.Parse(*f.blocklist)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return c.PublicAssignment(ch, root), nil
+	case *circuit.ExprCircuit:
+		values, err := parseVars(*f.vars)
+		if err != nil {
+			return nil, err
+		}
+		assignment, err := c.PublicAssignment(values, ch)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		return assignment, nil
+	}
+	return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("circuit %s does not take statement flags", definition.ID()))
+}
+
+func (f *statementFlags) parseChallenge() (*big.Int, error) {
+	if *f.challenge == "" {
+		return nil, nil
+	}
+	ch, err := challenge.Parse(*f.challenge)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	return ch, nil
+}
+
+// checkDomain rejects -domain for circuits without a Domain input.
+func (f *statementFlags) checkDomain(definition circuit.Definition) error {
+	if *f.domain != "" && !hasDomain(definition) {
+		return errNoDomain(definition)
+	}
+	return nil
+}
+
+// hasDomain reports whether a circuit has a Domain input.
+func hasDomain(definition circuit.Definition) bool {
+	switch definition.(type) {
+	case *circuit.RangeCircuit, *circuit.HashedRangeCircuit:
+		return true
+	}
+	return false
+}
+
+func errNoDomain(definition circuit.Definition) error {
+	return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("circuit %s does not take -domain", definition.ID()))
+}
+
+// checkHashedCurve rejects hashed-range on curves its digest has no native
+// implementation for.
+func checkHashedCurve() error {
+	if curve != ecc.BN254 {
+		return zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("%w: hashed-range digests are computed on %s only", commitment.ErrUnsupportedCurve, ecc.BN254))
+	}
+	return nil
+}
+
+// describe renders the public statement for progress messages.
+func (f *statementFlags) describe(definition circuit.Definition) string {
+	switch c := definition.(type) {
+	case *circuit.AnyRangeCircuit:
+		return fmt.Sprintf("Age ∈ %s", strings.ReplaceAll(*f.ranges, ",", " ∪ "))
+	case *circuit.BracketCircuit:
+		brackets, err := f.parseBrackets()
+		if err != nil {
+			break
+		}
+		if f.bracket != nil {
+			return fmt.Sprintf("Age ∈ %s", brackets.Label(*f.bracket))
+		}
+		return fmt.Sprintf("Age ∈ %s", brackets.Label(brackets.Index(*f.age)))
+	case *circuit.MonthsCircuit:
+		return fmt.Sprintf("%d ≤ Age ≤ %d in whole years", *f.min, *f.max)
+	case *circuit.PolicyRangeCircuit:
+		return "Min ≤ Age ≤ Max under the committed policy"
+	case *circuit.NonMembershipCircuit:
+		return "ID ∉ blocklist"
+	case *circuit.ExprCircuit:
+		return c.Statement().String()
+	case *circuit.CredentialCircuit:
+		if d, err := f.disclosure(c); err == nil {
+			return d.String()
+		}
+	case *circuit.ExpiringRangeCircuit:
+		if f.window != nil {
+			return fmt.Sprintf("%d ≤ Age ≤ %d, valid %s", *f.min, *f.max, f.window)
+		}
+		return fmt.Sprintf("%d ≤ Age ≤ %d, valid for %v", *f.min, *f.max, *f.ttl)
+	}
+	if *f.domain != "" {
+		return fmt.Sprintf("%d ≤ Age ≤ %d for %s", *f.min, *f.max, *f.domain)
+	}
+	return fmt.Sprintf("%d ≤ Age ≤ %d", *f.min, *f.max)
+}
+
+// parseRanges parses -ranges as a comma-separated list of min-max pairs.
+func (f *statementFlags) parseRanges() ([]circuit.Bounds, error) {
+	if *f.ranges == "" {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, errors.New("-ranges is required"))
+	}
+	return parseRanges(*f.ranges)
+}
+
+// parseRanges parses a comma-separated list of min-max pairs, such as
+// 0-17,65-150. Errors wrap zkp.ErrInvalidWitness.
+func parseRanges(s string) ([]circuit.Bounds, error) {
+	var ranges []circuit.Bounds
+	for _, field := range strings.Split(s, ",") {
+		lo, hi, ok := strings.Cut(strings.TrimSpace(field), "-")
+		if !ok {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("range %q: expected min-max", field))
+		}
+		min, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("range %q: %w", field, err))
+		}
+		max, err := strconv.Atoi(hi)
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("range %q: %w", field, err))
+		}
+		ranges = append(ranges, circuit.Bounds{Min: min, Max: max})
+	}
+	return ranges, nil
+}
+
+// parseBrackets parses -brackets, defaulting to circuit.DefaultBrackets.
+// Errors wrap zkp.ErrInvalidWitness.
+func (f *statementFlags) parseBrackets() (circuit.Brackets, error) {
+	if *f.brackets == "" {
+		return circuit.DefaultBrackets, nil
+	}
+	brackets, err := circuit.ParseBrackets(*f.brackets)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	return brackets, nil
+}
+
+// parseVars parses -vars as a comma-separated list of name=value pairs.
+func parseVars(s string) (map[string]int, error) {
+	values := map[string]int{}
+	if s == "" {
+		return values, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		name, v, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("input %q: expected name=value", field))
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("input %q: %w", field, err))
+		}
+		values[strings.TrimSpace(name)] = n
+	}
+	return values, nil
+}
+
+// disclosure returns the credential predicates selected by the flags: the
+// age range if -min or -max is set (a zero -max leaves it open), country
+// membership if -countries is set and the tier if -min-tier is.
+func (f *statementFlags) disclosure(c *circuit.CredentialCircuit) (circuit.Disclosure, error) {
+	var d circuit.Disclosure
+	if *f.min != 0 || *f.max != 0 {
+		d.Age = &circuit.Bounds{Min: *f.min, Max: *f.max}
+		if *f.max == 0 {
+			d.Age.Max = 1<<c.Bits() - 1
+		}
+	}
+	if *f.countries != "" {
+		for _, code := range strings.Split(*f.countries, ",") {
+			d.Countries = append(d.Countries, strings.ToUpper(strings.TrimSpace(code)))
+		}
+	}
+	if *f.minTier != "" {
+		tier, err := circuit.ParseTier(*f.minTier)
+		if err != nil {
+			return circuit.Disclosure{}, zkp.Wrap(zkp.ErrInvalidWitness, err)
+		}
+		d.MinTier = &tier
+	}
+	return d, nil
+}
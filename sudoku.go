@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ananthanir/hello-zkp/challenge"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/secret"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runSudoku proves knowledge of a solution to a public sudoku puzzle: the
+// verifier sees the clues, never the solution. Without -solution the puzzle
+// is solved off-circuit first, standing in for the prover's own work.
+func runSudoku(args []string) error {
+	fs := flag.NewFlagSet("sudoku", flag.ExitOnError)
+	puzzleFile := fs.String("puzzle", "testdata/sudoku/puzzle.txt", "puzzle file: 81 cells, 0 or . for blanks")
+	solutionFile := fs.String("solution", "", "solution file in the puzzle format, mode 0600 (empty: solve the puzzle)")
+	cache := fs.String("cache", defaultCacheDir(), "directory caching keys per circuit (empty: always run setup)")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	puzzle, err := readGrid(*puzzleFile)
+	if err != nil {
+		return err
+	}
+	var solution circuit.Grid
+	if *solutionFile != "" {
+		if err := secret.CheckFile(*solutionFile); err != nil {
+			return err
+		}
+		if solution, err = readGrid(*solutionFile); err != nil {
+			return err
+		}
+	} else if solution, err = puzzle.Solve(); err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	clues := 0
+	for _, v := range puzzle {
+		if v != 0 {
+			clues++
+		}
+	}
+	report.printf("Public puzzle (%d clues):\n%s", clues, puzzle)
+	report.println("Private:  the solution (never printed)")
+	report.set("clues", clues)
+
+	definition := circuit.NewSudokuCircuit()
+	assignment, err := definition.Assign(puzzle, solution)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	nonce, err := challenge.New()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	ccs, err := prover.Compile(curve, definition)
+	if err != nil {
+		return err
+	}
+	report.since("compile_ns", start)
+	report.printf("Circuit: %s on %s, %d constraints\n", definition.ID(), curve, ccs.GetNbConstraints())
+	report.set("circuit", definition.ID())
+	report.set("curve", curve.String())
+	report.set("constraints", ccs.GetNbConstraints())
+
+	start = time.Now()
+	pk, vk, err := setupKeys(ccs, *cache)
+	if err != nil {
+		return err
+	}
+	report.since("setup_ns", start)
+
+	full, _, err := prover.NewWitness(curve, assignment.WithChallenge(nonce))
+	if err != nil {
+		return err
+	}
+	start = time.Now()
+	proof, err := prover.Prove(ccs, pk, full)
+	if err != nil {
+		report.println("Prove: ❌ FAILED (solution does not satisfy constraints)")
+		return err
+	}
+	report.since("prove_ns", start)
+
+	// The verifier rebuilds the public inputs from the puzzle it published.
+	expected, err := definition.PublicAssignment(puzzle, nonce)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	public, err := prover.NewPublicWitness(curve, expected)
+	if err != nil {
+		return err
+	}
+	start = time.Now()
+	err = zkp.Verify(proof, vk, public)
+	report.since("verify_ns", start)
+	report.set("verified", err == nil)
+	if err != nil {
+		report.println("Verification: ❌ FAILED")
+		return err
+	}
+	report.println("Verification: ✅ SUCCESS (a solution of the puzzle is known, proven zero-knowledge)")
+	return nil
+}
+
+// readGrid reads and parses a puzzle or solution file.
+func readGrid(path string) (circuit.Grid, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return circuit.Grid{}, zkp.Wrap(zkp.ErrIO, err)
+	}
+	g, err := circuit.ParseGrid(string(data))
+	if err != nil {
+		return circuit.Grid{}, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("%s: %w", path, err))
+	}
+	return g, nil
+}
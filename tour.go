@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/ananthanir/hello-zkp/challenge"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// lesson is one circuit the tour can walk through.
+type lesson struct {
+	name    string // circuit name, as registered
+	title   string // menu entry
+	explain string // printed before the walkthrough
+
+	// statement asks for the inputs and returns the witness to prove.
+	statement func(p *prompter, definition circuit.Definition) (tourStatement, error)
+}
+
+// tourStatement is a lesson's witness, as the tour shows it.
+type tourStatement struct {
+	assignment frontend.Circuit
+	private    []string // what stays with the prover
+	public     []string // what the verifier sees, besides the challenge
+	claim      string
+
+	// replay returns the same public statement under another challenge.
+	replay func(ch *big.Int) frontend.Circuit
+}
+
+// lessons are the tour menu, in order.
+var lessons = []lesson{
+	{
+		name:  "range",
+		title: "Min ≤ Age ≤ Max (the classic age check)",
+		explain: `The prover knows an Age and convinces the verifier that it lies
+between two public bounds, without saying what it is.`,
+		statement: func(p *prompter, definition circuit.Definition) (tourStatement, error) {
+			c := definition.(*circuit.RangeCircuit)
+			age, err := p.int("Age (private)", 25)
+			if err != nil {
+				return tourStatement{}, err
+			}
+			min, err := p.int("Min bound (public)", 18)
+			if err != nil {
+				return tourStatement{}, err
+			}
+			max, err := p.int("Max bound (public)", 30)
+			if err != nil {
+				return tourStatement{}, err
+			}
+			assignment, err := c.Assign(age, min, max)
+			if err != nil {
+				return tourStatement{}, err
+			}
+			return tourStatement{
+				assignment: assignment,
+				private:    []string{fmt.Sprintf("Age = %d", age)},
+				public:     []string{fmt.Sprintf("Min = %d", min), fmt.Sprintf("Max = %d", max)},
+				claim:      fmt.Sprintf("%d ≤ Age ≤ %d", min, max),
+				replay: func(ch *big.Int) frontend.Circuit {
+					public, _ := c.PublicAssignment(min, max, ch)
+					return public
+				},
+			}, nil
+		},
+	},
+	{
+		name:  "any-range",
+		title: "Age in one of several ranges (youth OR senior discount)",
+		explain: `The prover shows that Age lies in at least one of several public
+ranges. A private selector picks the range, so the verifier does not
+learn which one matched.`,
+		statement: func(p *prompter, definition circuit.Definition) (tourStatement, error) {
+			c := definition.(*circuit.AnyRangeCircuit)
+			age, err := p.int("Age (private)", 70)
+			if err != nil {
+				return tourStatement{}, err
+			}
+			line, err := p.line(fmt.Sprintf("Ranges, up to %d (public)", c.Slots()), "0-17,65-150")
+			if err != nil {
+				return tourStatement{}, err
+			}
+			ranges, err := parseRanges(line)
+			if err != nil {
+				return tourStatement{}, err
+			}
+			assignment, err := c.Assign(age, ranges)
+			if err != nil {
+				return tourStatement{}, err
+			}
+			var names []string
+			for _, r := range ranges {
+				names = append(names, r.String())
+			}
+			return tourStatement{
+				assignment: assignment,
+				private:    []string{fmt.Sprintf("Age = %d", age), "which range matched"},
+				public:     []string{"Ranges = " + strings.Join(names, ", ")},
+				claim:      "Age ∈ " + strings.Join(names, " ∪ "),
+				replay: func(ch *big.Int) frontend.Circuit {
+					public, _ := c.PublicAssignment(ranges, ch)
+					return public
+				},
+			}, nil
+		},
+	},
+	{
+		name:  "credential",
+		title: "Several attributes at once (age, country, tier)",
+		explain: `The prover holds a credential with an age, a country and a tier,
+and chooses which predicates to disclose. Anything not disclosed is
+not constrained by the proof at all.`,
+		statement: func(p *prompter, definition circuit.Definition) (tourStatement, error) {
+			c := definition.(*circuit.CredentialCircuit)
+			var cred circuit.Credential
+			var err error
+			if cred.Age, err = p.int("Age (private)", 34); err != nil {
+				return tourStatement{}, err
+			}
+			if cred.Country, err = p.line("Country code (private)", "FR"); err != nil {
+				return tourStatement{}, err
+			}
+			cred.Country = strings.ToUpper(cred.Country)
+			tier, err := p.line("Tier: basic, silver, gold or platinum (private)", "gold")
+			if err != nil {
+				return tourStatement{}, err
+			}
+			if cred.Tier, err = circuit.ParseTier(tier); err != nil {
+				return tourStatement{}, err
+			}
+
+			var d circuit.Disclosure
+			if min, err := p.int("Disclose Age ≥ (0: do not disclose)", 18); err != nil {
+				return tourStatement{}, err
+			} else if min > 0 {
+				d.Age = &circuit.Bounds{Min: min, Max: 1<<c.Bits() - 1}
+			}
+			countries, err := p.line(fmt.Sprintf("Disclose Country ∈, up to %d codes (empty: do not disclose)", c.Slots()), "FR,DE,IT")
+			if err != nil {
+				return tourStatement{}, err
+			}
+			for _, code := range strings.Split(countries, ",") {
+				if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+					d.Countries = append(d.Countries, code)
+				}
+			}
+			minTier, err := p.line("Disclose Tier ≥ (empty: do not disclose)", "silver")
+			if err != nil {
+				return tourStatement{}, err
+			}
+			if minTier != "" {
+				t, err := circuit.ParseTier(minTier)
+				if err != nil {
+					return tourStatement{}, err
+				}
+				d.MinTier = &t
+			}
+
+			assignment, err := c.Assign(cred, d)
+			if err != nil {
+				return tourStatement{}, err
+			}
+			return tourStatement{
+				assignment: assignment,
+				private:    []string{fmt.Sprintf("Age = %d", cred.Age), "Country = " + cred.Country, "Tier = " + cred.Tier.String()},
+				public:     []string{"Disclosed predicates: " + d.String()},
+				claim:      d.String(),
+				replay: func(ch *big.Int) frontend.Circuit {
+					public, _ := c.PublicAssignment(d, ch)
+					return public
+				},
+			}, nil
+		},
+	},
+}
+
+// runTour runs the menu-driven walkthrough of demo -tour until the user
+// quits or stdin ends.
+func runTour(bits int, cache string) error {
+	p := newPrompter(os.Stdin)
+	report.println("=== hello-zkp tour ===")
+	report.println("Each lesson compiles a circuit, runs setup, builds a witness, proves and")
+	report.println("verifies, stopping after each step to show what happened and who sees what.")
+	for {
+		report.println("\nLessons:")
+		for i, l := range lessons {
+			report.printf("  %d) %s\n", i+1, l.title)
+		}
+		report.println("  q) quit")
+		choice, err := p.line("Choose a lesson", "1")
+		if errors.Is(err, io.EOF) || choice == "q" {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(choice)
+		if err != nil || n < 1 || n > len(lessons) {
+			report.printf("No lesson %q.\n", choice)
+			continue
+		}
+		if err := walkthrough(p, lessons[n-1], bits, cache); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			// A failed lesson is part of the lesson; go back to the menu.
+			report.printf("\n❌ %v\n", err)
+		}
+	}
+}
+
+// walkthrough runs one lesson step by step.
+func walkthrough(p *prompter, l lesson, bits int, cache string) error {
+	definition, err := circuit.New(l.name, bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	report.printf("\n=== %s ===\n%s\n", definition.ID(), l.explain)
+
+	// 1) Compile: the statement becomes a system of quadratic constraints
+	report.println("\n--- Step 1/5: compile ---")
+	start := time.Now()
+	ccs, err := prover.Compile(curve, definition)
+	if err != nil {
+		return err
+	}
+	report.printf("Compiled to %d R1CS constraints on %s in %v.\n", ccs.GetNbConstraints(), curve, time.Since(start).Round(time.Millisecond))
+	report.printf("Variables: %d public (plus the constant 1), %d secret, %d internal.\n",
+		ccs.GetNbPublicVariables()-1, ccs.GetNbSecretVariables(), ccs.GetNbInternalVariables())
+	report.println("The circuit is public: prover and verifier agree on it, not on the inputs.")
+	if err := p.pause(); err != nil {
+		return err
+	}
+
+	// 2) Setup: keys derived from the circuit alone
+	report.println("\n--- Step 2/5: trusted setup (Groth16) ---")
+	start = time.Now()
+	pk, vk, err := setupKeys(ccs, cache)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+	pkSize, err := serializedSize(pk)
+	if err != nil {
+		return err
+	}
+	vkSize, err := serializedSize(vk)
+	if err != nil {
+		return err
+	}
+	report.printf("Setup took %v (keys are cached per circuit).\n", elapsed.Round(time.Millisecond))
+	report.printf("Proving key:   %d bytes, grows with the constraint count; the prover keeps it.\n", pkSize)
+	report.printf("Verifying key: %d bytes, grows only with the public inputs; the verifier keeps it.\n", vkSize)
+	report.println("Whoever knows the setup randomness could forge proofs, so it must be destroyed")
+	report.println("(see the ceremony command for doing this with several parties).")
+	if err := p.pause(); err != nil {
+		return err
+	}
+
+	// 3) Witness: the prover's private and public values
+	report.println("\n--- Step 3/5: witness ---")
+	st, err := l.statement(p, definition)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	nonce, err := challenge.New()
+	if err != nil {
+		return err
+	}
+	assignment := withChallenge(st.assignment, nonce)
+	witness, publicWitness, err := prover.NewWitness(curve, assignment)
+	if err != nil {
+		return err
+	}
+	report.println("Stays with the prover:")
+	for _, s := range st.private {
+		report.printf("  🔒 %s\n", s)
+	}
+	report.println("Sent to the verifier:")
+	for _, s := range st.public {
+		report.printf("  👁  %s\n", s)
+	}
+	report.printf("  👁  Challenge = %s (fresh from the verifier, stops replays)\n", challenge.Format(nonce))
+	report.printf("Claim: %s\n", st.claim)
+	if err := p.pause(); err != nil {
+		return err
+	}
+
+	// 4) Prove
+	report.println("\n--- Step 4/5: prove ---")
+	start = time.Now()
+	proof, err := prover.Prove(ccs, pk, witness)
+	if err != nil {
+		report.println("The witness does not satisfy the constraints, so no proof exists:")
+		report.println("a zero-knowledge proof cannot make a false claim true.")
+		return err
+	}
+	elapsed = time.Since(start)
+	proofSize, err := serializedSize(proof)
+	if err != nil {
+		return err
+	}
+	report.printf("Proved in %v. The proof is %d bytes: three curve points, the same size\n", elapsed.Round(time.Millisecond), proofSize)
+	report.println("whatever the inputs, and they cannot be recovered from it.")
+	if err := p.pause(); err != nil {
+		return err
+	}
+
+	// 5) Verify, then show the proof is bound to its challenge
+	report.println("\n--- Step 5/5: verify ---")
+	start = time.Now()
+	err = zkp.Verify(proof, vk, publicWitness)
+	if err != nil {
+		return err
+	}
+	report.printf("Verification: ✅ SUCCESS in %v.\n", time.Since(start).Round(time.Microsecond))
+	report.printf("The verifier now believes %s, and learned nothing else.\n", st.claim)
+
+	other, err := challenge.New()
+	if err != nil {
+		return err
+	}
+	replayed, err := prover.NewPublicWitness(curve, st.replay(other))
+	if err != nil {
+		return err
+	}
+	if err := zkp.Verify(proof, vk, replayed); err != nil {
+		report.println("Replaying the same proof to a verifier with another challenge: ❌ rejected.")
+	} else {
+		report.println("Replaying the same proof to a verifier with another challenge: accepted (unexpected!).")
+	}
+	return p.pause()
+}
+
+// withChallenge binds ch into a lesson's assignment.
+func withChallenge(assignment frontend.Circuit, ch *big.Int) frontend.Circuit {
+	switch c := assignment.(type) {
+	case *circuit.RangeCircuit:
+		return c.WithChallenge(ch)
+	case *circuit.AnyRangeCircuit:
+		return c.WithChallenge(ch)
+	case *circuit.CredentialCircuit:
+		return c.WithChallenge(ch)
+	}
+	return assignment
+}
+
+// prompter asks questions on stdin, one answer per line, prompting only when
+// stdin is a terminal so the tour can also be scripted.
+type prompter struct {
+	r      *bufio.Reader
+	prompt bool
+}
+
+func newPrompter(f *os.File) *prompter {
+	src := newStdinSource(f)
+	return &prompter{r: src.r, prompt: src.prompt}
+}
+
+// line asks a question and returns the trimmed answer, or def if the answer
+// is empty.
+func (p *prompter) line(question, def string) (string, error) {
+	if p.prompt {
+		if def != "" {
+			report.printf("%s [%s]: ", question, def)
+		} else {
+			report.printf("%s: ", question)
+		}
+	}
+	s, err := p.r.ReadString('\n')
+	if err != nil && (s == "" || !errors.Is(err, io.EOF)) {
+		return "", err
+	}
+	if s = strings.TrimSpace(s); s == "" {
+		return def, nil
+	}
+	return s, nil
+}
+
+// int asks for an integer, repeating the question until it gets one.
+func (p *prompter) int(question string, def int) (int, error) {
+	for {
+		s, err := p.line(question, strconv.Itoa(def))
+		if err != nil {
+			return 0, err
+		}
+		v, err := strconv.Atoi(s)
+		if err == nil {
+			return v, nil
+		}
+		report.printf("%q is not a number.\n", s)
+	}
+}
+
+// pause waits for Enter on a terminal.
+func (p *prompter) pause() error {
+	if !p.prompt {
+		return nil
+	}
+	report.printf("[Enter to continue] ")
+	_, err := p.r.ReadString('\n')
+	return err
+}
@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Batching of queued spans: a batch is sent when it is full, and at least
+// every flushInterval.
+const (
+	batchSize     = 256
+	flushInterval = 5 * time.Second
+)
+
+// otlpExporter queues ended spans and posts them in batches as an OTLP/HTTP
+// JSON ExportTraceServiceRequest.
+type otlpExporter struct {
+	url     string
+	service string
+	client  *http.Client
+
+	mu      sync.Mutex
+	queue   []otlpSpan
+	done    chan struct{}
+	stopped sync.Once
+}
+
+func newOTLPExporter(endpoint, service string) (*otlpExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q (want http(s)://host:port)", endpoint)
+	}
+	e := &otlpExporter{
+		url:     strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		service: service,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		done:    make(chan struct{}),
+	}
+	go e.loop()
+	return e, nil
+}
+
+// loop flushes the queue periodically until stop.
+func (e *otlpExporter) loop() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.flush(context.Background())
+		case <-e.done:
+			return
+		}
+	}
+}
+
+func (e *otlpExporter) stop() {
+	e.stopped.Do(func() { close(e.done) })
+}
+
+func (e *otlpExporter) add(s *Span, end time.Time, err error) {
+	span := otlpSpan{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		Name:              s.name,
+		Kind:              1, // SPAN_KIND_INTERNAL
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:        attributes(s.attrs),
+	}
+	if s.parentID != [8]byte{} {
+		span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+	if err != nil {
+		span.Status = &otlpStatus{Code: 2, Message: err.Error()} // STATUS_CODE_ERROR
+	}
+	e.mu.Lock()
+	e.queue = append(e.queue, span)
+	full := len(e.queue) >= batchSize
+	e.mu.Unlock()
+	if full {
+		go e.flush(context.Background())
+	}
+}
+
+// flush posts the queued spans. Spans of a failed post are dropped: tracing
+// must not hold up or fail the operations it observes.
+func (e *otlpExporter) flush(ctx context.Context) error {
+	e.mu.Lock()
+	spans := e.queue
+	e.queue = nil
+	e.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: attributes([]any{"service.name", e.service})},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: scope},
+			Spans: spans,
+		}},
+	}}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("exporting %d spans: %w", len(spans), err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("exporting %d spans: %s: %s", len(spans), e.url, resp.Status)
+	}
+	return nil
+}
+
+// attributes converts key, value pairs to OTLP attributes. A trailing key
+// without a value is dropped.
+func attributes(kv []any) []otlpKeyValue {
+	var out []otlpKeyValue
+	for i := 0; i+1 < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+		var v otlpValue
+		switch x := kv[i+1].(type) {
+		case string:
+			v.StringValue = &x
+		case bool:
+			v.BoolValue = &x
+		case int:
+			s := strconv.Itoa(x)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(x, 10)
+			v.IntValue = &s
+		case uint64:
+			s := strconv.FormatUint(x, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &x
+		case time.Duration:
+			s := strconv.FormatInt(x.Milliseconds(), 10)
+			v.IntValue = &s
+		default:
+			s := fmt.Sprint(x)
+			v.StringValue = &s
+		}
+		out = append(out, otlpKeyValue{Key: key, Value: v})
+	}
+	return out
+}
+
+// The OTLP/HTTP JSON encoding of an ExportTraceServiceRequest, limited to
+// the fields written here. IDs are hex and 64-bit integers decimal strings,
+// as the protobuf JSON mapping of OTLP requires.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            *otlpStatus    `json:"status,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
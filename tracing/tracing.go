@@ -0,0 +1,170 @@
+// Package tracing records a span around each proving step (compile, setup,
+// witness, prove, verify) and exports them to an OpenTelemetry collector,
+// so the latency breakdown of a prover service shows up next to the rest of
+// the traces of a deployment. Spans are no-ops unless an endpoint is set.
+//
+// Spans are exported with OTLP over HTTP, JSON encoded, to
+// <endpoint>/v1/traces: the transport every OpenTelemetry collector accepts
+// and the one that needs no dependency beyond net/http.
+//
+//	hello-zkp prove -otlp-endpoint http://localhost:4318 ...
+//	OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4318 go run ./cmd/prover ...
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"os"
+	"sync"
+	"time"
+)
+
+// Environment variables of the OpenTelemetry SDKs honoured here.
+const (
+	EndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	ServiceEnv  = "OTEL_SERVICE_NAME"
+)
+
+// scope names the instrumentation in exported spans.
+const scope = "github.com/ananthanir/hello-zkp"
+
+var (
+	mu       sync.RWMutex
+	exporter *otlpExporter
+	root     context.Context
+)
+
+// Span is one timed operation. A nil *Span, as returned when tracing is
+// off, does nothing.
+type Span struct {
+	exporter *otlpExporter
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	start    time.Time
+	attrs    []any
+}
+
+type spanKey struct{}
+
+// Start starts a span named name as a child of the span in ctx, or of the
+// root span set by StartRoot if ctx has none. kv are attributes given as
+// key, value pairs. The returned context carries the new span.
+func Start(ctx context.Context, name string, kv ...any) (context.Context, *Span) {
+	mu.RLock()
+	e, r := exporter, root
+	mu.RUnlock()
+	if e == nil {
+		return ctx, nil
+	}
+	parent, _ := ctx.Value(spanKey{}).(*Span)
+	if parent == nil && r != nil {
+		parent, _ = r.Value(spanKey{}).(*Span)
+	}
+	s := &Span{exporter: e, name: name, start: time.Now(), attrs: kv}
+	rand.Read(s.spanID[:])
+	if parent != nil {
+		s.traceID, s.parentID = parent.traceID, parent.spanID
+	} else {
+		rand.Read(s.traceID[:])
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// StartRoot starts a span that every span started without a parent in its
+// context becomes a child of, such as the span of a CLI command, and returns
+// the function ending it.
+func StartRoot(name string, kv ...any) func(err error) {
+	ctx, s := Start(context.Background(), name, kv...)
+	mu.Lock()
+	root = ctx
+	mu.Unlock()
+	return func(err error) {
+		mu.Lock()
+		root = nil
+		mu.Unlock()
+		s.End(err)
+	}
+}
+
+// SetAttributes adds attributes given as key, value pairs.
+func (s *Span) SetAttributes(kv ...any) {
+	if s != nil {
+		s.attrs = append(s.attrs, kv...)
+	}
+}
+
+// End ends the span, with an error status if err is not nil, and queues it
+// for export.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.exporter.add(s, time.Now(), err)
+}
+
+// TraceID returns the hex trace ID, or "" for a nil span.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// Setup exports spans to the collector at endpoint, under the service name
+// service unless ServiceEnv overrides it. An empty endpoint turns tracing
+// off.
+func Setup(endpoint, service string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if exporter != nil {
+		exporter.stop()
+		exporter = nil
+	}
+	if endpoint == "" {
+		return nil
+	}
+	if s := os.Getenv(ServiceEnv); s != "" {
+		service = s
+	}
+	e, err := newOTLPExporter(endpoint, service)
+	if err != nil {
+		return err
+	}
+	exporter = e
+	return nil
+}
+
+// Shutdown exports the spans still queued, waiting until ctx is done at
+// most, and turns tracing off.
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	e := exporter
+	exporter, root = nil, nil
+	mu.Unlock()
+	if e == nil {
+		return nil
+	}
+	e.stop()
+	return e.flush(ctx)
+}
+
+// Flags are the tracing flags of a command.
+type Flags struct {
+	Endpoint *string
+}
+
+// AddFlags registers -otlp-endpoint on fs, defaulting to EndpointEnv.
+func AddFlags(fs *flag.FlagSet) *Flags {
+	return &Flags{
+		Endpoint: fs.String("otlp-endpoint", os.Getenv(EndpointEnv), "OpenTelemetry collector to send spans to, e.g. http://localhost:4318 (empty: no tracing)"),
+	}
+}
+
+// Setup applies the parsed flags.
+func (f *Flags) Setup(service string) error {
+	return Setup(*f.Endpoint, service)
+}
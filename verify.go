@@ -0,0 +1,260 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/audit"
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/domain"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/prover"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runVerify checks a proof envelope against a verifying key. The envelope is
+// rejected before any pairing work if it was made for another circuit, curve
+// or key.
+//
+// With -min and -max, -ranges for any-range, -brackets for age-bracket,
+// -policy for policy-range, -blocklist for non-membership or -vars for an
+// expr: statement (plus -challenge and -commitment where they apply), or
+// with any of -min, -max, -countries and -min-tier for a credential, the
+// verifier pins the statement it expects instead of trusting the public
+// inputs carried by the envelope. -domain is checked either way.
+//
+// -public pins the statement from a file of named public inputs instead
+// (see circuit.PublicInputs). With -vk-fingerprint the verifying key itself
+// is pinned.
+//
+// An expiring-range proof also has to be within its validity window by the
+// verifier's clock, give or take -clock-skew, and no longer than -max-ttl.
+// An age-bracket proof outputs
+// the bracket, which is read from the envelope and reported.
+//
+// With -audit-log the outcome is recorded, failures included.
+func runVerify(args []string) (err error) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	keys := fs.String("keys", "keys", "directory holding vk.bin")
+	in := fs.String("proof", "proof.json", "proof envelope to verify")
+	name := fs.String("circuit", "range", "circuit the proof is for")
+	statement := addStatementFlags(fs, false)
+	publicFile := fs.String("public", "", "JSON file of named public inputs to pin the statement to, instead of the statement flags")
+	fingerprint := fs.String("vk-fingerprint", "", "SHA-256 fingerprint vk.bin must have, as printed by 'vk fingerprint' (empty: any key)")
+	skew := fs.Duration("clock-skew", time.Minute, "clock difference tolerated when checking a validity window (expiring-range)")
+	maxTTL := fs.Duration("max-ttl", 7*24*time.Hour, "longest validity window accepted (expiring-range; 0: any)")
+	auditLog := addAuditFlag(fs)
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	log, err := openAudit(*auditLog)
+	if err != nil {
+		return err
+	}
+	var (
+		definition circuit.Definition
+		vk         groth16.VerifyingKey
+		env        *envelope.Envelope
+		pinnedAll  bool
+	)
+	defer func() {
+		var public witness.Witness
+		if env != nil && vk != nil {
+			_, public, _ = env.Open(definition.ID(), vk)
+		}
+		err = recordAudit(log, audit.Verified, definition, env, public, pinnedAll, err)
+	}()
+
+	definition, err = circuit.New(*name, *bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+
+	pinned := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { pinned[f.Name] = true })
+	expectStatement := pinned["min"] || pinned["max"] || pinned["ranges"] || pinned["brackets"] || pinned["policy"] || pinned["blocklist"] || pinned["vars"] || pinned["challenge"]
+	if _, ok := definition.(*circuit.CredentialCircuit); ok {
+		// Every credential predicate is optional: whatever is given is the
+		// disclosure the verifier expects.
+		expectStatement = expectStatement || pinned["countries"] || pinned["min-tier"]
+	} else if expectStatement && !(pinned["min"] && pinned["max"]) && !pinned["ranges"] && !pinned["brackets"] && !pinned["policy"] && !pinned["blocklist"] && !pinned["vars"] {
+		return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("-min and -max (or -ranges, -brackets, -policy, -blocklist or -vars) are required to pin the statement"))
+	}
+	if *publicFile != "" && (expectStatement || pinned["domain"]) {
+		return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("-public pins the whole statement: it excludes the statement flags"))
+	}
+	// Even an empty -domain is a statement the proof cannot be checked
+	// against when the circuit has no Domain input.
+	if pinned["domain"] && !hasDomain(definition) {
+		return errNoDomain(definition)
+	}
+	pinnedAll = expectStatement || *publicFile != ""
+	report.set("circuit", definition.ID())
+	vk, err = readVerifyingKey(*keys)
+	if err != nil {
+		return err
+	}
+	// A pinned fingerprint catches a key swapped along with the proofs it
+	// would accept, which the envelope's own vk_hash cannot.
+	if *fingerprint != "" {
+		err := checkFingerprint(vk, *fingerprint)
+		if errors.Is(err, zkp.ErrVerificationFailed) {
+			return reportVerification(err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	env, err = readEnvelope(*in)
+	if err != nil {
+		return reportVerification(err)
+	}
+
+	// The window travels in the envelope: the verifier checks it against its
+	// clock once the proof has verified.
+	expiring, _ := definition.(*circuit.ExpiringRangeCircuit)
+	if expiring != nil {
+		_, public, err := env.Open(definition.ID(), vk)
+		if err != nil {
+			return reportVerification(zkp.Wrap(zkp.ErrVerificationFailed, err))
+		}
+		window, err := expiring.ReadWindow(public)
+		if err != nil {
+			return reportVerification(zkp.Wrap(zkp.ErrVerificationFailed, err))
+		}
+		statement.window = &window
+		report.set("window", window)
+	}
+	// So does the bracket, the one public input the prover chooses: a pinned
+	// statement fixes the edges, and the circuit that the bracket is Age's.
+	var bracketLine string
+	if b, ok := definition.(*circuit.BracketCircuit); ok {
+		_, public, err := env.Open(definition.ID(), vk)
+		if err != nil {
+			return reportVerification(zkp.Wrap(zkp.ErrVerificationFailed, err))
+		}
+		brackets, bracket, err := b.ReadBracket(public)
+		if err != nil {
+			return reportVerification(zkp.Wrap(zkp.ErrVerificationFailed, err))
+		}
+		statement.bracket = &bracket
+		report.set("bracket", bracket)
+		report.set("bracket_label", brackets.Label(bracket))
+		bracketLine = fmt.Sprintf("Bracket: Age ∈ %s (bracket %d of %s)\n", brackets.Label(bracket), bracket, brackets)
+	}
+	checkWindow := func(err error) error {
+		if err == nil && statement.window != nil {
+			if err = statement.window.Check(time.Now(), *skew); err == nil && *maxTTL > 0 {
+				err = statement.window.CheckTTL(*maxTTL)
+			}
+			if err != nil {
+				report.printf("Validity window: ❌ %v\n", err)
+				err = zkp.Wrap(zkp.ErrVerificationFailed, err)
+			}
+		}
+		if err == nil && bracketLine != "" {
+			report.printf("%s", bracketLine)
+		}
+		return reportVerification(err)
+	}
+
+	// Even when the envelope's statement is trusted, the domain is the
+	// verifier's own: a proof made for another relying party is rejected
+	// before any pairing work.
+	if pinned["domain"] && !expectStatement {
+		var c *circuit.RangeCircuit
+		switch d := definition.(type) {
+		case *circuit.RangeCircuit:
+			c = d
+		case *circuit.HashedRangeCircuit:
+			return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("hashed-range hides the domain in its digest: pin -min and -max to check it"))
+		default:
+			return errNoDomain(definition)
+		}
+		_, public, err := env.Open(definition.ID(), vk)
+		if err != nil {
+			return reportVerification(zkp.Wrap(zkp.ErrVerificationFailed, err))
+		}
+		got, err := c.ReadDomain(public)
+		if err == nil {
+			err = domain.Check(got, *statement.domain)
+		}
+		if err != nil {
+			return reportVerification(zkp.Wrap(zkp.ErrVerificationFailed, err))
+		}
+	}
+
+	// Named inputs are put in witness order by the circuit's schema, so
+	// the verifier need not know it.
+	if *publicFile != "" {
+		report.set("pinned", true)
+		inputs, err := readPublicInputs(*publicFile)
+		if err != nil {
+			return err
+		}
+		publicWitness, err := prover.BuildPublicWitness(curve, definition, inputs)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		err = env.VerifyStatement(definition.ID(), vk, publicWitness)
+		report.since("verify_ns", start)
+		return checkWindow(err)
+	}
+
+	report.set("pinned", expectStatement)
+	if !expectStatement {
+		// Show what was proven, by name, since nothing was pinned.
+		if _, public, err := env.Open(definition.ID(), vk); err == nil {
+			if inputs, err := circuit.ReadPublicInputs(definition, public); err == nil {
+				report.set("public_inputs", inputs)
+			}
+		}
+		start := time.Now()
+		err := env.Verify(definition.ID(), vk)
+		report.since("verify_ns", start)
+		return checkWindow(err)
+	}
+	expected, err := statement.publicAssignment(definition)
+	if err != nil {
+		return err
+	}
+	publicWitness, err := prover.NewPublicWitness(curve, expected)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = env.VerifyStatement(definition.ID(), vk, publicWitness)
+	report.since("verify_ns", start)
+	return checkWindow(err)
+}
+
+func reportVerification(err error) error {
+	report.set("verified", err == nil)
+	if err != nil {
+		report.println("Verification: ❌ FAILED")
+		return err
+	}
+	report.println("Verification: ✅ SUCCESS (Min ≤ Age ≤ Max proven zero-knowledge)")
+	return nil
+}
+
+// readPublicInputs reads a JSON file of named public inputs.
+func readPublicInputs(path string) (circuit.PublicInputs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return circuit.PublicInputs{}, zkp.Wrap(zkp.ErrIO, err)
+	}
+	inputs, err := circuit.ParsePublicInputs(data)
+	if err != nil {
+		return circuit.PublicInputs{}, zkp.Wrap(zkp.ErrInvalidWitness, fmt.Errorf("%s: %w", path, err))
+	}
+	return inputs, nil
+}
@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// EnvelopeVersion is the envelope format version this package reads.
+const EnvelopeVersion = 1
+
+var (
+	// ErrMismatch is returned for an envelope made for another version,
+	// curve, circuit or verifying key.
+	ErrMismatch = errors.New("envelope does not match the verifier")
+
+	// ErrStatement is returned when the public inputs of an envelope are not
+	// the ones the verifier expects.
+	ErrStatement = errors.New("public inputs do not match the expected statement")
+)
+
+// Envelope is the JSON proof envelope of the envelope package, read without
+// its gnark types.
+type Envelope struct {
+	Version      int    `json:"version"`
+	Curve        string `json:"curve"`
+	Circuit      string `json:"circuit"`
+	VKHash       string `json:"vk_hash"`
+	Proof        []byte `json:"proof"`
+	PublicInputs []byte `json:"public_inputs"`
+}
+
+// ReadEnvelope decodes a JSON envelope.
+func ReadEnvelope(r io.Reader) (*Envelope, error) {
+	var e Envelope
+	if err := json.NewDecoder(r).Decode(&e); err != nil {
+		return nil, fmt.Errorf("%w: envelope: %v", ErrMalformed, err)
+	}
+	return &e, nil
+}
+
+// Open checks the envelope metadata against the circuit and key the
+// verifier expects and decodes the proof and public inputs. It does not
+// verify the proof.
+func (e *Envelope) Open(circuitID string, vk *VerifyingKey) (*Proof, []fr.Element, error) {
+	switch {
+	case e.Version != EnvelopeVersion:
+		return nil, nil, fmt.Errorf("%w: version %d (expected %d)", ErrMismatch, e.Version, EnvelopeVersion)
+	case e.Curve != Curve.String():
+		return nil, nil, fmt.Errorf("%w: envelope is for %s, verifying key is for %s", ErrMismatch, e.Curve, Curve)
+	case e.Circuit != circuitID:
+		return nil, nil, fmt.Errorf("%w: envelope is for %q, expected %q", ErrMismatch, e.Circuit, circuitID)
+	case e.VKHash != vk.Hash():
+		return nil, nil, fmt.Errorf("%w: envelope expects key %s, got %s", ErrMismatch, e.VKHash, vk.Hash())
+	}
+	proof, err := ParseProof(e.Proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	public, err := ParsePublicInputs(e.PublicInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, public, nil
+}
+
+// Verify opens the envelope and checks the proof it carries against its own
+// public inputs.
+func (e *Envelope) Verify(circuitID string, vk *VerifyingKey) error {
+	proof, public, err := e.Open(circuitID, vk)
+	if err != nil {
+		return err
+	}
+	return vk.Verify(proof, public)
+}
+
+// VerifyStatement is Verify for a verifier that pins the public inputs: the
+// envelope's must equal expected exactly.
+func (e *Envelope) VerifyStatement(circuitID string, vk *VerifyingKey, expected []fr.Element) error {
+	proof, public, err := e.Open(circuitID, vk)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(public, expected) {
+		return ErrStatement
+	}
+	return vk.Verify(proof, expected)
+}
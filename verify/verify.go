@@ -0,0 +1,187 @@
+// Package verify checks BN254 Groth16 proofs with gnark-crypto alone.
+//
+// zkp and envelope verify through gnark's groth16 backend, which also links
+// in the constraint systems, the solver and the prover of every curve. This
+// package reads the same verifying keys, proofs, public inputs and envelopes
+// and runs the pairing check itself, so an embedded verifier stays small and
+// starts fast. It covers what the hello-zkp circuits produce: proofs without
+// Pedersen commitments, on BN254.
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Curve is the only curve this package verifies on.
+const Curve = ecc.BN254
+
+var (
+	// ErrMalformed is returned for encodings that cannot be decoded.
+	ErrMalformed = errors.New("malformed input")
+
+	// ErrUnsupported is returned for keys and proofs this package cannot
+	// check, i.e. those with Pedersen commitments.
+	ErrUnsupported = errors.New("not supported by the lightweight verifier")
+
+	// ErrVerificationFailed is returned when the pairing check fails, or the
+	// proof does not match its verifying key.
+	ErrVerificationFailed = errors.New("verification failed")
+)
+
+// VerifyingKey is a Groth16 verifying key, prepared for verification.
+type VerifyingKey struct {
+	alpha    bn254.G1Affine
+	k        []bn254.G1Affine
+	negGamma bn254.G2Affine
+	negDelta bn254.G2Affine
+	e        bn254.GT // e(α, β)
+	nbPublic int      // public inputs, without the constant wire
+	hash     string   // hex SHA-256 of the compressed encoding
+}
+
+// ReadVerifyingKey decodes a verifying key as written by gnark's
+// VerifyingKey.WriteTo or WriteRawTo, the formats of keys/vk.bin.
+func ReadVerifyingKey(r io.Reader) (*VerifyingKey, error) {
+	var (
+		alpha, betaG1, deltaG1 bn254.G1Affine
+		beta, gamma, delta     bn254.G2Affine
+		k                      []bn254.G1Affine
+		publicCommitted        [][]uint64
+		nbCommitments          uint32
+	)
+	dec := bn254.NewDecoder(r)
+	for i, v := range []any{&alpha, &betaG1, &beta, &gamma, &deltaG1, &delta, &k, &publicCommitted, &nbCommitments} {
+		if err := dec.Decode(v); err != nil {
+			return nil, fmt.Errorf("%w: verifying key field %d: %v", ErrMalformed, i, err)
+		}
+	}
+	// Envelopes carry the hash of the compressed encoding, whichever
+	// encoding the key was read from.
+	h := sha256.New()
+	enc := bn254.NewEncoder(h)
+	for _, v := range []any{&alpha, &betaG1, &beta, &gamma, &deltaG1, &delta, k, publicCommitted, nbCommitments} {
+		if err := enc.Encode(v); err != nil {
+			return nil, fmt.Errorf("%w: verifying key: %v", ErrMalformed, err)
+		}
+	}
+	if nbCommitments != 0 || len(publicCommitted) != 0 {
+		return nil, fmt.Errorf("%w: verifying key has %d Pedersen commitments", ErrUnsupported, nbCommitments)
+	}
+	if len(k) == 0 {
+		return nil, fmt.Errorf("%w: verifying key has no public wires", ErrMalformed)
+	}
+	vk := &VerifyingKey{alpha: alpha, k: k, nbPublic: len(k) - 1, hash: hex.EncodeToString(h.Sum(nil))}
+	vk.negGamma.Neg(&gamma)
+	vk.negDelta.Neg(&delta)
+	var err error
+	if vk.e, err = bn254.Pair([]bn254.G1Affine{alpha}, []bn254.G2Affine{beta}); err != nil {
+		return nil, fmt.Errorf("%w: verifying key: %v", ErrMalformed, err)
+	}
+	return vk, nil
+}
+
+// NbPublic returns the number of public inputs the key expects.
+func (vk *VerifyingKey) NbPublic() int {
+	return vk.nbPublic
+}
+
+// Hash returns the hex SHA-256 of the key's compressed encoding, the
+// vk_hash that envelopes carry.
+func (vk *VerifyingKey) Hash() string {
+	return vk.hash
+}
+
+// Proof is a Groth16 proof without commitments.
+type Proof struct {
+	ar, krs bn254.G1Affine
+	bs      bn254.G2Affine
+}
+
+// ParseProof decodes a proof as written by gnark's Proof.WriteTo, the
+// format of the proof field of an envelope. Points are checked to be on the
+// curve and in the right subgroup.
+func ParseProof(data []byte) (*Proof, error) {
+	// Ar (G1) | Bs (G2) | Krs (G1) | n (uint32) | n commitments (G1) | PoK (G1)
+	prefix := 2*bn254.SizeOfG1AffineCompressed + bn254.SizeOfG2AffineCompressed
+	if len(data) < prefix+4 {
+		return nil, fmt.Errorf("%w: proof is %d bytes, too short", ErrMalformed, len(data))
+	}
+	if n := binary.BigEndian.Uint32(data[prefix:]); n != 0 {
+		return nil, fmt.Errorf("%w: proof carries %d Pedersen commitments", ErrUnsupported, n)
+	}
+	if want := prefix + 4 + bn254.SizeOfG1AffineCompressed; len(data) != want {
+		return nil, fmt.Errorf("%w: proof is %d bytes, expected %d", ErrMalformed, len(data), want)
+	}
+	var p Proof
+	dec := bn254.NewDecoder(bytes.NewReader(data[:prefix]))
+	for _, v := range []any{&p.ar, &p.bs, &p.krs} {
+		if err := dec.Decode(v); err != nil {
+			return nil, fmt.Errorf("%w: proof: %v", ErrMalformed, err)
+		}
+	}
+	return &p, nil
+}
+
+// ParsePublicInputs decodes a public witness as written by gnark's
+// Witness.MarshalBinary, the format of the public_inputs field of an
+// envelope.
+func ParsePublicInputs(data []byte) ([]fr.Element, error) {
+	// nbPublic (uint32) | nbSecret (uint32) | n (uint32) | n field elements
+	if len(data) < 12 {
+		return nil, fmt.Errorf("%w: public inputs are %d bytes, too short", ErrMalformed, len(data))
+	}
+	nbPublic := binary.BigEndian.Uint32(data[0:])
+	nbSecret := binary.BigEndian.Uint32(data[4:])
+	n := binary.BigEndian.Uint32(data[8:])
+	if nbSecret != 0 || n != nbPublic {
+		return nil, fmt.Errorf("%w: public inputs header announces %d public, %d secret and %d values", ErrMalformed, nbPublic, nbSecret, n)
+	}
+	if want := 12 + uint64(n)*fr.Bytes; uint64(len(data)) != want {
+		return nil, fmt.Errorf("%w: public inputs are %d bytes, their header announces %d", ErrMalformed, len(data), want)
+	}
+	values := make([]fr.Element, n)
+	for i := range values {
+		if err := values[i].SetBytesCanonical(data[12+i*fr.Bytes : 12+(i+1)*fr.Bytes]); err != nil {
+			return nil, fmt.Errorf("%w: public input %d: %v", ErrMalformed, i, err)
+		}
+	}
+	return values, nil
+}
+
+// Verify checks proof against vk for the given public inputs:
+// e(Ar, Bs) = e(α, β) · e(K₀ + Σ xᵢKᵢ, γ) · e(Krs, δ).
+func (vk *VerifyingKey) Verify(proof *Proof, public []fr.Element) error {
+	if len(public) != vk.nbPublic {
+		return fmt.Errorf("%w: %d public inputs, the key expects %d", ErrVerificationFailed, len(public), vk.nbPublic)
+	}
+	var kSum bn254.G1Jac
+	if _, err := kSum.MultiExp(vk.k[1:], public, ecc.MultiExpConfig{}); err != nil {
+		return fmt.Errorf("%w: %v", ErrVerificationFailed, err)
+	}
+	kSum.AddMixed(&vk.k[0])
+	var kSumAff bn254.G1Affine
+	kSumAff.FromJacobian(&kSum)
+
+	// e(Krs, -δ) · e(Ar, Bs) · e(kSum, -γ) must equal e(α, β)
+	ml, err := bn254.MillerLoop(
+		[]bn254.G1Affine{proof.krs, proof.ar, kSumAff},
+		[]bn254.G2Affine{vk.negDelta, proof.bs, vk.negGamma},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerificationFailed, err)
+	}
+	if got := bn254.FinalExponentiation(&ml); !vk.e.Equal(&got) {
+		return fmt.Errorf("%w: pairing check failed", ErrVerificationFailed)
+	}
+	return nil
+}
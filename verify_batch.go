@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ananthanir/hello-zkp/circuit"
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// runVerifyBatch verifies every envelope in a directory in parallel.
+func runVerifyBatch(args []string) error {
+	fs := flag.NewFlagSet("verify-batch", flag.ExitOnError)
+	bits := fs.Int("bits", circuit.DefaultBits, "bit width of Age, Min and Max")
+	name := fs.String("circuit", "range", "circuit the proofs are for")
+	keys := fs.String("keys", "keys", "directory holding vk.bin")
+	dir := fs.String("proofs", "proofs", "directory of *.json proof envelopes")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	definition, err := circuit.New(*name, *bits)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrCompile, err)
+	}
+	vk, err := readVerifyingKey(*keys)
+	if err != nil {
+		return err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(*dir, "*.json"))
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	sort.Strings(paths)
+
+	// A file that cannot be read or parsed fails on its own; the others are
+	// still verified.
+	errs := make([]error, len(paths))
+	var (
+		envs     []*envelope.Envelope
+		readable []int
+	)
+	for i, path := range paths {
+		env, err := readEnvelope(path)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		envs = append(envs, env)
+		readable = append(readable, i)
+	}
+
+	start := time.Now()
+	verified, err := envelope.VerifyBatch(definition.ID(), vk, envs)
+	if err != nil {
+		return err
+	}
+	took := time.Since(start)
+	for j, i := range readable {
+		errs[i] = verified[j]
+	}
+
+	var failed int
+	outcomes := make([]batchOutcome, len(errs))
+	for i, err := range errs {
+		outcomes[i] = newBatchOutcome(0, paths[i], 0, err)
+		if err != nil {
+			failed++
+			report.printf("%s: ❌ %v\n", paths[i], err)
+			continue
+		}
+		report.printf("%s: ✅\n", paths[i])
+	}
+
+	report.set("circuit", definition.ID())
+	report.set("proofs", outcomes)
+	report.set("valid", len(paths)-failed)
+	report.set("invalid", failed)
+	report.set("wall_ns", took.Nanoseconds())
+	report.println("\n=== Summary ===")
+	report.printf("Proofs:   %d (%d valid, %d invalid)\n", len(paths), len(paths)-failed, failed)
+	report.printf("Wall:     %v\n", took.Round(time.Millisecond))
+	if failed > 0 {
+		return zkp.Wrap(zkp.ErrVerificationFailed, fmt.Errorf("%d of %d proofs did not verify", failed, len(paths)))
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/keyfile"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+const vkUsage = `usage: hello-zkp vk <step> [flags]
+
+steps:
+  fingerprint  print the SHA-256 fingerprint of a verifying key
+  publish      serve a verifying key and its fingerprint over HTTP(S)
+  fetch        download a verifying key, check its pinned fingerprint and save it
+`
+
+// maxVerifyingKeySize bounds a downloaded key. Verifying keys are a few
+// hundred bytes plus one point per public input.
+const maxVerifyingKeySize = 1 << 20
+
+// vkSteps maps each vk step to its implementation.
+var vkSteps = map[string]func(args []string) error{
+	"fingerprint": runVKFingerprint,
+	"publish":     runVKPublish,
+	"fetch":       runVKFetch,
+}
+
+// runVK dispatches to a step of verifying key distribution. A key is named
+// by its fingerprint, the SHA-256 of its compressed encoding that envelopes
+// carry as vk_hash: a verifier that hardcodes it can take the key from any
+// server and still detect a substituted one.
+func runVK(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, vkUsage)
+		os.Exit(exitUsage)
+	}
+	run, ok := vkSteps[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown vk step %q\n\n%s", args[0], vkUsage)
+		os.Exit(exitUsage)
+	}
+	return run(args[1:])
+}
+
+func runVKFingerprint(args []string) error {
+	fs := flag.NewFlagSet("vk fingerprint", flag.ExitOnError)
+	keys := fs.String("keys", "keys", "directory holding vk.bin")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	vk, err := readVerifyingKey(*keys)
+	if err != nil {
+		return err
+	}
+	fingerprint, err := envelope.HashVerifyingKey(vk)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	report.set("fingerprint", fingerprint)
+	report.set("curve", vk.CurveID().String())
+	report.printf("%s\n", fingerprint)
+	return nil
+}
+
+// runVKPublish serves GET /vk.bin, the key in its compressed encoding, and
+// GET /vk.bin.sha256, its fingerprint in sha256sum format, until
+// interrupted. The fingerprint served next to the key only guards against
+// corruption: verifiers must get the one they pin from elsewhere.
+func runVKPublish(args []string) error {
+	fs := flag.NewFlagSet("vk publish", flag.ExitOnError)
+	keys := fs.String("keys", "keys", "directory holding vk.bin")
+	addr := fs.String("addr", "localhost:8787", "address to listen on")
+	cert := fs.String("tls-cert", "", "TLS certificate file, to serve HTTPS (with -tls-key)")
+	key := fs.String("tls-key", "", "TLS private key file")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	if (*cert == "") != (*key == "") {
+		return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("-tls-cert and -tls-key go together"))
+	}
+	vk, err := readVerifyingKey(*keys)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	if _, err := vk.WriteTo(&body); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	fingerprint, err := envelope.HashVerifyingKey(vk)
+	if err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /"+verifyingKeyFile, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write(body.Bytes())
+	})
+	mux.HandleFunc("GET /"+verifyingKeyFile+keyfile.ChecksumSuffix, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "%s  %s\n", fingerprint, verifyingKeyFile)
+	})
+	server := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	scheme := "http"
+	if *cert != "" {
+		scheme = "https"
+	}
+	report.set("fingerprint", fingerprint)
+	report.set("url", fmt.Sprintf("%s://%s/%s", scheme, *addr, verifyingKeyFile))
+	report.printf("Serving %s://%s/%s (%d bytes)\n", scheme, *addr, verifyingKeyFile, body.Len())
+	report.printf("Fingerprint: %s\n", fingerprint)
+	report.println("Verifiers pin it with: vk fetch -fingerprint <it> or verify -vk-fingerprint <it>")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+	if *cert != "" {
+		err = server.ListenAndServeTLS(*cert, *key)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	return nil
+}
+
+// runVKFetch downloads a verifying key and writes it to -keys, once it
+// matches the pinned fingerprint. Without -fingerprint the key is trusted
+// on first use and its fingerprint printed to pin from then on.
+func runVKFetch(args []string) error {
+	fs := flag.NewFlagSet("vk fetch", flag.ExitOnError)
+	url := fs.String("url", "", "URL of the verifying key, e.g. https://example.org/vk.bin")
+	pin := fs.String("fingerprint", "", "SHA-256 fingerprint the key must have (empty: trust on first use)")
+	keys := fs.String("keys", "keys", "directory to write vk.bin to")
+	timeout := fs.Duration("timeout", 30*time.Second, "time allowed for the download")
+	addJSONFlag(fs)
+	parseFlags(fs, args)
+
+	if *url == "" {
+		return zkp.Wrap(zkp.ErrInvalidWitness, errors.New("-url is required"))
+	}
+	// The pin is checked on the bytes as downloaded: nothing decodes a key
+	// from an untrusted server before it is known to be the pinned one.
+	data, err := downloadVerifyingKey(*url, *timeout)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	fingerprint := hex.EncodeToString(sum[:])
+	report.set("fingerprint", fingerprint)
+	report.set("pinned", *pin != "")
+	if *pin != "" {
+		if err := fingerprintError(envelope.CheckEncodedFingerprint(data, *pin)); err != nil {
+			report.printf("Fingerprint: ❌ %v\n", err)
+			return err
+		}
+	}
+	vk, err := decodeVerifyingKey(*url, data)
+	if err != nil {
+		return err
+	}
+	if *pin != "" {
+		report.printf("Fingerprint: ✅ %s matches the pin\n", fingerprint)
+	} else {
+		report.printf("Fingerprint: ⚠️ %s, unpinned (trusted on first use)\n", fingerprint)
+	}
+
+	if err := os.MkdirAll(*keys, 0o755); err != nil {
+		return zkp.Wrap(zkp.ErrIO, err)
+	}
+	path := filepath.Join(*keys, verifyingKeyFile)
+	if _, err := keyfile.Write(path, vk, keyfile.Compressed); err != nil {
+		return err
+	}
+	report.set("out", path)
+	report.printf("Wrote %s key to %s\n", vk.CurveID(), path)
+	return nil
+}
+
+// downloadVerifyingKey downloads the encoding of a verifying key, without
+// decoding it.
+func downloadVerifyingKey(url string, timeout time.Duration) ([]byte, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, zkp.Wrap(zkp.ErrIO, fmt.Errorf("GET %s: %s", url, resp.Status))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxVerifyingKeySize+1))
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, err)
+	}
+	if len(data) > maxVerifyingKeySize {
+		return nil, zkp.Wrap(zkp.ErrIO, fmt.Errorf("GET %s: more than %d bytes, not a verifying key", url, maxVerifyingKeySize))
+	}
+	return data, nil
+}
+
+// decodeVerifyingKey decodes a downloaded verifying key on the curve in
+// use. The key must take up all of data, so that the fingerprint of data is
+// the fingerprint of the key.
+func decodeVerifyingKey(url string, data []byte) (groth16.VerifyingKey, error) {
+	vk := groth16.NewVerifyingKey(curve)
+	n, err := vk.ReadFrom(bytes.NewReader(data))
+	if err != nil {
+		return nil, zkp.Wrap(zkp.ErrIO, fmt.Errorf("GET %s: not a %s verifying key: %w", url, curve, err))
+	}
+	if n != int64(len(data)) {
+		return nil, zkp.Wrap(zkp.ErrIO, fmt.Errorf("GET %s: %d bytes after the %s verifying key", url, int64(len(data))-n, curve))
+	}
+	return vk, nil
+}
+
+// checkFingerprint checks a key against a pinned fingerprint. A different
+// key is a failed verification, as a proof checked against it would be
+// meaningless; a malformed fingerprint is invalid input.
+func checkFingerprint(vk groth16.VerifyingKey, pin string) error {
+	return fingerprintError(envelope.CheckFingerprint(vk, pin))
+}
+
+// fingerprintError wraps an error from envelope.CheckFingerprint or
+// CheckEncodedFingerprint with the sentinel for its exit status.
+func fingerprintError(err error) error {
+	switch {
+	case errors.Is(err, envelope.ErrVKMismatch):
+		return zkp.Wrap(zkp.ErrVerificationFailed, err)
+	case err != nil:
+		return zkp.Wrap(zkp.ErrInvalidWitness, err)
+	}
+	return nil
+}
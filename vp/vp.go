@@ -0,0 +1,137 @@
+// Package vp wraps proof envelopes in W3C Verifiable Presentations, so an age
+// proof can travel through digital-identity wallets and verifier libraries
+// that speak the Verifiable Credentials data model.
+//
+// The presentation carries no credential: the zero-knowledge proof is the
+// presentation's proof, under the custom type ProofType. Its fields are the
+// envelope's, and the verifying key is referenced by hash through
+// verificationMethod. Terms outside the VC v2 context are defined by an
+// inline @vocab, so the document remains valid JSON-LD.
+package vp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ananthanir/hello-zkp/envelope"
+)
+
+const (
+	// CredentialsContext is the W3C Verifiable Credentials v2 context.
+	CredentialsContext = "https://www.w3.org/ns/credentials/v2"
+
+	// Vocabulary is the namespace of the terms defined by this package.
+	Vocabulary = "https://github.com/ananthanir/hello-zkp#"
+
+	// PresentationType is the type every presentation has.
+	PresentationType = "VerifiablePresentation"
+
+	// ProofType is the type of the embedded Groth16 proof.
+	ProofType = "HelloZkpGroth16Proof"
+
+	// vkPrefix starts the verificationMethod naming a verifying key by its
+	// hex SHA-256 (see envelope.HashVerifyingKey).
+	vkPrefix = "urn:hello-zkp:vk:sha256:"
+)
+
+// Presentation is a Verifiable Presentation whose proof is a hello-zkp proof.
+type Presentation struct {
+	Context []any    `json:"@context"`
+	Type    []string `json:"type"`
+	Proof   Proof    `json:"proof"`
+}
+
+// Proof is the embedded proof. Binary fields are base64 encoded in JSON, as
+// in the envelope.
+type Proof struct {
+	Type               string `json:"type"`
+	Created            string `json:"created"`
+	ProofPurpose       string `json:"proofPurpose"`
+	VerificationMethod string `json:"verificationMethod"`
+	EnvelopeVersion    int    `json:"envelopeVersion"`
+	Curve              string `json:"curve"`
+	Circuit            string `json:"circuit"`
+	ProofValue         []byte `json:"proofValue"`
+	PublicInputs       []byte `json:"publicInputs"`
+}
+
+// New wraps an envelope in a presentation created at the given time.
+func New(env *envelope.Envelope, created time.Time) *Presentation {
+	return &Presentation{
+		Context: []any{CredentialsContext, map[string]string{"@vocab": Vocabulary}},
+		Type:    []string{PresentationType},
+		Proof: Proof{
+			Type:               ProofType,
+			Created:            created.UTC().Format(time.RFC3339),
+			ProofPurpose:       "authentication",
+			VerificationMethod: vkPrefix + env.VKHash,
+			EnvelopeVersion:    env.Version,
+			Curve:              env.Curve,
+			Circuit:            env.Circuit,
+			ProofValue:         env.Proof,
+			PublicInputs:       env.PublicInputs,
+		},
+	}
+}
+
+// Envelope unwraps the proof envelope, which is then verified as usual. It
+// fails with envelope.ErrMalformed if p is not a presentation this package
+// produced.
+func (p *Presentation) Envelope() (*envelope.Envelope, error) {
+	switch {
+	case len(p.Context) == 0 || p.Context[0] != CredentialsContext:
+		return nil, malformed("missing %s context", CredentialsContext)
+	case !slices.Contains(p.Type, PresentationType):
+		return nil, malformed("type is not %s", PresentationType)
+	case p.Proof.Type != ProofType:
+		return nil, malformed("proof type %q, want %s", p.Proof.Type, ProofType)
+	}
+	vkHash, ok := strings.CutPrefix(p.Proof.VerificationMethod, vkPrefix)
+	if !ok {
+		return nil, malformed("unsupported verificationMethod %q", p.Proof.VerificationMethod)
+	}
+	return &envelope.Envelope{
+		Version:      p.Proof.EnvelopeVersion,
+		Curve:        p.Proof.Curve,
+		Circuit:      p.Proof.Circuit,
+		VKHash:       vkHash,
+		Proof:        p.Proof.ProofValue,
+		PublicInputs: p.Proof.PublicInputs,
+	}, nil
+}
+
+// WriteTo writes the presentation as indented JSON.
+func (p *Presentation) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// IsPresentation reports whether a JSON document looks like a presentation
+// rather than a bare envelope, i.e. has a JSON-LD @context.
+func IsPresentation(data []byte) bool {
+	var probe struct {
+		Context json.RawMessage `json:"@context"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Context != nil
+}
+
+// Read decodes a JSON presentation.
+func Read(r io.Reader) (*Presentation, error) {
+	var p Presentation
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, fmt.Errorf("%w: %v", envelope.ErrMalformed, err)
+	}
+	return &p, nil
+}
+
+func malformed(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", envelope.ErrMalformed, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,78 @@
+//go:build js && wasm
+
+// Command wasm exposes the hello-zkp verifier to JavaScript, so a browser can
+// check a proof client-side without a server round-trip.
+//
+// Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o verifier.wasm ./wasm
+//
+// and load it next to Go's wasm_exec.js. Once running it defines
+//
+//	verifyAgeProof(proofB64, vkB64, publicInputsB64) -> {ok: bool, error: string}
+//
+// where the arguments are the base64 encodings produced by the envelope
+// package (the same bytes as the proof and public_inputs fields of a
+// proof.json, and of keys/vk.bin).
+package main
+
+import (
+	"fmt"
+	"syscall/js"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/rs/zerolog"
+
+	"github.com/ananthanir/hello-zkp/envelope"
+	"github.com/ananthanir/hello-zkp/zkp"
+)
+
+// curve is the curve the CLI proves on.
+var curve = ecc.BN254
+
+func main() {
+	// Disable gnark debug logs
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	js.Global().Set("verifyAgeProof", js.FuncOf(verifyAgeProof))
+
+	// Keep the Go runtime alive so the exported function stays callable.
+	select {}
+}
+
+// verifyAgeProof is the JavaScript entry point. It never throws: failures are
+// reported in the error field of the result.
+func verifyAgeProof(this js.Value, args []js.Value) any {
+	if len(args) != 3 {
+		return result(fmt.Errorf("expected 3 arguments (proof, verifying key, public inputs), got %d", len(args)))
+	}
+	for i, arg := range args {
+		if arg.Type() != js.TypeString {
+			return result(fmt.Errorf("argument %d must be a base64 string, got %s", i+1, arg.Type()))
+		}
+	}
+	return result(verify(args[0].String(), args[1].String(), args[2].String()))
+}
+
+func verify(proofB64, vkB64, publicInputsB64 string) error {
+	vk, err := envelope.DecodeVerifyingKey(curve, vkB64)
+	if err != nil {
+		return err
+	}
+	proof, err := envelope.DecodeProof(curve, proofB64)
+	if err != nil {
+		return err
+	}
+	publicWitness, err := envelope.DecodePublicWitness(curve, publicInputsB64)
+	if err != nil {
+		return err
+	}
+	return zkp.Verify(proof, vk, publicWitness)
+}
+
+func result(err error) any {
+	if err != nil {
+		return map[string]any{"ok": false, "error": err.Error()}
+	}
+	return map[string]any{"ok": true, "error": ""}
+}
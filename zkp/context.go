@@ -0,0 +1,53 @@
+package zkp
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// abandoned counts the calls of Run whose f is still running after Run
+// returned early.
+var abandoned atomic.Int64
+
+// Run calls f and waits for it to return or for ctx to be done, whichever
+// comes first. If ctx is done first, Run returns ctx.Err() (context.Canceled
+// or context.DeadlineExceeded) as is, without one of the sentinel errors.
+//
+// gnark has no way to interrupt a compile, setup or proof, so f keeps running
+// in the background until it finishes, holding its CPU and memory; its result
+// is then discarded. Abandoned reports how many such calls are still running,
+// so that callers fed by untrusted peers can stop taking on new work.
+func Run[T any](ctx context.Context, f func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	var left atomic.Bool
+	go func() {
+		v, err := f()
+		if !left.CompareAndSwap(false, true) {
+			abandoned.Add(-1)
+		}
+		done <- result{v, err}
+	}()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		if left.CompareAndSwap(false, true) {
+			abandoned.Add(1)
+		}
+		return zero, ctx.Err()
+	}
+}
+
+// Abandoned returns the number of calls of f that Run gave up waiting for
+// and that have not finished yet.
+func Abandoned() int64 {
+	return abandoned.Load()
+}
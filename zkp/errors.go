@@ -0,0 +1,47 @@
+// Package zkp holds the error taxonomy shared by the proving and verifying
+// sides, and verification itself.
+//
+// It deliberately depends on nothing but the Groth16 backend, so verifiers can
+// use it without pulling in the circuit compiler.
+package zkp
+
+import "errors"
+
+// Sentinel errors identifying the stage that failed. Every error returned by
+// this package and by prover wraps exactly one of them, except the context
+// errors of a cancelled call (see Run); test with errors.Is.
+var (
+	ErrCompile            = errors.New("compile failed")
+	ErrSetup              = errors.New("setup failed")
+	ErrInvalidWitness     = errors.New("invalid witness")
+	ErrVerificationFailed = errors.New("verification failed")
+	ErrIO                 = errors.New("i/o failure")
+)
+
+// Error is a failure in one stage of the flow. Kind is one of the sentinel
+// errors above and Err the underlying cause; errors.Is matches both, and
+// errors.As recovers the *Error itself.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Wrap returns err as an *Error of the given kind. It returns nil for a nil
+// err, and leaves err alone if it already is of that kind.
+func Wrap(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, kind) {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
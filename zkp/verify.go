@@ -0,0 +1,35 @@
+package zkp
+
+import (
+	"context"
+	"time"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+
+	"github.com/ananthanir/hello-zkp/logging"
+	"github.com/ananthanir/hello-zkp/tracing"
+)
+
+// Verify checks a Groth16 proof against a verifying key and public witness.
+func Verify(proof groth16.Proof, vk groth16.VerifyingKey, publicWitness witness.Witness) error {
+	return verify(context.Background(), proof, vk, publicWitness)
+}
+
+func verify(ctx context.Context, proof groth16.Proof, vk groth16.VerifyingKey, publicWitness witness.Witness) error {
+	start := time.Now()
+	_, span := tracing.Start(ctx, "verify", "curve", vk.CurveID().String())
+	err := groth16.Verify(proof, vk, publicWitness)
+	span.SetAttributes("ok", err == nil)
+	span.End(err)
+	logging.Done("verify_done", start, "curve", vk.CurveID().String(), "ok", err == nil)
+	return Wrap(ErrVerificationFailed, err)
+}
+
+// VerifyContext is Verify, returning early if ctx is done first.
+func VerifyContext(ctx context.Context, proof groth16.Proof, vk groth16.VerifyingKey, publicWitness witness.Witness) error {
+	_, err := Run(ctx, func() (struct{}, error) {
+		return struct{}{}, verify(ctx, proof, vk, publicWitness)
+	})
+	return err
+}